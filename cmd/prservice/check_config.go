@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/mink0ff/pr_service/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// runCheckConfig loads the configuration the way every other command does,
+// prints it with secrets redacted and fails if anything is invalid. The
+// output is a valid config file. It doesn't connect to the database.
+func runCheckConfig(args []string) error {
+	fs := flag.NewFlagSet("check-config", flag.ExitOnError)
+	cfgOpts := addConfigFlags(fs)
+	_ = fs.Parse(args)
+
+	cfg, err := cfgOpts.read()
+	if err != nil {
+		return fmt.Errorf("invalid configuration:\n%w", err)
+	}
+
+	if err := printYAML(cfg.Redacted()); err != nil {
+		return err
+	}
+
+	if err := validateConfig(cfg); err != nil {
+		return fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return nil
+}
+
+func printYAML(v *config.Config) error {
+	enc := yaml.NewEncoder(os.Stdout)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	return enc.Close()
+}
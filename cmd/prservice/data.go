@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mink0ff/pr_service/internal/export"
+	"github.com/mink0ff/pr_service/internal/primport"
+	"github.com/mink0ff/pr_service/internal/repository"
+	"github.com/mink0ff/pr_service/internal/repository/transaction"
+	"github.com/mink0ff/pr_service/internal/service"
+)
+
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	cfgOpts := addConfigFlags(fs)
+	format := fs.String("format", "", "input format: ndjson or csv (default: by file extension)")
+	dryRun := fs.Bool("dry-run", false, "validate the file without importing it")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: prservice import [flags] <file|->")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	path := fs.Arg(0)
+	input, closeInput, err := openInput(path)
+	if err != nil {
+		return err
+	}
+	defer closeInput()
+
+	if *format == "" {
+		*format = primport.FormatNDJSON
+		if strings.EqualFold(filepath.Ext(path), ".csv") {
+			*format = primport.FormatCSV
+		}
+	}
+
+	cfg, err := cfgOpts.load()
+	if err != nil {
+		return err
+	}
+	db, err := openDB(&cfg.DB, true)
+	if err != nil {
+		return err
+	}
+
+	importService := service.NewImportService(
+		repository.NewPrRepo(db),
+		repository.NewUserRepo(db),
+		repository.NewReviewerHistoryRepo(db),
+		transaction.NewTransactionManager(db),
+	)
+
+	report, err := importService.ImportPullRequests(context.Background(), *format, input, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	if err := printJSON(report); err != nil {
+		return err
+	}
+
+	if len(report.Errors) > 0 {
+		return fmt.Errorf("%d of %d rows are invalid, nothing was imported", len(report.Errors), report.Total)
+	}
+	return nil
+}
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	cfgOpts := addConfigFlags(fs)
+	format := fs.String("format", export.FormatSnapshot, "output format: ndjson, csv or snapshot")
+	entity := fs.String("entity", "", "entity to export with ndjson or csv: "+strings.Join(export.Entities, ", "))
+	output := fs.String("o", "-", "output file, - for stdout")
+	_ = fs.Parse(args)
+
+	cfg, err := cfgOpts.load()
+	if err != nil {
+		return err
+	}
+	db, err := openDB(&cfg.DB, true)
+	if err != nil {
+		return err
+	}
+	exportService := service.NewExportService(repository.NewSnapshotRepo(db), transaction.NewTransactionManager(db))
+
+	out := os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if *format == export.FormatSnapshot {
+		manifest, err := exportService.Snapshot(context.Background(), out)
+		if err != nil {
+			return err
+		}
+		log.Printf("Snapshot at schema version %d: %v", manifest.SchemaVersion, manifest.Counts)
+		return nil
+	}
+
+	return exportService.Export(context.Background(), *entity, *format, out)
+}
+
+func runRestore(args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	cfgOpts := addConfigFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: prservice restore [flags] <snapshot.tar.gz|->")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	input, closeInput, err := openInput(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer closeInput()
+
+	cfg, err := cfgOpts.load()
+	if err != nil {
+		return err
+	}
+	db, err := openDB(&cfg.DB, true)
+	if err != nil {
+		return err
+	}
+	exportService := service.NewExportService(repository.NewSnapshotRepo(db), transaction.NewTransactionManager(db))
+
+	report, err := exportService.Restore(context.Background(), input)
+	if err != nil {
+		return err
+	}
+
+	return printJSON(report)
+}
+
+// openInput opens path for reading, "-" meaning stdin.
+func openInput(path string) (io.Reader, func(), error) {
+	if path == "-" {
+		return os.Stdin, func() {}, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, func() { _ = f.Close() }, nil
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
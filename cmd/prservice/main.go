@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/mink0ff/pr_service/internal/config"
+	"github.com/mink0ff/pr_service/internal/jobs"
+	"github.com/mink0ff/pr_service/internal/repository/gormdb"
+	"github.com/mink0ff/pr_service/internal/repository/migrate"
+	"github.com/mink0ff/pr_service/internal/service"
+	"gorm.io/gorm"
+)
+
+const usage = `Usage: prservice <command> [flags]
+
+Commands:
+  serve          run the HTTP server and background jobs
+  migrate        apply, roll back or inspect database migrations (up|down|status|force)
+  seed           load demo teams and pull requests
+  token          create or revoke admin API tokens (create|revoke)
+  import         import historical pull requests from an NDJSON or CSV file
+  export         export an entity as NDJSON/CSV, or everything as a snapshot archive
+  restore        load a snapshot archive into an empty database
+  check-config   validate the configuration and print it with secrets redacted
+
+Run "prservice <command> -h" for the flags of a command. Settings come from
+the defaults, then the -config file, then the environment (including the
+env file), then the flags, each overriding the previous.
+`
+
+var commands = map[string]func(args []string) error{
+	"serve":        runServe,
+	"migrate":      runMigrate,
+	"seed":         runSeed,
+	"token":        runToken,
+	"import":       runImport,
+	"export":       runExport,
+	"restore":      runRestore,
+	"check-config": runCheckConfig,
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	name := os.Args[1]
+	if name == "-h" || name == "--help" || name == "help" {
+		fmt.Print(usage)
+		return
+	}
+
+	run, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", name, usage)
+		os.Exit(2)
+	}
+
+	// Not log.Fatalf: with LOG_LEVEL above info the message would be dropped.
+	if err := run(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		os.Exit(1)
+	}
+}
+
+// configOptions are the flags shared by every command that loads the
+// configuration.
+type configOptions struct {
+	file          string
+	envFile       string
+	dsn           string
+	migrationPath string
+}
+
+func addConfigFlags(fs *flag.FlagSet) *configOptions {
+	opts := &configOptions{}
+	fs.StringVar(&opts.file, "config", "", "YAML or JSON config file")
+	fs.StringVar(&opts.envFile, "env-file", ".env", "file with environment variables")
+	fs.StringVar(&opts.dsn, "dsn", "", "database DSN (overrides DB_DSN)")
+	fs.StringVar(&opts.migrationPath, "migrations", "", "migrations directory (overrides DB_MIGRATION_PATH)")
+	return opts
+}
+
+// read builds the configuration and applies the flags on top, the shared
+// ones first and then the command's own overrides.
+func (o *configOptions) read(overrides ...func(*config.Config)) (*config.Config, error) {
+	cfg, err := config.Load(o.file, o.envFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.dsn != "" {
+		cfg.DB.DSN = o.dsn
+	}
+	if o.migrationPath != "" {
+		cfg.DB.MigrationPath = o.migrationPath
+	}
+	for _, override := range overrides {
+		override(cfg)
+	}
+
+	return cfg, nil
+}
+
+// load is read followed by validation, so a command never starts with a bad
+// configuration. It also sets up logging.
+func (o *configOptions) load(overrides ...func(*config.Config)) (*config.Config, error) {
+	cfg, err := o.read(overrides...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+
+	if err := validateConfig(cfg); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+
+	setupLogging(&cfg.Log)
+	return cfg, nil
+}
+
+// validateConfig adds the checks that belong to other packages to
+// Config.Validate.
+func validateConfig(cfg *config.Config) error {
+	problems := []error{cfg.Validate()}
+
+	if _, err := service.NewSeedFunc(cfg.Selection.Mode, cfg.Selection.Seed); err != nil {
+		problems = append(problems, fmt.Errorf("selection.mode (REVIEWER_SELECTION_MODE): %w", err))
+	}
+
+	schedules := []struct {
+		key     string
+		enabled bool
+		spec    string
+	}{
+		{"notify.digest_schedule (NOTIFY_DIGEST_SCHEDULE)", cfg.Notify.Enabled, cfg.Notify.DigestSchedule},
+		{"smtp.digest_schedule (EMAIL_DIGEST_SCHEDULE)", cfg.SMTP.Enabled, cfg.SMTP.DigestSchedule},
+		{"escalation.schedule (ESCALATION_SCHEDULE)", cfg.Escalation.Enabled, cfg.Escalation.Schedule},
+		{"retention.schedule (RETENTION_SCHEDULE)", cfg.Retention.Enabled, cfg.Retention.Schedule},
+	}
+	for _, s := range schedules {
+		if _, err := jobs.ParseSchedule(s.spec); s.enabled && err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", s.key, err))
+		}
+	}
+
+	return errors.Join(problems...)
+}
+
+// setupLogging routes the standard logger through slog in the configured
+// format. log.Printf lines reporting a failure are written at error level and
+// the rest at info, so LOG_LEVEL=warn keeps the failures.
+func setupLogging(cfg *config.LogConfig) {
+	var level slog.Level
+	_ = level.UnmarshalText([]byte(cfg.Level))
+
+	opts := &slog.HandlerOptions{Level: level}
+	var h slog.Handler = slog.NewTextHandler(os.Stderr, opts)
+	if cfg.Format == "json" {
+		h = slog.NewJSONHandler(os.Stderr, opts)
+	}
+	logger := slog.New(h)
+	slog.SetDefault(logger)
+
+	// Set after slog.SetDefault, which points the standard logger at its
+	// own info-level bridge.
+	log.SetFlags(0)
+	log.SetOutput(&stdLogWriter{logger: logger})
+}
+
+// stdLogWriter forwards the standard logger to slog. Services and
+// repositories log with log.Printf, so the level is read from the message.
+type stdLogWriter struct {
+	logger *slog.Logger
+}
+
+func (w *stdLogWriter) Write(p []byte) (int, error) {
+	msg := strings.TrimSpace(string(p))
+	w.logger.Log(context.Background(), stdLogLevel(msg), msg)
+	return len(p), nil
+}
+
+func stdLogLevel(msg string) slog.Level {
+	lower := strings.ToLower(msg)
+	switch {
+	case strings.HasPrefix(lower, "failed"), strings.HasPrefix(lower, "error"), strings.Contains(lower, " failed"):
+		return slog.LevelError
+	case strings.Contains(lower, " did not "):
+		return slog.LevelWarn
+	default:
+		return slog.LevelInfo
+	}
+}
+
+func openDB(cfg *config.DBConfig, migrateUp bool) (*gorm.DB, error) {
+	db, err := gormdb.NewGormDB(&gormdb.GormConfig{
+		DSN:             cfg.DSN,
+		MaxOpenConns:    cfg.MaxOpenConns,
+		MaxIdleConns:    cfg.MaxIdleConns,
+		ConnMaxLifetime: cfg.ConnMaxLife,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to DB: %w", err)
+	}
+
+	if migrateUp {
+		if err := migrate.RunMigrations(db, cfg.MigrationPath); err != nil {
+			return nil, fmt.Errorf("failed to run migrations: %w", err)
+		}
+	}
+
+	return db, nil
+}
+
+// isFlagSet reports whether the flag was given on the command line, which
+// lets boolean flags override the environment in both directions.
+func isFlagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
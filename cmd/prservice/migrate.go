@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/mink0ff/pr_service/internal/repository/migrate"
+)
+
+func runMigrate(args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	cfgOpts := addConfigFlags(fs)
+	steps := fs.Int("steps", 1, "number of migrations to roll back with down")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: prservice migrate [flags] up|down|status|force <version>")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := cfgOpts.load()
+	if err != nil {
+		return err
+	}
+	db, err := openDB(&cfg.DB, false)
+	if err != nil {
+		return err
+	}
+
+	switch action := fs.Arg(0); action {
+	case "up":
+		return migrate.RunMigrations(db, cfg.DB.MigrationPath)
+	case "down":
+		return migrate.Down(db, cfg.DB.MigrationPath, *steps)
+	case "status":
+		status, err := migrate.GetStatus(db, cfg.DB.MigrationPath)
+		if err != nil {
+			return err
+		}
+		return printJSON(status)
+	case "force":
+		if fs.NArg() != 2 {
+			return fmt.Errorf("force needs a version")
+		}
+		version, err := strconv.Atoi(fs.Arg(1))
+		if err != nil {
+			return fmt.Errorf("invalid version %q: %w", fs.Arg(1), err)
+		}
+		return migrate.Force(db, cfg.DB.MigrationPath, version)
+	default:
+		return fmt.Errorf("unknown migrate action %q, expected up, down, status or force", action)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/notifier"
+	"github.com/mink0ff/pr_service/internal/repository"
+	"github.com/mink0ff/pr_service/internal/repository/transaction"
+	"github.com/mink0ff/pr_service/internal/service"
+)
+
+//go:embed seed.json
+var defaultSeed []byte
+
+type seedData struct {
+	Teams        []dto.Team            `json:"teams"`
+	PullRequests []dto.CreatePRRequest `json:"pull_requests"`
+	Merged       []string              `json:"merged"`
+}
+
+// runSeed goes through the services, so seeded pull requests get reviewers
+// exactly as real ones would. Teams and pull requests that already exist are
+// skipped, which makes the command safe to run repeatedly.
+func runSeed(args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ExitOnError)
+	cfgOpts := addConfigFlags(fs)
+	file := fs.String("file", "", "JSON file with teams, pull_requests and merged; built-in demo data by default")
+	_ = fs.Parse(args)
+
+	raw := defaultSeed
+	if *file != "" {
+		var err error
+		if raw, err = os.ReadFile(*file); err != nil {
+			return err
+		}
+	}
+
+	var data seedData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return err
+	}
+
+	cfg, err := cfgOpts.load()
+	if err != nil {
+		return err
+	}
+	db, err := openDB(&cfg.DB, true)
+	if err != nil {
+		return err
+	}
+
+	userRepo := repository.NewUserRepo(db)
+	teamRepo := repository.NewTeamRepo(db)
+	prRepo := repository.NewPrRepo(db)
+	teamSettingsRepo := repository.NewTeamSettingsRepo(db)
+	ownershipRepo := repository.NewOwnershipRuleRepo(db)
+	txManager := transaction.NewTransactionManager(db)
+
+	prService := service.NewPRService(prRepo, userRepo, teamRepo, repository.NewReviewerHistoryRepo(db), ownershipRepo,
+		repository.NewTagRepo(db), teamSettingsRepo, txManager, notifier.NewNoopNotifier(), service.HashSeeds())
+	teamService := service.NewTeamService(teamRepo, userRepo, prRepo, teamSettingsRepo, ownershipRepo,
+		repository.NewTeamAuditRepo(db), prService, txManager)
+
+	ctx := context.Background()
+	var created, skipped int
+
+	for _, team := range data.Teams {
+		_, err := teamService.CreateTeam(ctx, &team)
+		if errors.Is(err, service.ErrTeamExists) {
+			skipped++
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		created++
+	}
+
+	for _, pr := range data.PullRequests {
+		_, err := prService.CreatePR(ctx, &pr)
+		if errors.Is(err, service.ErrPRExists) {
+			skipped++
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		created++
+	}
+
+	for _, prID := range data.Merged {
+		if _, err := prService.MergePR(ctx, &dto.MergePRRequest{PullRequestID: prID}); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("Seed finished: %d created, %d already existed", created, skipped)
+	return nil
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mink0ff/pr_service/internal/config"
+	"github.com/mink0ff/pr_service/internal/handler"
+	"github.com/mink0ff/pr_service/internal/health"
+	"github.com/mink0ff/pr_service/internal/jobs"
+	"github.com/mink0ff/pr_service/internal/mailer"
+	"github.com/mink0ff/pr_service/internal/notifier"
+	"github.com/mink0ff/pr_service/internal/repository"
+	"github.com/mink0ff/pr_service/internal/repository/migrate"
+	"github.com/mink0ff/pr_service/internal/repository/transaction"
+	"github.com/mink0ff/pr_service/internal/service"
+	"gorm.io/gorm"
+)
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	cfgOpts := addConfigFlags(fs)
+	addr := fs.String("addr", "", "address to listen on (overrides PORT)")
+	migrateUp := fs.Bool("migrate", true, "apply pending migrations before starting")
+	adminAuth := fs.Bool("admin-auth", true, "serve /admin/* behind API tokens, false disables them (overrides ADMIN_AUTH_ENABLED)")
+	_ = fs.Parse(args)
+
+	cfg, err := cfgOpts.load(func(cfg *config.Config) {
+		if *addr != "" {
+			cfg.HTTP.Addr = *addr
+		}
+		if isFlagSet(fs, "admin-auth") {
+			cfg.Auth.AdminTokenRequired = *adminAuth
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	db, err := openDB(&cfg.DB, *migrateUp)
+	if err != nil {
+		return err
+	}
+
+	schemaVersion, err := migrate.LatestVersion(cfg.DB.MigrationPath)
+	if err != nil {
+		return fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	chatNotifier := notifier.NewNoopNotifier()
+	if cfg.Notify.Enabled {
+		slack, err := notifier.NewSlackNotifier(&cfg.Notify)
+		if err != nil {
+			return fmt.Errorf("failed to create chat notifier: %w", err)
+		}
+		chatNotifier = slack
+	}
+
+	seeds, err := service.NewSeedFunc(cfg.Selection.Mode, cfg.Selection.Seed)
+	if err != nil {
+		return fmt.Errorf("invalid reviewer selection config: %w", err)
+	}
+
+	userRepo := repository.NewUserRepo(db)
+	teamRepo := repository.NewTeamRepo(db)
+	prRepo := repository.NewPrRepo(db)
+	reviewerHistoryPero := repository.NewReviewerHistoryRepo(db)
+	settingsRepo := repository.NewNotificationSettingsRepo(db)
+	teamSettingsRepo := repository.NewTeamSettingsRepo(db)
+	jobRepo := repository.NewJobRepo(db)
+	ownershipRepo := repository.NewOwnershipRuleRepo(db)
+	tagRepo := repository.NewTagRepo(db)
+	snapshotRepo := repository.NewSnapshotRepo(db)
+	tokenRepo := repository.NewTokenRepo(db)
+	auditRepo := repository.NewTeamAuditRepo(db)
+
+	txManager := transaction.NewTransactionManager(db)
+
+	userService := service.NewUserService(userRepo, teamRepo, settingsRepo, tagRepo)
+	prService := service.NewPRService(prRepo, userRepo, teamRepo, reviewerHistoryPero, ownershipRepo, tagRepo, teamSettingsRepo, txManager, chatNotifier, seeds)
+	teamService := service.NewTeamService(teamRepo, userRepo, prRepo, teamSettingsRepo, ownershipRepo, auditRepo, prService, txManager)
+	statsService := service.NewStatsService(reviewerHistoryPero, teamRepo)
+	notificationService := service.NewNotificationService(teamRepo, userRepo, settingsRepo, chatNotifier, mailer.NewSMTPMailer(&cfg.SMTP))
+	escalationService := service.NewEscalationService(prRepo, userRepo, teamRepo, reviewerHistoryPero, prService, chatNotifier)
+	importService := service.NewImportService(prRepo, userRepo, reviewerHistoryPero, txManager)
+	exportService := service.NewExportService(snapshotRepo, txManager)
+	tokenService := service.NewTokenService(tokenRepo)
+	retentionService := service.NewRetentionService(repository.NewRetentionRepo(db), teamRepo, userRepo, auditRepo, txManager, cfg.Retention.Period, cfg.Retention.Mode)
+
+	runner := jobs.NewRunner(db, jobRepo)
+	if !cfg.Jobs.Enabled {
+		log.Printf("Background jobs are disabled on this instance")
+	}
+	if cfg.Jobs.Enabled && cfg.Notify.Enabled {
+		if err := runner.Register("review-digest", cfg.Notify.DigestSchedule, notificationService.SendReviewDigests); err != nil {
+			return fmt.Errorf("failed to register job: %w", err)
+		}
+	}
+	if cfg.Jobs.Enabled && cfg.SMTP.Enabled {
+		if err := runner.Register("email-digest", cfg.SMTP.DigestSchedule, notificationService.SendEmailDigests); err != nil {
+			return fmt.Errorf("failed to register job: %w", err)
+		}
+	}
+	if cfg.Jobs.Enabled && cfg.Escalation.Enabled {
+		err := runner.Register("review-escalation", cfg.Escalation.Schedule, func(ctx context.Context) error {
+			_, err := escalationService.EscalateStaleReviews(ctx)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to register job: %w", err)
+		}
+	}
+	if cfg.Jobs.Enabled && cfg.Retention.Enabled {
+		err := runner.Register("retention", cfg.Retention.Schedule, func(ctx context.Context) error {
+			_, err := retentionService.PurgeExpired(ctx)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to register job: %w", err)
+		}
+	}
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	if err := runner.Start(jobsCtx); err != nil {
+		return fmt.Errorf("failed to start job runner: %w", err)
+	}
+
+	var adminMiddleware func(http.Handler) http.Handler
+	if cfg.Auth.AdminTokenRequired {
+		adminMiddleware = handler.RequireToken(tokenService)
+	} else {
+		log.Printf("Admin endpoints are disabled, set ADMIN_AUTH_ENABLED=true to serve them behind API tokens")
+	}
+
+	checker := health.NewChecker(cfg.Health.CheckTimeout, cfg.Health.CacheTTL)
+	checker.Add("database", health.DatabaseCheck(db))
+	checker.Add("migrations", health.MigrationsCheck(db, schemaVersion))
+	checker.Add("jobs", runner.Check)
+
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r, teamService, userService, prService, statsService, runner, importService, exportService, retentionService, adminMiddleware, checker)
+
+	srv := &http.Server{
+		Addr:              cfg.HTTP.Addr,
+		Handler:           r,
+		ReadTimeout:       cfg.HTTP.ReadTimeout,
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout,
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
+	}
+
+	signalCtx, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Printf("Starting server at %s", cfg.HTTP.Addr)
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("server failed: %w", err)
+	case <-signalCtx.Done():
+	}
+	// A second signal kills the process instead of waiting for the drain.
+	stopSignals()
+
+	shutdown(srv, checker, runner, stopJobs, db, &cfg.HTTP)
+	return nil
+}
+
+// shutdown fails readiness, stops accepting connections and drains in-flight
+// requests, then stops the job runner and closes the DB pool. Everything
+// shares one deadline; what hasn't finished by then is cut off.
+func shutdown(srv *http.Server, checker *health.Checker, runner *jobs.Runner, stopJobs context.CancelFunc, db *gorm.DB, cfg *config.HTTPConfig) {
+	log.Printf("Shutting down, draining requests for up to %s", cfg.ShutdownTimeout)
+	checker.SetShuttingDown()
+	time.Sleep(cfg.ShutdownDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("HTTP server did not drain in time: %v", err)
+		_ = srv.Close()
+	}
+
+	stopJobs()
+	jobsDone := make(chan struct{})
+	go func() {
+		runner.Wait()
+		close(jobsDone)
+	}()
+	select {
+	case <-jobsDone:
+	case <-ctx.Done():
+		log.Printf("Background jobs did not stop in time")
+	}
+
+	if sqlDB, err := db.DB(); err == nil {
+		if err := sqlDB.Close(); err != nil {
+			log.Printf("Failed to close DB pool: %v", err)
+		}
+	}
+
+	log.Printf("Shutdown complete")
+}
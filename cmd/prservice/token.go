@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/mink0ff/pr_service/internal/repository"
+	"github.com/mink0ff/pr_service/internal/service"
+)
+
+func runToken(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Usage: prservice token create|revoke [flags]")
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet("token "+args[0], flag.ExitOnError)
+	cfgOpts := addConfigFlags(fs)
+	name := fs.String("name", "", "token name, e.g. the system that uses it")
+	ttl := fs.Duration("ttl", 0, "token lifetime, e.g. 720h; 0 never expires (create only)")
+	_ = fs.Parse(args[1:])
+
+	cfg, err := cfgOpts.load()
+	if err != nil {
+		return err
+	}
+	db, err := openDB(&cfg.DB, true)
+	if err != nil {
+		return err
+	}
+	tokenService := service.NewTokenService(repository.NewTokenRepo(db))
+
+	switch args[0] {
+	case "create":
+		token, err := tokenService.CreateToken(context.Background(), *name, *ttl)
+		if err != nil {
+			return err
+		}
+		log.Printf("Store the token now, it cannot be shown again")
+		return printJSON(token)
+	case "revoke":
+		return tokenService.RevokeToken(context.Background(), *name)
+	default:
+		return fmt.Errorf("unknown token action %q, expected create or revoke", args[0])
+	}
+}
@@ -0,0 +1,179 @@
+package codeowners
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Rule is a single CODEOWNERS line: a path pattern followed by its owners.
+// A rule without owners is valid and means the matching paths have no owner.
+type Rule struct {
+	Pattern string
+	Owners  []string
+	Line    int
+}
+
+// Parse reads rules in CODEOWNERS syntax. Blank lines and comments are
+// skipped, the order of rules is preserved since the last matching one wins.
+func Parse(r io.Reader) ([]Rule, error) {
+	var rules []Rule
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+
+		line := strings.TrimSpace(stripComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		rule := Rule{Pattern: strings.ReplaceAll(fields[0], `\#`, "#"), Owners: fields[1:], Line: lineNo}
+
+		if err := ValidatePattern(rule.Pattern); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		for _, owner := range rule.Owners {
+			if err := ValidateOwner(owner); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+		}
+
+		rules = append(rules, rule)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func stripComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '#' && (i == 0 || line[i-1] != '\\') {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func ValidatePattern(pattern string) error {
+	switch {
+	case pattern == "":
+		return fmt.Errorf("empty pattern")
+	case strings.HasPrefix(pattern, "!"):
+		return fmt.Errorf("negated pattern %q is not supported", pattern)
+	case strings.ContainsAny(pattern, "[]"):
+		return fmt.Errorf("character ranges in %q are not supported", pattern)
+	}
+
+	_, err := compile(pattern)
+	return err
+}
+
+// ValidateOwner checks the owner syntax: owners are written as @name, where
+// name is either a user id or a team name.
+func ValidateOwner(owner string) error {
+	name, ok := strings.CutPrefix(owner, "@")
+	if !ok || name == "" {
+		return fmt.Errorf("owner %q must look like @user_id or @team_name", owner)
+	}
+	return nil
+}
+
+// OwnerName strips the leading @ from an owner.
+func OwnerName(owner string) string {
+	return strings.TrimPrefix(owner, "@")
+}
+
+type Pattern struct {
+	raw string
+	re  *regexp.Regexp
+}
+
+func NewPattern(pattern string) (*Pattern, error) {
+	re, err := compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return &Pattern{raw: pattern, re: re}, nil
+}
+
+func (p *Pattern) String() string {
+	return p.raw
+}
+
+func (p *Pattern) Match(filePath string) bool {
+	return p.re.MatchString(NormalizePath(filePath))
+}
+
+// NormalizePath turns a changed file path into the repository-relative form
+// patterns are matched against.
+func NormalizePath(filePath string) string {
+	p := path.Clean("/" + strings.TrimSpace(filePath))
+	return strings.TrimPrefix(p, "/")
+}
+
+// compile translates a gitignore-style pattern into a regular expression:
+//   - a leading or inner slash anchors the pattern to the repository root,
+//     otherwise it matches at any depth;
+//   - a trailing slash matches directories only;
+//   - * and ? never cross a slash, ** does;
+//   - a pattern that names a directory also matches everything below it,
+//     except for a trailing /* which matches direct children only.
+func compile(pattern string) (*regexp.Regexp, error) {
+	p := pattern
+
+	dirOnly := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+
+	if p == "" {
+		return nil, fmt.Errorf("pattern %q matches nothing", pattern)
+	}
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; {
+		case strings.HasPrefix(p[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '\\' && i+1 < len(p):
+			i++
+			b.WriteString(regexp.QuoteMeta(string(p[i])))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	switch {
+	case dirOnly:
+		b.WriteString("/.*$")
+	case strings.HasSuffix(p, "/*") && !strings.HasSuffix(p, "/**"):
+		b.WriteString("$")
+	default:
+		b.WriteString("(?:/.*)?$")
+	}
+
+	return regexp.Compile(b.String())
+}
@@ -1,48 +1,218 @@
 package config
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	"log"
 	"os"
-	"strconv"
 	"time"
 
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
 )
 
+// Config is the whole service configuration. It is built in layers:
+// Default, then the optional config file, then environment variables (the
+// env file included), then command line flags applied by the caller.
+type Config struct {
+	HTTP       HTTPConfig       `yaml:"http"`
+	DB         DBConfig         `yaml:"db"`
+	Log        LogConfig        `yaml:"log"`
+	Auth       AuthConfig       `yaml:"auth"`
+	Selection  SelectionConfig  `yaml:"selection"`
+	Notify     NotifyConfig     `yaml:"notify"`
+	SMTP       SMTPConfig       `yaml:"smtp"`
+	Jobs       JobsConfig       `yaml:"jobs"`
+	Escalation EscalationConfig `yaml:"escalation"`
+	Retention  RetentionConfig  `yaml:"retention"`
+	Health     HealthConfig     `yaml:"health"`
+}
+
+type HTTPConfig struct {
+	Addr              string        `yaml:"addr"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+	// ShutdownDelay keeps serving after readiness starts failing, so load
+	// balancers stop routing here before the listener closes.
+	ShutdownDelay   time.Duration `yaml:"shutdown_delay"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
 type DBConfig struct {
-	DSN           string
-	MaxOpenConns  int
-	MaxIdleConns  int
-	ConnMaxLife   time.Duration
-	MigrationPath string
+	DSN           string        `yaml:"dsn"`
+	MaxOpenConns  int           `yaml:"max_open_conns"`
+	MaxIdleConns  int           `yaml:"max_idle_conns"`
+	ConnMaxLife   time.Duration `yaml:"conn_max_lifetime"`
+	MigrationPath string        `yaml:"migration_path"`
+}
+
+type LogConfig struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+}
+
+type AuthConfig struct {
+	// AdminTokenRequired protects /admin/* with API tokens created by
+	// "prservice token create". When it is off the admin routes are not
+	// served at all.
+	AdminTokenRequired bool `yaml:"admin_token_required"`
+}
+
+type SelectionConfig struct {
+	Mode string `yaml:"mode"`
+	Seed int64  `yaml:"seed"`
+}
+
+type NotifyConfig struct {
+	Enabled        bool          `yaml:"enabled"`
+	PRLinkTemplate string        `yaml:"pr_link_template"`
+	Timeout        time.Duration `yaml:"timeout"`
+	DigestSchedule string        `yaml:"digest_schedule"`
+}
+
+type SMTPConfig struct {
+	Enabled        bool          `yaml:"enabled"`
+	Host           string        `yaml:"host"`
+	Port           int           `yaml:"port"`
+	Username       string        `yaml:"username"`
+	Password       string        `yaml:"password"`
+	From           string        `yaml:"from"`
+	Timeout        time.Duration `yaml:"timeout"`
+	DigestSchedule string        `yaml:"digest_schedule"`
+}
+
+type JobsConfig struct {
+	// Enabled runs the background jobs in this process. Turning it off lets
+	// some replicas serve only the API.
+	Enabled bool `yaml:"enabled"`
 }
 
-func LoadDBConfig(envFile string) *DBConfig {
+type EscalationConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Schedule string `yaml:"schedule"`
+}
+
+// RetentionConfig controls the job that hard-deletes soft-deleted teams,
+// users and pull requests once Period has passed. Mode "archive" copies them
+// to the archive schema first, "delete" drops them.
+type RetentionConfig struct {
+	Enabled  bool          `yaml:"enabled"`
+	Schedule string        `yaml:"schedule"`
+	Period   time.Duration `yaml:"period"`
+	Mode     string        `yaml:"mode"`
+}
+
+type HealthConfig struct {
+	// CheckTimeout bounds each readiness check separately.
+	CheckTimeout time.Duration `yaml:"check_timeout"`
+	// CacheTTL is how long a readiness report is reused before the checks
+	// run again.
+	CacheTTL time.Duration `yaml:"cache_ttl"`
+}
+
+func Default() *Config {
+	return &Config{
+		HTTP: HTTPConfig{
+			Addr:              ":8080",
+			ReadTimeout:       15 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       120 * time.Second,
+			ShutdownTimeout:   30 * time.Second,
+		},
+		DB: DBConfig{
+			MaxOpenConns:  10,
+			MaxIdleConns:  5,
+			ConnMaxLife:   300 * time.Second,
+			MigrationPath: "/migrations",
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "text",
+		},
+		Auth: AuthConfig{
+			AdminTokenRequired: true,
+		},
+		Selection: SelectionConfig{
+			Mode: "random",
+		},
+		Notify: NotifyConfig{
+			Timeout:        5 * time.Second,
+			DigestSchedule: "0 9 * * 1-5",
+		},
+		SMTP: SMTPConfig{
+			Host:           "localhost",
+			Port:           25,
+			From:           "pr-service@localhost",
+			Timeout:        10 * time.Second,
+			DigestSchedule: "0 8 * * *",
+		},
+		Jobs: JobsConfig{
+			Enabled: true,
+		},
+		Escalation: EscalationConfig{
+			Enabled:  true,
+			Schedule: "*/15 * * * *",
+		},
+		Retention: RetentionConfig{
+			Enabled:  true,
+			Schedule: "0 3 * * *",
+			Period:   90 * 24 * time.Hour,
+			Mode:     "archive",
+		},
+		Health: HealthConfig{
+			CheckTimeout: 2 * time.Second,
+			CacheTTL:     time.Second,
+		},
+	}
+}
+
+// Load builds the configuration from the defaults, the config file (YAML or
+// JSON, skipped when file is empty) and the environment. The env file, when
+// present, is loaded into the environment first without overriding
+// variables that are already set.
+//
+// Every malformed value is reported, not just the first one. Load does not
+// call Validate.
+func Load(file, envFile string) (*Config, error) {
 	if envFile != "" {
 		if err := godotenv.Load(envFile); err != nil {
 			log.Printf("No %s file found, using environment variables", envFile)
 		}
-	} else {
-		_ = godotenv.Load()
 	}
 
-	maxOpen, _ := strconv.Atoi(getEnv("DB_MAX_OPEN_CONNS", "10"))
-	maxIdle, _ := strconv.Atoi(getEnv("DB_MAX_IDLE_CONNS", "5"))
-	connMaxLifeSec, _ := strconv.Atoi(getEnv("DB_CONN_MAX_LIFETIME", "300"))
-	migrationPath := getEnv("DB_MIGRATION_PATH", "/migrations")
-
-	return &DBConfig{
-		DSN:           getEnv("DB_DSN", ""),
-		MaxOpenConns:  maxOpen,
-		MaxIdleConns:  maxIdle,
-		ConnMaxLife:   time.Duration(connMaxLifeSec) * time.Second,
-		MigrationPath: migrationPath,
+	cfg := Default()
+	if file != "" {
+		if err := cfg.loadFile(file); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.loadEnv(); err != nil {
+		return nil, err
 	}
+
+	return cfg, nil
 }
 
-func getEnv(key, defaultVal string) string {
-	if val, ok := os.LookupEnv(key); ok {
-		return val
+// loadFile decodes the file over the current values, so keys missing from
+// the file keep their defaults. JSON is valid YAML, so one decoder handles
+// both. Unknown keys are rejected to catch typos.
+func (c *Config) loadFile(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config file %s: %w", file, err)
 	}
-	return defaultVal
+
+	return nil
 }
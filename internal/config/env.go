@@ -0,0 +1,140 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// envReader applies the variables that are set and collects a parse error
+// for each malformed one.
+type envReader struct {
+	errs []error
+}
+
+func (c *Config) loadEnv() error {
+	e := &envReader{}
+
+	if port, ok := os.LookupEnv("PORT"); ok {
+		c.HTTP.Addr = ":" + port
+	}
+	e.seconds("HTTP_READ_TIMEOUT", &c.HTTP.ReadTimeout)
+	e.seconds("HTTP_READ_HEADER_TIMEOUT", &c.HTTP.ReadHeaderTimeout)
+	e.seconds("HTTP_WRITE_TIMEOUT", &c.HTTP.WriteTimeout)
+	e.seconds("HTTP_IDLE_TIMEOUT", &c.HTTP.IdleTimeout)
+	e.seconds("HTTP_SHUTDOWN_DELAY", &c.HTTP.ShutdownDelay)
+	e.seconds("HTTP_SHUTDOWN_TIMEOUT", &c.HTTP.ShutdownTimeout)
+
+	e.string("DB_DSN", &c.DB.DSN)
+	e.int("DB_MAX_OPEN_CONNS", &c.DB.MaxOpenConns)
+	e.int("DB_MAX_IDLE_CONNS", &c.DB.MaxIdleConns)
+	e.seconds("DB_CONN_MAX_LIFETIME", &c.DB.ConnMaxLife)
+	e.string("DB_MIGRATION_PATH", &c.DB.MigrationPath)
+
+	e.string("LOG_LEVEL", &c.Log.Level)
+	e.string("LOG_FORMAT", &c.Log.Format)
+
+	e.bool("ADMIN_AUTH_ENABLED", &c.Auth.AdminTokenRequired)
+
+	e.string("REVIEWER_SELECTION_MODE", &c.Selection.Mode)
+	e.int64("REVIEWER_SELECTION_SEED", &c.Selection.Seed)
+
+	e.bool("NOTIFY_ENABLED", &c.Notify.Enabled)
+	e.string("NOTIFY_PR_LINK_TEMPLATE", &c.Notify.PRLinkTemplate)
+	e.seconds("NOTIFY_TIMEOUT", &c.Notify.Timeout)
+	e.string("NOTIFY_DIGEST_SCHEDULE", &c.Notify.DigestSchedule)
+
+	e.bool("EMAIL_DIGEST_ENABLED", &c.SMTP.Enabled)
+	e.string("SMTP_HOST", &c.SMTP.Host)
+	e.int("SMTP_PORT", &c.SMTP.Port)
+	e.string("SMTP_USERNAME", &c.SMTP.Username)
+	e.string("SMTP_PASSWORD", &c.SMTP.Password)
+	e.string("SMTP_FROM", &c.SMTP.From)
+	e.seconds("SMTP_TIMEOUT", &c.SMTP.Timeout)
+	e.string("EMAIL_DIGEST_SCHEDULE", &c.SMTP.DigestSchedule)
+
+	e.bool("JOBS_ENABLED", &c.Jobs.Enabled)
+
+	e.bool("ESCALATION_ENABLED", &c.Escalation.Enabled)
+	e.string("ESCALATION_SCHEDULE", &c.Escalation.Schedule)
+
+	e.bool("RETENTION_ENABLED", &c.Retention.Enabled)
+	e.string("RETENTION_SCHEDULE", &c.Retention.Schedule)
+	e.duration("RETENTION_DAYS", 24*time.Hour, &c.Retention.Period)
+	e.string("RETENTION_MODE", &c.Retention.Mode)
+
+	e.millis("HEALTH_CHECK_TIMEOUT_MS", &c.Health.CheckTimeout)
+	e.millis("HEALTH_CACHE_TTL_MS", &c.Health.CacheTTL)
+
+	return errors.Join(e.errs...)
+}
+
+func (e *envReader) string(key string, dst *string) {
+	if val, ok := os.LookupEnv(key); ok {
+		*dst = val
+	}
+}
+
+func (e *envReader) int(key string, dst *int) {
+	val, ok := os.LookupEnv(key)
+	if !ok {
+		return
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s: %q is not an integer", key, val))
+		return
+	}
+	*dst = n
+}
+
+func (e *envReader) int64(key string, dst *int64) {
+	val, ok := os.LookupEnv(key)
+	if !ok {
+		return
+	}
+	n, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s: %q is not an integer", key, val))
+		return
+	}
+	*dst = n
+}
+
+func (e *envReader) bool(key string, dst *bool) {
+	val, ok := os.LookupEnv(key)
+	if !ok {
+		return
+	}
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s: %q is not a boolean", key, val))
+		return
+	}
+	*dst = b
+}
+
+// seconds and millis keep the units the variables have always used, while
+// the config file takes Go durations such as "15s".
+func (e *envReader) seconds(key string, dst *time.Duration) {
+	e.duration(key, time.Second, dst)
+}
+
+func (e *envReader) millis(key string, dst *time.Duration) {
+	e.duration(key, time.Millisecond, dst)
+}
+
+func (e *envReader) duration(key string, unit time.Duration, dst *time.Duration) {
+	val, ok := os.LookupEnv(key)
+	if !ok {
+		return
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s: %q is not an integer", key, val))
+		return
+	}
+	*dst = time.Duration(n) * unit
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+)
+
+var (
+	LogLevels      = []string{"debug", "info", "warn", "error"}
+	LogFormats     = []string{"text", "json"}
+	RetentionModes = []string{"archive", "delete"}
+)
+
+// validator collects every problem so one run of check-config shows them
+// all. Keys are named by their config file path and env variable.
+type validator struct {
+	errs []error
+}
+
+func (v *validator) check(ok bool, key, env, format string, args ...any) {
+	if !ok {
+		v.errs = append(v.errs, fmt.Errorf("%s (%s): %s", key, env, fmt.Sprintf(format, args...)))
+	}
+}
+
+func (v *validator) positive(d time.Duration, key, env string) {
+	v.check(d > 0, key, env, "must be positive, got %s", d)
+}
+
+func (v *validator) nonNegative(d time.Duration, key, env string) {
+	v.check(d >= 0, key, env, "must not be negative, got %s", d)
+}
+
+// Validate checks the values the config package can judge by itself.
+// Reviewer selection modes and job schedules are validated by their owners.
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	_, port, err := net.SplitHostPort(c.HTTP.Addr)
+	v.check(err == nil && validPort(port), "http.addr", "PORT", "invalid listen address %q", c.HTTP.Addr)
+	v.nonNegative(c.HTTP.ReadTimeout, "http.read_timeout", "HTTP_READ_TIMEOUT")
+	v.nonNegative(c.HTTP.ReadHeaderTimeout, "http.read_header_timeout", "HTTP_READ_HEADER_TIMEOUT")
+	v.nonNegative(c.HTTP.WriteTimeout, "http.write_timeout", "HTTP_WRITE_TIMEOUT")
+	v.nonNegative(c.HTTP.IdleTimeout, "http.idle_timeout", "HTTP_IDLE_TIMEOUT")
+	v.nonNegative(c.HTTP.ShutdownDelay, "http.shutdown_delay", "HTTP_SHUTDOWN_DELAY")
+	v.positive(c.HTTP.ShutdownTimeout, "http.shutdown_timeout", "HTTP_SHUTDOWN_TIMEOUT")
+
+	v.check(c.DB.DSN != "", "db.dsn", "DB_DSN", "must be set")
+	v.check(c.DB.MaxOpenConns > 0, "db.max_open_conns", "DB_MAX_OPEN_CONNS", "must be positive, got %d", c.DB.MaxOpenConns)
+	v.check(c.DB.MaxIdleConns >= 0 && c.DB.MaxIdleConns <= c.DB.MaxOpenConns, "db.max_idle_conns", "DB_MAX_IDLE_CONNS",
+		"must be between 0 and db.max_open_conns, got %d", c.DB.MaxIdleConns)
+	v.nonNegative(c.DB.ConnMaxLife, "db.conn_max_lifetime", "DB_CONN_MAX_LIFETIME")
+	info, err := os.Stat(c.DB.MigrationPath)
+	v.check(err == nil && info.IsDir(), "db.migration_path", "DB_MIGRATION_PATH", "%q is not a directory", c.DB.MigrationPath)
+
+	v.check(slices.Contains(LogLevels, c.Log.Level), "log.level", "LOG_LEVEL", "must be one of %v, got %q", LogLevels, c.Log.Level)
+	v.check(slices.Contains(LogFormats, c.Log.Format), "log.format", "LOG_FORMAT", "must be one of %v, got %q", LogFormats, c.Log.Format)
+
+	if c.Notify.Enabled {
+		v.positive(c.Notify.Timeout, "notify.timeout", "NOTIFY_TIMEOUT")
+	}
+
+	if c.SMTP.Enabled {
+		v.check(c.SMTP.Host != "", "smtp.host", "SMTP_HOST", "must be set")
+		v.check(validPort(strconv.Itoa(c.SMTP.Port)), "smtp.port", "SMTP_PORT", "invalid port %d", c.SMTP.Port)
+		_, err := mail.ParseAddress(c.SMTP.From)
+		v.check(err == nil, "smtp.from", "SMTP_FROM", "invalid address %q", c.SMTP.From)
+		v.positive(c.SMTP.Timeout, "smtp.timeout", "SMTP_TIMEOUT")
+	}
+
+	if c.Retention.Enabled {
+		v.positive(c.Retention.Period, "retention.period", "RETENTION_DAYS")
+		v.check(slices.Contains(RetentionModes, c.Retention.Mode), "retention.mode", "RETENTION_MODE",
+			"must be one of %v, got %q", RetentionModes, c.Retention.Mode)
+	}
+
+	v.positive(c.Health.CheckTimeout, "health.check_timeout", "HEALTH_CHECK_TIMEOUT_MS")
+	v.nonNegative(c.Health.CacheTTL, "health.cache_ttl", "HEALTH_CACHE_TTL_MS")
+
+	return errors.Join(v.errs...)
+}
+
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n > 0 && n <= 65535
+}
+
+const redacted = "REDACTED"
+
+// Redacted returns a copy that is safe to print or log.
+func (c *Config) Redacted() *Config {
+	out := *c
+	out.DB.DSN = redactDSN(c.DB.DSN)
+	if out.SMTP.Password != "" {
+		out.SMTP.Password = redacted
+	}
+	return &out
+}
+
+var dsnPassword = regexp.MustCompile(`(password=)\S+`)
+
+// redactDSN hides the password in both URL and key=value DSNs.
+func redactDSN(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), redacted)
+			return u.String()
+		}
+	}
+	return dsnPassword.ReplaceAllString(dsn, "${1}"+redacted)
+}
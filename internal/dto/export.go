@@ -0,0 +1,125 @@
+package dto
+
+import "time"
+
+type ExportTeam struct {
+	TeamID       string     `json:"team_id"`
+	TeamName     string     `json:"team_name"`
+	WebhookURL   *string    `json:"webhook_url"`
+	ParentTeamID *string    `json:"parent_team_id"`
+	DeletedAt    *time.Time `json:"deleted_at"`
+}
+
+type ExportUser struct {
+	UserID         string     `json:"user_id"`
+	Username       string     `json:"username"`
+	TeamID         string     `json:"team_id"`
+	IsActive       bool       `json:"is_active"`
+	MaxOpenReviews *int       `json:"max_open_reviews"`
+	DeletedAt      *time.Time `json:"deleted_at"`
+}
+
+type ExportMembership struct {
+	UserID    string    `json:"user_id"`
+	TeamID    string    `json:"team_id"`
+	IsPrimary bool      `json:"is_primary"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ExportTeamSettings struct {
+	TeamID                string `json:"team_id"`
+	SLARemindAfterHours   *int   `json:"sla_remind_after_hours"`
+	SLAReassignAfterHours *int   `json:"sla_reassign_after_hours"`
+	RotationWindow        *int   `json:"rotation_window"`
+}
+
+type ExportOwnershipRule struct {
+	RuleID    string    `json:"rule_id"`
+	TeamID    string    `json:"team_id"`
+	Position  int       `json:"position"`
+	Pattern   string    `json:"pattern"`
+	Owners    string    `json:"owners"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ExportUserSkill struct {
+	UserID string `json:"user_id"`
+	Tag    string `json:"tag"`
+}
+
+type ExportNotificationSettings struct {
+	UserID           string     `json:"user_id"`
+	Email            *string    `json:"email"`
+	EmailOptOut      bool       `json:"email_opt_out"`
+	DigestFrequency  string     `json:"digest_frequency"`
+	LastDigestSentAt *time.Time `json:"last_digest_sent_at"`
+}
+
+type ExportPullRequest struct {
+	PullRequestID   string     `json:"pull_request_id"`
+	PullRequestName string     `json:"pull_request_name"`
+	AuthorID        string     `json:"author_id"`
+	Status          PRStatus   `json:"status"`
+	CreatedAt       time.Time  `json:"created_at"`
+	MergedAt        *time.Time `json:"merged_at"`
+	SelectionSeed   *int64     `json:"selection_seed"`
+	DeletedAt       *time.Time `json:"deleted_at"`
+}
+
+type ExportReviewer struct {
+	PullRequestID string     `json:"pull_request_id"`
+	ReviewerID    string     `json:"reviewer_id"`
+	AssignedAt    time.Time  `json:"assigned_at"`
+	RemindedAt    *time.Time `json:"reminded_at"`
+	Pinned        bool       `json:"pinned"`
+}
+
+type ExportLabel struct {
+	PullRequestID string `json:"pull_request_id"`
+	Label         string `json:"label"`
+}
+
+type ExportHistoryEvent struct {
+	EventID       string    `json:"event_id"`
+	PullRequestID string    `json:"pull_request_id"`
+	UserID        string    `json:"user_id"`
+	EventType     string    `json:"event_type"`
+	SelectionSeed *int64    `json:"selection_seed"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type ExportAuditEvent struct {
+	EventID   string    `json:"event_id"`
+	TeamID    string    `json:"team_id"`
+	TeamName  string    `json:"team_name"`
+	Action    string    `json:"action"`
+	UserID    *string   `json:"user_id"`
+	OldValue  *string   `json:"old_value"`
+	NewValue  *string   `json:"new_value"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ExportAPIToken carries only the token hash, same as the database.
+type ExportAPIToken struct {
+	TokenID    string     `json:"token_id"`
+	Name       string     `json:"name"`
+	TokenHash  string     `json:"token_hash"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+// SnapshotManifest is the first entry of a snapshot archive.
+type SnapshotManifest struct {
+	Version       int              `json:"version"`
+	SchemaVersion uint             `json:"schema_version"`
+	ExportedAt    time.Time        `json:"exported_at"`
+	Counts        map[string]int64 `json:"counts"`
+}
+
+type RestoreReport struct {
+	SchemaVersion uint             `json:"schema_version"`
+	ExportedAt    time.Time        `json:"exported_at"`
+	Restored      map[string]int64 `json:"restored"`
+}
@@ -0,0 +1,23 @@
+package dto
+
+import "time"
+
+const (
+	HealthOK           = "ok"
+	HealthFail         = "fail"
+	HealthShuttingDown = "shutting_down"
+)
+
+type HealthCheckResult struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Error     string  `json:"error,omitempty"`
+	LatencyMS float64 `json:"latency_ms"`
+}
+
+type HealthReport struct {
+	Status    string              `json:"status"`
+	Checks    []HealthCheckResult `json:"checks"`
+	CheckedAt time.Time           `json:"checked_at"`
+	Cached    bool                `json:"cached"`
+}
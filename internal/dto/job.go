@@ -0,0 +1,40 @@
+package dto
+
+import "time"
+
+type JobRun struct {
+	JobRunID   string     `json:"job_run_id"`
+	JobName    string     `json:"job_name"`
+	Trigger    string     `json:"trigger"`
+	Status     string     `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	Instance   string     `json:"instance"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+type JobStatus struct {
+	Name      string     `json:"name"`
+	Schedule  string     `json:"schedule"`
+	Paused    bool       `json:"paused"`
+	NextRunAt *time.Time `json:"next_run_at,omitempty"`
+	LastRun   *JobRun    `json:"last_run,omitempty"`
+}
+
+type ListJobsResponse struct {
+	Jobs []JobStatus `json:"jobs"`
+}
+
+type ListJobRunsResponse struct {
+	JobName string   `json:"job_name"`
+	Runs    []JobRun `json:"runs"`
+}
+
+type JobNameRequest struct {
+	Name string `json:"name"`
+}
+
+type TriggerJobResponse struct {
+	Name      string `json:"name"`
+	Triggered bool   `json:"triggered"`
+}
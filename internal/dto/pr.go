@@ -15,6 +15,9 @@ type PullRequestDTO struct {
 	AuthorID          string     `json:"author_id"`
 	Status            PRStatus   `json:"status"`
 	AssignedReviewers []string   `json:"assigned_reviewers"`
+	PinnedReviewers   []string   `json:"pinned_reviewers,omitempty"`
+	Labels            []string   `json:"labels,omitempty"`
+	SelectionSeed     *int64     `json:"selection_seed,omitempty"`
 	CreatedAt         *time.Time `json:"createdAt,omitempty"`
 	MergedAt          *time.Time `json:"mergedAt,omitempty"`
 }
@@ -27,13 +30,92 @@ type PullRequestShortDTO struct {
 }
 
 type CreatePRRequest struct {
-	PullRequestID   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorID        string `json:"author_id"`
+	PullRequestID   string   `json:"pull_request_id"`
+	PullRequestName string   `json:"pull_request_name"`
+	AuthorID        string   `json:"author_id"`
+	ChangedFiles    []string `json:"changed_files,omitempty"`
+	Labels          []string `json:"labels,omitempty"`
+	// Seed replays a recorded reviewer selection. It is not accepted from
+	// authors, who could otherwise retry seeds until they get the reviewers
+	// they want; only ReplayPRRequest on the admin path sets it.
+	Seed *int64 `json:"-"`
+}
+
+// ReplayPRRequest creates a pull request with reviewers picked from the given
+// seed, e.g. one returned by a preview or recorded in the history.
+type ReplayPRRequest struct {
+	CreatePRRequest
+	Seed *int64 `json:"seed"`
+}
+
+// ReviewerMatch explains why a reviewer was picked: MatchedRule is the
+// ownership rule the reviewer owns for the changed files and MatchedSkills are
+// the reviewer's skills found among the PR labels. Both empty mean a random pick.
+// RecentReviews is how many of the author's recent PRs the reviewer already
+// reviewed, which lowered their chance when team rotation is on.
+// FallbackTeam is set when the author's team had too few candidates and the
+// reviewer was taken from this parent team.
+type ReviewerMatch struct {
+	ReviewerID    string         `json:"reviewer_id"`
+	MatchedRule   *OwnershipRule `json:"matched_rule"`
+	MatchedSkills []string       `json:"matched_skills,omitempty"`
+	RecentReviews int            `json:"recent_reviews,omitempty"`
+	FallbackTeam  string         `json:"fallback_team,omitempty"`
 }
 
+// CreatePRResponse lists in AtCapacity the candidates skipped for being at
+// their review limit when the pull request got fewer reviewers than it could.
 type CreatePRResponse struct {
-	PR PullRequestDTO `json:"pr"`
+	PR              PullRequestDTO  `json:"pr"`
+	ReviewerMatches []ReviewerMatch `json:"reviewer_matches"`
+	AtCapacity      []string        `json:"at_capacity,omitempty"`
+}
+
+// PreviewReviewersRequest mirrors CreatePRRequest; PullRequestID is optional
+// and only used to derive the seed and to skip the PR itself in rotation.
+type PreviewReviewersRequest struct {
+	PullRequestID string   `json:"pull_request_id,omitempty"`
+	AuthorID      string   `json:"author_id"`
+	ChangedFiles  []string `json:"changed_files,omitempty"`
+	Labels        []string `json:"labels,omitempty"`
+	Seed          *int64   `json:"seed,omitempty"`
+}
+
+type CandidateReason string
+
+const (
+	CandidateCodeOwner      CandidateReason = "CODE_OWNER"
+	CandidateSkillMatch     CandidateReason = "SKILL_MATCH"
+	CandidateTeamMember     CandidateReason = "TEAM_MEMBER"
+	CandidateRecentReviewer CandidateReason = "RECENT_REVIEWER"
+	CandidateAuthor         CandidateReason = "AUTHOR"
+	CandidateInactive       CandidateReason = "INACTIVE"
+	CandidateAtCapacity     CandidateReason = "AT_CAPACITY"
+	CandidateParentTeam     CandidateReason = "PARENT_TEAM"
+)
+
+// ReviewerCandidate is one team member or code owner as seen by the selection:
+// eligible candidates are ranked, selected ones first, and Reasons says why a
+// candidate was considered or excluded.
+type ReviewerCandidate struct {
+	UserID        string            `json:"user_id"`
+	Username      string            `json:"username"`
+	Rank          int               `json:"rank,omitempty"`
+	Eligible      bool              `json:"eligible"`
+	Selected      bool              `json:"selected"`
+	Reasons       []CandidateReason `json:"reasons"`
+	Weight        float64           `json:"weight,omitempty"`
+	MatchedRule   *OwnershipRule    `json:"matched_rule,omitempty"`
+	MatchedSkills []string          `json:"matched_skills,omitempty"`
+	RecentReviews int               `json:"recent_reviews,omitempty"`
+}
+
+type PreviewReviewersResponse struct {
+	AuthorID   string              `json:"author_id"`
+	TeamName   string              `json:"team_name"`
+	Seed       int64               `json:"seed"`
+	Reviewers  []string            `json:"reviewers"`
+	Candidates []ReviewerCandidate `json:"candidates"`
 }
 
 type MergePRRequest struct {
@@ -44,12 +126,77 @@ type MergePRResponse struct {
 	PR PullRequestDTO `json:"pr"`
 }
 
+type DeletePRRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+}
+
 type ReassignReviewerRequest struct {
 	PullRequestID string `json:"pull_request_id"`
 	OldUserID     string `json:"old_user_id"`
+	// SLAEscalation is set by the SLA worker so the reassignment is recorded
+	// as SLA_REASSIGNED in the same transaction.
+	SLAEscalation bool `json:"-"`
 }
 
 type ReassignReviewerResponse struct {
 	PR         PullRequestDTO `json:"pr"`
 	ReplacedBy string         `json:"replaced_by"`
 }
+
+type AddReviewerRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+	Pin           bool   `json:"pin,omitempty"`
+}
+
+// RemoveReviewerRequest removes a reviewer; with Backfill the freed slot is
+// filled the same way /pullRequest/reassign picks a replacement.
+type RemoveReviewerRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+	Backfill      bool   `json:"backfill,omitempty"`
+}
+
+type PinReviewerRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+}
+
+type ReviewerChangeResponse struct {
+	PR         PullRequestDTO `json:"pr"`
+	ReplacedBy string         `json:"replaced_by,omitempty"`
+}
+
+type PRLabels struct {
+	PullRequestID string   `json:"pull_request_id"`
+	Labels        []string `json:"labels"`
+}
+
+// ImportPullRequest is one historical pull request to import with its
+// reviewers as is. Timestamps are RFC 3339 or plain dates; Row is the line
+// of the input the record came from.
+type ImportPullRequest struct {
+	Row             int      `json:"-"`
+	PullRequestID   string   `json:"pull_request_id"`
+	PullRequestName string   `json:"pull_request_name"`
+	AuthorID        string   `json:"author_id"`
+	Status          PRStatus `json:"status"`
+	Reviewers       []string `json:"reviewers"`
+	CreatedAt       string   `json:"created_at"`
+	MergedAt        string   `json:"merged_at,omitempty"`
+}
+
+type ImportRowError struct {
+	Row           int    `json:"row"`
+	PullRequestID string `json:"pull_request_id,omitempty"`
+	Error         string `json:"error"`
+}
+
+// ImportReport summarizes an import. Any row error rejects the whole input,
+// in which case Imported is zero.
+type ImportReport struct {
+	Total    int              `json:"total"`
+	Imported int              `json:"imported"`
+	DryRun   bool             `json:"dry_run"`
+	Errors   []ImportRowError `json:"errors"`
+}
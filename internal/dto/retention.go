@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+type DeletedRecord struct {
+	Entity    string    `json:"entity"`
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+type DeletedRecordsResponse struct {
+	Entity  string          `json:"entity"`
+	Records []DeletedRecord `json:"records"`
+}
+
+type RestoreDeletedRequest struct {
+	Entity string `json:"entity"`
+	ID     string `json:"id"`
+}
+
+// RetentionReport counts the records purged by one retention run.
+type RetentionReport struct {
+	Mode         string `json:"mode"`
+	PullRequests int64  `json:"pull_requests"`
+	Users        int64  `json:"users"`
+	Teams        int64  `json:"teams"`
+}
@@ -8,3 +8,23 @@ type ReviewerStatsItem struct {
 type ReviewerStatsResponse struct {
 	Items []ReviewerStatsItem `json:"items"`
 }
+
+type TeamStatsCounts struct {
+	Members       int64 `json:"members"`
+	ActiveMembers int64 `json:"active_members"`
+	Assignments   int64 `json:"assignments"`
+	OpenReviews   int64 `json:"open_reviews"`
+}
+
+// TeamStatsNode holds a team's own numbers and the totals including all of
+// its sub-teams.
+type TeamStatsNode struct {
+	TeamName string          `json:"team_name"`
+	Own      TeamStatsCounts `json:"own"`
+	Total    TeamStatsCounts `json:"total"`
+	Children []TeamStatsNode `json:"children"`
+}
+
+type TeamStatsResponse struct {
+	Teams []TeamStatsNode `json:"teams"`
+}
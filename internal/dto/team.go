@@ -1,9 +1,14 @@
 package dto
 
+import "time"
+
+// TeamMember is a member of a team. PrimaryTeam is only filled in responses,
+// for members whose primary team is another one.
 type TeamMember struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	IsActive bool   `json:"is_active"`
+	UserID      string `json:"user_id"`
+	Username    string `json:"username"`
+	IsActive    bool   `json:"is_active"`
+	PrimaryTeam string `json:"primary_team,omitempty"`
 }
 
 type Team struct {
@@ -25,3 +30,175 @@ type DeactivateTeamUsersResponse struct {
 	TeamName         string `json:"team_name"`
 	DeactivatedCount int    `json:"deactivated_count"`
 }
+
+type SetTeamWebhookRequest struct {
+	TeamName   string `json:"team_name"`
+	WebhookURL string `json:"webhook_url"`
+}
+
+type SetTeamWebhookResponse struct {
+	TeamName          string `json:"team_name"`
+	WebhookConfigured bool   `json:"webhook_configured"`
+}
+
+type TeamSLA struct {
+	TeamName           string `json:"team_name"`
+	RemindAfterHours   *int   `json:"remind_after_hours"`
+	ReassignAfterHours *int   `json:"reassign_after_hours"`
+}
+
+type TeamRotation struct {
+	TeamName string `json:"team_name"`
+	Window   *int   `json:"window"`
+}
+
+type EscalationReport struct {
+	Reminded   int `json:"reminded"`
+	Reassigned int `json:"reassigned"`
+	Skipped    int `json:"skipped"`
+}
+
+type OwnershipRule struct {
+	RuleID   string   `json:"rule_id"`
+	Position int      `json:"position"`
+	Pattern  string   `json:"pattern"`
+	Owners   []string `json:"owners"`
+}
+
+type OwnershipRulesResponse struct {
+	TeamName string          `json:"team_name"`
+	Rules    []OwnershipRule `json:"rules"`
+}
+
+type ImportOwnershipRulesRequest struct {
+	TeamName string `json:"team_name"`
+	Content  string `json:"content"`
+}
+
+type AddOwnershipRuleRequest struct {
+	TeamName string   `json:"team_name"`
+	Pattern  string   `json:"pattern"`
+	Owners   []string `json:"owners"`
+}
+
+type DeleteOwnershipRuleRequest struct {
+	TeamName string `json:"team_name"`
+	RuleID   string `json:"rule_id"`
+}
+
+type AddTeamMemberRequest struct {
+	TeamName string `json:"team_name"`
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	IsActive bool   `json:"is_active"`
+}
+
+// MoveTeamMemberRequest moves the user into TeamName.
+type MoveTeamMemberRequest struct {
+	UserID   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+}
+
+type ReassignedReview struct {
+	PullRequestID string `json:"pull_request_id"`
+	ReplacedBy    string `json:"replaced_by"`
+}
+
+// MoveTeamMemberResponse lists what happened to the user's open reviews:
+// KeptReviews are the pull requests where the old team had no replacement
+// or the user is pinned.
+type MoveTeamMemberResponse struct {
+	User        User               `json:"user"`
+	FromTeam    string             `json:"from_team"`
+	Reassigned  []ReassignedReview `json:"reassigned_reviews"`
+	KeptReviews []string           `json:"kept_reviews"`
+}
+
+type RemoveTeamMemberRequest struct {
+	TeamName string `json:"team_name"`
+	UserID   string `json:"user_id"`
+}
+
+// TeamMembershipRequest adds an existing user to one more team, or changes
+// whether they review for it.
+type TeamMembershipRequest struct {
+	TeamName string `json:"team_name"`
+	UserID   string `json:"user_id"`
+	IsActive bool   `json:"is_active"`
+}
+
+type RemoveMembershipRequest struct {
+	TeamName string `json:"team_name"`
+	UserID   string `json:"user_id"`
+}
+
+type RenameTeamRequest struct {
+	TeamName    string `json:"team_name"`
+	NewTeamName string `json:"new_team_name"`
+}
+
+type DeleteTeamRequest struct {
+	TeamName string `json:"team_name"`
+}
+
+// SetTeamParentRequest moves TeamName under ParentTeamName; an empty parent
+// makes it a top-level team.
+type SetTeamParentRequest struct {
+	TeamName       string `json:"team_name"`
+	ParentTeamName string `json:"parent_team_name"`
+}
+
+type TeamTreeNode struct {
+	TeamName       string         `json:"team_name"`
+	ParentTeamName string         `json:"parent_team_name,omitempty"`
+	Members        int            `json:"members"`
+	Children       []TeamTreeNode `json:"children"`
+}
+
+type TeamTreeResponse struct {
+	Teams []TeamTreeNode `json:"teams"`
+}
+
+type TeamAuditEvent struct {
+	Action    string    `json:"action"`
+	TeamName  string    `json:"team_name"`
+	UserID    string    `json:"user_id,omitempty"`
+	OldValue  string    `json:"old_value,omitempty"`
+	NewValue  string    `json:"new_value,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type TeamAuditLogResponse struct {
+	TeamName string           `json:"team_name"`
+	Events   []TeamAuditEvent `json:"events"`
+}
+
+// TeamSyncRequest is the complete desired state of a team. Members missing
+// from the list are deactivated, not deleted.
+type TeamSyncRequest struct {
+	Team
+	DryRun bool `json:"dry_run"`
+}
+
+type TeamMemberUpdate struct {
+	UserID string     `json:"user_id"`
+	Before TeamMember `json:"before"`
+	After  TeamMember `json:"after"`
+}
+
+type TeamMemberMove struct {
+	TeamMember
+	FromTeam string `json:"from_team"`
+}
+
+// TeamSyncDiff is what a sync changes. With DryRun set nothing was written.
+type TeamSyncDiff struct {
+	TeamName    string             `json:"team_name"`
+	DryRun      bool               `json:"dry_run"`
+	TeamCreated bool               `json:"team_created"`
+	Added       []TeamMember       `json:"added"`
+	Updated     []TeamMemberUpdate `json:"updated"`
+	Moved       []TeamMemberMove   `json:"moved"`
+	Deactivated []string           `json:"deactivated"`
+	Unchanged   int                `json:"unchanged"`
+}
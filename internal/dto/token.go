@@ -0,0 +1,10 @@
+package dto
+
+import "time"
+
+type CreatedToken struct {
+	Name      string     `json:"name"`
+	Token     string     `json:"token"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
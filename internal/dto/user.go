@@ -1,7 +1,5 @@
 package dto
 
-import "github.com/google/uuid"
-
 type User struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
@@ -19,13 +17,74 @@ type SetUserActiveResponse struct {
 }
 
 type CreateUserRequest struct {
-	UserID   string    `json:"user_id"`
-	Name     string    `json:"username"`
-	TeamID   uuid.UUID `json:"team_id"`
-	IsActive bool      `json:"is_active"`
+	UserID   string `json:"user_id"`
+	Name     string `json:"username"`
+	TeamName string `json:"team_name"`
+	IsActive bool   `json:"is_active"`
+}
+
+type UserMembership struct {
+	TeamName  string `json:"team_name"`
+	IsPrimary bool   `json:"is_primary"`
+	IsActive  bool   `json:"is_active"`
+}
+
+type UserMembershipsResponse struct {
+	UserID string           `json:"user_id"`
+	Teams  []UserMembership `json:"teams"`
+}
+
+// UserDetails is a user together with their teams, current review load and
+// the pull requests they authored.
+type UserDetails struct {
+	User
+	Teams       []UserMembership      `json:"teams"`
+	OpenReviews int                   `json:"open_reviews"`
+	AuthoredPRs []PullRequestShortDTO `json:"authored_pull_requests"`
+}
+
+const (
+	DefaultUsersPageLimit = 50
+	MaxUsersPageLimit     = 500
+)
+
+// ListUsersRequest filters users by team and active state. Empty TeamName
+// and nil IsActive match everything.
+type ListUsersRequest struct {
+	TeamName string
+	IsActive *bool
+	Limit    int
+	Offset   int
+}
+
+type ListUsersResponse struct {
+	Users  []User `json:"users"`
+	Total  int64  `json:"total"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
 }
 
 type GetReviewPRsResponse struct {
 	UserID       string                `json:"user_id"`
 	PullRequests []PullRequestShortDTO `json:"pull_requests"`
 }
+
+type NotificationSettings struct {
+	UserID          string `json:"user_id"`
+	Email           string `json:"email"`
+	EmailOptOut     bool   `json:"email_opt_out"`
+	DigestFrequency string `json:"digest_frequency"`
+}
+
+type UserSkills struct {
+	UserID string   `json:"user_id"`
+	Skills []string `json:"skills"`
+}
+
+// UserReviewLimit is the user's cap on concurrent reviews of OPEN pull
+// requests; a nil MaxOpenReviews removes the cap.
+type UserReviewLimit struct {
+	UserID         string `json:"user_id"`
+	MaxOpenReviews *int   `json:"max_open_reviews"`
+	OpenReviews    int    `json:"open_reviews"`
+}
@@ -0,0 +1,408 @@
+package dto
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	MaxIDLength   = 64
+	MaxNameLength = 255
+	MaxListLength = 1000
+)
+
+// IDs are used in URLs, CSV files and log lines, so they are restricted to
+// characters that need no quoting anywhere.
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:#/@-]*$`)
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every invalid field of a request, not only the
+// first one.
+type ValidationError struct {
+	Details []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Details))
+	for i, d := range e.Details {
+		parts[i] = d.Field + ": " + d.Message
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// Validatable is implemented by request DTOs; handlers call Validate after
+// decoding the body.
+type Validatable interface {
+	Validate() error
+}
+
+func NewFieldError(field, format string, args ...any) *ValidationError {
+	return &ValidationError{Details: []FieldError{{Field: field, Message: fmt.Sprintf(format, args...)}}}
+}
+
+type validator struct {
+	details []FieldError
+}
+
+func (v *validator) add(field, format string, args ...any) {
+	v.details = append(v.details, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) check(ok bool, field, format string, args ...any) {
+	if !ok {
+		v.add(field, format, args...)
+	}
+}
+
+func (v *validator) required(field, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		v.add(field, "is required")
+		return false
+	}
+	return true
+}
+
+func (v *validator) maxLength(field, value string, limit int) {
+	if utf8.RuneCountInString(value) > limit {
+		v.add(field, "must be at most %d characters", limit)
+	}
+}
+
+func (v *validator) id(field, value string) {
+	if !v.required(field, value) {
+		return
+	}
+	switch {
+	case len(value) > MaxIDLength:
+		v.add(field, "must be at most %d characters", MaxIDLength)
+	case !idPattern.MatchString(value):
+		v.add(field, "must start with a letter or digit and contain only letters, digits and . _ : # / @ -")
+	}
+}
+
+func (v *validator) optionalID(field, value string) {
+	if value != "" {
+		v.id(field, value)
+	}
+}
+
+func (v *validator) name(field, value string) {
+	if v.required(field, value) {
+		v.maxLength(field, value, MaxNameLength)
+	}
+}
+
+func (v *validator) listLength(field string, n int) {
+	if n > MaxListLength {
+		v.add(field, "must have at most %d items", MaxListLength)
+	}
+}
+
+// ids checks every element as an ID and reports repeated values.
+func (v *validator) ids(field string, values []string) {
+	v.listLength(field, len(values))
+	seen := make(map[string]bool, len(values))
+	for i, value := range values {
+		elem := fmt.Sprintf("%s[%d]", field, i)
+		v.id(elem, value)
+		if seen[value] {
+			v.add(elem, "duplicate value %q", value)
+		}
+		seen[value] = true
+	}
+}
+
+func (v *validator) nonEmptyItems(field string, values []string) {
+	v.listLength(field, len(values))
+	for i, value := range values {
+		elem := fmt.Sprintf("%s[%d]", field, i)
+		if v.required(elem, value) {
+			v.maxLength(elem, value, MaxNameLength)
+		}
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.details) == 0 {
+		return nil
+	}
+	return &ValidationError{Details: v.details}
+}
+
+func (r *Team) Validate() error {
+	v := &validator{}
+	v.name("team_name", r.TeamName)
+	v.listLength("members", len(r.Members))
+
+	seen := make(map[string]bool, len(r.Members))
+	for i, m := range r.Members {
+		prefix := fmt.Sprintf("members[%d]", i)
+		v.id(prefix+".user_id", m.UserID)
+		v.name(prefix+".username", m.Username)
+		if seen[m.UserID] {
+			v.add(prefix+".user_id", "duplicate member %q", m.UserID)
+		}
+		seen[m.UserID] = true
+	}
+	return v.err()
+}
+
+func (r *AddTeamMemberRequest) Validate() error {
+	v := &validator{}
+	v.name("team_name", r.TeamName)
+	v.id("user_id", r.UserID)
+	v.name("username", r.Username)
+	return v.err()
+}
+
+func (r *MoveTeamMemberRequest) Validate() error {
+	v := &validator{}
+	v.id("user_id", r.UserID)
+	v.name("team_name", r.TeamName)
+	return v.err()
+}
+
+func (r *RemoveTeamMemberRequest) Validate() error {
+	v := &validator{}
+	v.name("team_name", r.TeamName)
+	v.id("user_id", r.UserID)
+	return v.err()
+}
+
+func (r *TeamMembershipRequest) Validate() error {
+	v := &validator{}
+	v.name("team_name", r.TeamName)
+	v.id("user_id", r.UserID)
+	return v.err()
+}
+
+func (r *RemoveMembershipRequest) Validate() error {
+	v := &validator{}
+	v.name("team_name", r.TeamName)
+	v.id("user_id", r.UserID)
+	return v.err()
+}
+
+func (r *RenameTeamRequest) Validate() error {
+	v := &validator{}
+	v.name("team_name", r.TeamName)
+	v.name("new_team_name", r.NewTeamName)
+	return v.err()
+}
+
+func (r *DeleteTeamRequest) Validate() error {
+	v := &validator{}
+	v.name("team_name", r.TeamName)
+	return v.err()
+}
+
+func (r *SetTeamParentRequest) Validate() error {
+	v := &validator{}
+	v.name("team_name", r.TeamName)
+	if r.ParentTeamName != "" {
+		v.name("parent_team_name", r.ParentTeamName)
+	}
+	return v.err()
+}
+
+func (r *DeactivateTeamUsersRequest) Validate() error {
+	v := &validator{}
+	v.name("team_name", r.TeamName)
+	return v.err()
+}
+
+func (r *SetTeamWebhookRequest) Validate() error {
+	v := &validator{}
+	v.name("team_name", r.TeamName)
+	v.maxLength("webhook_url", r.WebhookURL, 2048)
+	return v.err()
+}
+
+func (r *TeamSLA) Validate() error {
+	v := &validator{}
+	v.name("team_name", r.TeamName)
+	return v.err()
+}
+
+func (r *TeamRotation) Validate() error {
+	v := &validator{}
+	v.name("team_name", r.TeamName)
+	return v.err()
+}
+
+func (r *ImportOwnershipRulesRequest) Validate() error {
+	v := &validator{}
+	v.name("team_name", r.TeamName)
+	return v.err()
+}
+
+func (r *AddOwnershipRuleRequest) Validate() error {
+	v := &validator{}
+	v.name("team_name", r.TeamName)
+	if v.required("pattern", r.Pattern) {
+		v.maxLength("pattern", r.Pattern, MaxNameLength)
+	}
+	if len(r.Owners) == 0 {
+		v.add("owners", "is required")
+	}
+	v.ids("owners", r.Owners)
+	return v.err()
+}
+
+func (r *DeleteOwnershipRuleRequest) Validate() error {
+	v := &validator{}
+	v.name("team_name", r.TeamName)
+	v.required("rule_id", r.RuleID)
+	return v.err()
+}
+
+func (r *CreateUserRequest) Validate() error {
+	v := &validator{}
+	v.id("user_id", r.UserID)
+	v.name("username", r.Name)
+	v.name("team_name", r.TeamName)
+	return v.err()
+}
+
+func (r *ListUsersRequest) Validate() error {
+	v := &validator{}
+	if r.TeamName != "" {
+		v.maxLength("team_name", r.TeamName, MaxNameLength)
+	}
+	v.check(r.Limit >= 1 && r.Limit <= MaxUsersPageLimit, "limit", "must be between 1 and %d", MaxUsersPageLimit)
+	v.check(r.Offset >= 0, "offset", "must not be negative")
+	return v.err()
+}
+
+func (r *SetUserActiveRequest) Validate() error {
+	v := &validator{}
+	v.id("user_id", r.UserID)
+	return v.err()
+}
+
+func (r *NotificationSettings) Validate() error {
+	v := &validator{}
+	v.id("user_id", r.UserID)
+	v.maxLength("email", r.Email, MaxNameLength)
+	return v.err()
+}
+
+func (r *UserSkills) Validate() error {
+	v := &validator{}
+	v.id("user_id", r.UserID)
+	v.nonEmptyItems("skills", r.Skills)
+	return v.err()
+}
+
+func (r *UserReviewLimit) Validate() error {
+	v := &validator{}
+	v.id("user_id", r.UserID)
+	return v.err()
+}
+
+func (r *CreatePRRequest) Validate() error {
+	v := &validator{}
+	r.validate(v)
+	return v.err()
+}
+
+func (r *CreatePRRequest) validate(v *validator) {
+	v.id("pull_request_id", r.PullRequestID)
+	v.name("pull_request_name", r.PullRequestName)
+	v.id("author_id", r.AuthorID)
+	v.nonEmptyItems("changed_files", r.ChangedFiles)
+	v.nonEmptyItems("labels", r.Labels)
+}
+
+// Validate checks an imported row with the CreatePRRequest rules and its
+// reviewers as IDs, so imported data cannot hold IDs the API would reject.
+func (r *ImportPullRequest) Validate() error {
+	v := &validator{}
+	(&CreatePRRequest{PullRequestID: r.PullRequestID, PullRequestName: r.PullRequestName, AuthorID: r.AuthorID}).validate(v)
+	for i, id := range r.Reviewers {
+		v.id(fmt.Sprintf("reviewers[%d]", i), id)
+	}
+	return v.err()
+}
+
+func (r *ReplayPRRequest) Validate() error {
+	v := &validator{}
+	r.CreatePRRequest.validate(v)
+	v.check(r.Seed != nil, "seed", "is required")
+	return v.err()
+}
+
+func (r *PreviewReviewersRequest) Validate() error {
+	v := &validator{}
+	v.optionalID("pull_request_id", r.PullRequestID)
+	v.id("author_id", r.AuthorID)
+	v.nonEmptyItems("changed_files", r.ChangedFiles)
+	v.nonEmptyItems("labels", r.Labels)
+	return v.err()
+}
+
+func (r *MergePRRequest) Validate() error {
+	v := &validator{}
+	v.id("pull_request_id", r.PullRequestID)
+	return v.err()
+}
+
+func (r *DeletePRRequest) Validate() error {
+	v := &validator{}
+	v.id("pull_request_id", r.PullRequestID)
+	return v.err()
+}
+
+func (r *ReassignReviewerRequest) Validate() error {
+	v := &validator{}
+	v.id("pull_request_id", r.PullRequestID)
+	v.id("old_user_id", r.OldUserID)
+	return v.err()
+}
+
+func (r *AddReviewerRequest) Validate() error {
+	v := &validator{}
+	v.id("pull_request_id", r.PullRequestID)
+	v.id("user_id", r.UserID)
+	return v.err()
+}
+
+func (r *RemoveReviewerRequest) Validate() error {
+	v := &validator{}
+	v.id("pull_request_id", r.PullRequestID)
+	v.id("user_id", r.UserID)
+	return v.err()
+}
+
+func (r *PinReviewerRequest) Validate() error {
+	v := &validator{}
+	v.id("pull_request_id", r.PullRequestID)
+	v.id("user_id", r.UserID)
+	return v.err()
+}
+
+func (r *PRLabels) Validate() error {
+	v := &validator{}
+	v.id("pull_request_id", r.PullRequestID)
+	v.nonEmptyItems("labels", r.Labels)
+	return v.err()
+}
+
+func (r *RestoreDeletedRequest) Validate() error {
+	v := &validator{}
+	v.required("entity", r.Entity)
+	v.id("id", r.ID)
+	return v.err()
+}
+
+func (r *JobNameRequest) Validate() error {
+	v := &validator{}
+	v.required("name", r.Name)
+	return v.err()
+}
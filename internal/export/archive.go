@@ -0,0 +1,76 @@
+package export
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"io"
+	"time"
+)
+
+const (
+	ManifestName = "manifest.json"
+	// SnapshotVersion is bumped when the archive layout changes.
+	SnapshotVersion = 2
+)
+
+// FileName is the archive entry holding an entity's NDJSON rows.
+func FileName(entity string) string {
+	return entity + ".ndjson"
+}
+
+// ArchiveWriter writes a gzipped tar. Tar needs every entry's size up front,
+// so callers spool entities to temporary files before adding them.
+type ArchiveWriter struct {
+	gz *gzip.Writer
+	tw *tar.Writer
+}
+
+func NewArchiveWriter(w io.Writer) *ArchiveWriter {
+	gz := gzip.NewWriter(w)
+	return &ArchiveWriter{gz: gz, tw: tar.NewWriter(gz)}
+}
+
+func (a *ArchiveWriter) Add(name string, size int64, modTime time.Time, r io.Reader) error {
+	err := a.tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    size,
+		ModTime: modTime,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(a.tw, r)
+	return err
+}
+
+func (a *ArchiveWriter) Close() error {
+	return errors.Join(a.tw.Close(), a.gz.Close())
+}
+
+type ArchiveReader struct {
+	tr *tar.Reader
+}
+
+func NewArchiveReader(r io.Reader) (*ArchiveReader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return &ArchiveReader{tr: tar.NewReader(gz)}, nil
+}
+
+// Next returns the next regular file in the archive, or io.EOF at the end.
+// The returned reader is valid until the following call.
+func (a *ArchiveReader) Next() (string, io.Reader, error) {
+	for {
+		hdr, err := a.tr.Next()
+		if err != nil {
+			return "", nil, err
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			return hdr.Name, a.tr, nil
+		}
+	}
+}
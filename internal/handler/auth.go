@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/mink0ff/pr_service/internal/service"
+)
+
+// RequireToken rejects requests without a valid "Authorization: Bearer"
+// API token.
+func RequireToken(tokenService service.TokenService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok {
+				status, errResp := MapError(service.ErrUnauthorized)
+				writeJSON(w, status, errResp)
+				return
+			}
+
+			if err := tokenService.Authenticate(r.Context(), strings.TrimSpace(token)); err != nil {
+				status, errResp := MapError(err)
+				writeJSON(w, status, errResp)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/mink0ff/pr_service/internal/dto"
+)
+
+const maxBodyBytes = 1 << 20
+
+// decodeJSON decodes the body into dst, rejecting unknown fields and
+// trailing data, then validates dst. On failure it writes the error
+// response and returns false.
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(dst)
+	if err == nil && dec.More() {
+		err = errors.New("request body must contain a single JSON object")
+	}
+	if err != nil {
+		writeDecodeError(w, err)
+		return false
+	}
+
+	return validate(w, dst)
+}
+
+func validate(w http.ResponseWriter, dst any) bool {
+	v, ok := dst.(dto.Validatable)
+	if !ok {
+		return true
+	}
+	if err := v.Validate(); err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return false
+	}
+	return true
+}
+
+func writeDecodeError(w http.ResponseWriter, err error) {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var maxErr *http.MaxBytesError
+
+	switch {
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		err = dto.NewFieldError(typeErr.Field, "must be %s", typeErr.Type.String())
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json has no typed error for unknown fields.
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		err = dto.NewFieldError(field, "unknown field")
+	case errors.As(err, &maxErr):
+		writeJSON(w, http.StatusRequestEntityTooLarge, ErrorResponse{
+			Code:    "BODY_TOO_LARGE",
+			Message: fmt.Sprintf("request body must be at most %d bytes", maxErr.Limit),
+		})
+		return
+	case errors.Is(err, io.EOF):
+		err = errors.New("request body is empty")
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		err = errors.New("request body is not valid JSON")
+	}
+
+	var vErr *dto.ValidationError
+	if errors.As(err, &vErr) {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+	writeJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_JSON", Message: err.Error()})
+}
+
+// requireQuery returns the query parameter or writes a validation error
+// naming it.
+func requireQuery(w http.ResponseWriter, r *http.Request, name string) (string, bool) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		status, errResp := MapError(dto.NewFieldError(name, "query parameter is required"))
+		writeJSON(w, status, errResp)
+		return "", false
+	}
+	return value, true
+}
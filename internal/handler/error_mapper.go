@@ -2,43 +2,50 @@ package handler
 
 import (
 	"errors"
+	"log"
 	"net/http"
 
+	"github.com/google/uuid"
+	"github.com/mink0ff/pr_service/internal/dto"
 	svc "github.com/mink0ff/pr_service/internal/service"
 )
 
 type ErrorResponse struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
+	// Details is a list of dto.FieldError for VALIDATION_FAILED and an
+	// object with error-specific data otherwise.
+	Details any `json:"details,omitempty"`
+	// CorrelationID is only set for internal errors; the same ID is in the
+	// server log next to the actual cause.
+	CorrelationID string `json:"correlation_id,omitempty"`
 }
 
 func MapError(err error) (int, ErrorResponse) {
-	switch {
-	case errors.Is(err, svc.ErrTeamExists):
-		return http.StatusConflict, ErrorResponse{Code: "TEAM_EXISTS", Message: err.Error()}
-
-	case errors.Is(err, svc.ErrTeamNotFound):
-		return http.StatusNotFound, ErrorResponse{Code: "TEAM_NOT_FOUND", Message: err.Error()}
-
-	case errors.Is(err, svc.ErrUserNotFound):
-		return http.StatusNotFound, ErrorResponse{Code: "USER_NOT_FOUND", Message: err.Error()}
-
-	case errors.Is(err, svc.ErrPRExists):
-		return http.StatusConflict, ErrorResponse{Code: "PR_EXISTS", Message: err.Error()}
-
-	case errors.Is(err, svc.ErrPRNotFound):
-		return http.StatusNotFound, ErrorResponse{Code: "PR_NOT_FOUND", Message: err.Error()}
+	var validationErr *dto.ValidationError
+	if errors.As(err, &validationErr) {
+		return http.StatusBadRequest, ErrorResponse{Code: "VALIDATION_FAILED", Message: "request validation failed", Details: validationErr.Details}
+	}
 
-	case errors.Is(err, svc.ErrPRMerged):
-		return http.StatusConflict, ErrorResponse{Code: "PR_MERGED", Message: err.Error()}
+	if domainErr, ok := svc.AsError(err); ok {
+		resp := ErrorResponse{Code: domainErr.Code, Message: err.Error()}
+		if domainErr.Cause() != nil {
+			// The cause is usually a database error; keep it out of the response.
+			log.Printf("Request failed with %s: %v", domainErr.Code, err)
+			resp.Message = domainErr.Message
+		}
+		if len(domainErr.Details) > 0 {
+			resp.Details = domainErr.Details
+		}
+		return domainErr.Status, resp
+	}
 
-	case errors.Is(err, svc.ErrReviewerNotAssigned):
-		return http.StatusBadRequest, ErrorResponse{Code: "NOT_ASSIGNED", Message: err.Error()}
+	correlationID := uuid.NewString()
+	log.Printf("Internal error %s: %v", correlationID, err)
 
-	case errors.Is(err, svc.ErrNoCandidate):
-		return http.StatusConflict, ErrorResponse{Code: "NO_CANDIDATE", Message: err.Error()}
+	return http.StatusInternalServerError, ErrorResponse{
+		Code:          "INTERNAL_ERROR",
+		Message:       "internal server error",
+		CorrelationID: correlationID,
 	}
-
-	return http.StatusInternalServerError,
-		ErrorResponse{Code: "INTERNAL_ERROR", Message: "internal server error"}
 }
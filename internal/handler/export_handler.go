@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mink0ff/pr_service/internal/export"
+	"github.com/mink0ff/pr_service/internal/service"
+)
+
+type ExportHandler struct {
+	exportService service.ExportService
+}
+
+func NewExportHandler(exportService service.ExportService) *ExportHandler {
+	return &ExportHandler{exportService: exportService}
+}
+
+// Export streams one entity as NDJSON or CSV, or every entity as a snapshot
+// archive when format=snapshot.
+func (h *ExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	// Exports may take longer than the server's write timeout.
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = export.FormatNDJSON
+	}
+
+	var err error
+	var out *downloadWriter
+	if format == export.FormatSnapshot {
+		filename := fmt.Sprintf("pr-snapshot-%s.tar.gz", time.Now().UTC().Format("20060102T150405Z"))
+		out = &downloadWriter{w: w, contentType: "application/gzip", filename: filename}
+		_, err = h.exportService.Snapshot(r.Context(), out)
+	} else {
+		entity := r.URL.Query().Get("entity")
+		contentType := "application/x-ndjson"
+		if format == export.FormatCSV {
+			contentType = "text/csv"
+		}
+		out = &downloadWriter{w: w, contentType: contentType, filename: entity + "." + format}
+		err = h.exportService.Export(r.Context(), entity, format, out)
+	}
+
+	if err != nil {
+		if out.started {
+			// The status is already sent; the client sees a truncated body.
+			log.Printf("Export aborted mid-stream: %v", err)
+			return
+		}
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+	}
+}
+
+func (h *ExportHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+	_ = rc.SetReadDeadline(time.Time{})
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	report, err := h.exportService.Restore(r.Context(), r.Body)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+// downloadWriter sends the download headers on the first write, so an error
+// returned before any output can still be answered with a JSON error.
+type downloadWriter struct {
+	w           http.ResponseWriter
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (d *downloadWriter) Write(p []byte) (int, error) {
+	if !d.started {
+		d.started = true
+		d.w.Header().Set("Content-Type", d.contentType)
+		d.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", d.filename))
+		d.w.WriteHeader(http.StatusOK)
+	}
+	return d.w.Write(p)
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/primport"
+	"github.com/mink0ff/pr_service/internal/service"
+)
+
+type ImportHandler struct {
+	importService service.ImportService
+}
+
+func NewImportHandler(importService service.ImportService) *ImportHandler {
+	return &ImportHandler{importService: importService}
+}
+
+// ImportPullRequests takes the format from the format query parameter or,
+// failing that, from the Content-Type: text/csv means CSV, anything else NDJSON.
+func (h *ImportHandler) ImportPullRequests(w http.ResponseWriter, r *http.Request) {
+	// Large files may take longer to upload than the server's timeouts.
+	rc := http.NewResponseController(w)
+	_ = rc.SetReadDeadline(time.Time{})
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = primport.FormatNDJSON
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "text/csv") {
+			format = primport.FormatCSV
+		}
+	}
+
+	dryRun := false
+	if raw := r.URL.Query().Get("dry_run"); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			status, errResp := MapError(dto.NewFieldError("dry_run", "must be a boolean"))
+			writeJSON(w, status, errResp)
+			return
+		}
+		dryRun = v
+	}
+
+	report, err := h.importService.ImportPullRequests(r.Context(), format, r.Body, dryRun)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	if len(report.Errors) > 0 {
+		writeJSON(w, http.StatusUnprocessableEntity, report)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
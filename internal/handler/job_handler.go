@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/jobs"
+	"github.com/mink0ff/pr_service/internal/service"
+)
+
+type JobHandler struct {
+	jobService jobs.Service
+}
+
+func NewJobHandler(jobService jobs.Service) *JobHandler {
+	return &JobHandler{jobService: jobService}
+}
+
+func (h *JobHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.jobService.ListJobs(r.Context())
+	if err != nil {
+		status, errResp := MapError(jobError(err))
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *JobHandler) ListJobRuns(w http.ResponseWriter, r *http.Request) {
+	name, ok := requireQuery(w, r, "name")
+	if !ok {
+		return
+	}
+
+	limit := 20
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 || n > 500 {
+			status, errResp := MapError(dto.NewFieldError("limit", "must be between 1 and 500"))
+			writeJSON(w, status, errResp)
+			return
+		}
+		limit = n
+	}
+
+	resp, err := h.jobService.ListJobRuns(r.Context(), name, limit)
+	if err != nil {
+		status, errResp := MapError(jobError(err))
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *JobHandler) TriggerJob(w http.ResponseWriter, r *http.Request) {
+	var req dto.JobNameRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	resp, err := h.jobService.TriggerJob(r.Context(), req.Name)
+	if err != nil {
+		status, errResp := MapError(jobError(err))
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, resp)
+}
+
+func (h *JobHandler) PauseJob(w http.ResponseWriter, r *http.Request) {
+	h.setPaused(w, r, true)
+}
+
+func (h *JobHandler) ResumeJob(w http.ResponseWriter, r *http.Request) {
+	h.setPaused(w, r, false)
+}
+
+func (h *JobHandler) setPaused(w http.ResponseWriter, r *http.Request, paused bool) {
+	var req dto.JobNameRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	resp, err := h.jobService.SetJobPaused(r.Context(), req.Name, paused)
+	if err != nil {
+		status, errResp := MapError(jobError(err))
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// jobError maps runner errors to their API errors.
+func jobError(err error) error {
+	if errors.Is(err, jobs.ErrJobNotFound) {
+		return service.ErrJobNotFound.Wrap(err)
+	}
+	return err
+}
@@ -18,8 +18,7 @@ func NewPRHandler(prService service.PRService) *PRHandler {
 
 func (h *PRHandler) CreatePR(w http.ResponseWriter, r *http.Request) {
 	var req dto.CreatePRRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
@@ -33,10 +32,44 @@ func (h *PRHandler) CreatePR(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusCreated, resp)
 }
 
+// ReplayPR is the admin-only way to create a pull request with a chosen
+// selection seed.
+func (h *PRHandler) ReplayPR(w http.ResponseWriter, r *http.Request) {
+	var req dto.ReplayPRRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	req.CreatePRRequest.Seed = req.Seed
+
+	resp, err := h.prService.CreatePR(r.Context(), &req.CreatePRRequest)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func (h *PRHandler) PreviewReviewers(w http.ResponseWriter, r *http.Request) {
+	var req dto.PreviewReviewersRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	resp, err := h.prService.PreviewReviewers(r.Context(), &req)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
 func (h *PRHandler) MergePR(w http.ResponseWriter, r *http.Request) {
 	var req dto.MergePRRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
@@ -50,10 +83,24 @@ func (h *PRHandler) MergePR(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, resp)
 }
 
+func (h *PRHandler) DeletePR(w http.ResponseWriter, r *http.Request) {
+	var req dto.DeletePRRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if err := h.prService.DeletePR(r.Context(), &req); err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"pull_request_id": req.PullRequestID, "deleted": true})
+}
+
 func (h *PRHandler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 	var req dto.ReassignReviewerRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
@@ -67,6 +114,118 @@ func (h *PRHandler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, resp)
 }
 
+func (h *PRHandler) AddReviewer(w http.ResponseWriter, r *http.Request) {
+	var req dto.AddReviewerRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	resp, err := h.prService.AddReviewer(r.Context(), &req)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *PRHandler) RemoveReviewer(w http.ResponseWriter, r *http.Request) {
+	var req dto.RemoveReviewerRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	resp, err := h.prService.RemoveReviewer(r.Context(), &req)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *PRHandler) PinReviewer(w http.ResponseWriter, r *http.Request) {
+	var req dto.PinReviewerRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	resp, err := h.prService.SetReviewerPinned(r.Context(), &req, true)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *PRHandler) UnpinReviewer(w http.ResponseWriter, r *http.Request) {
+	var req dto.PinReviewerRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	resp, err := h.prService.SetReviewerPinned(r.Context(), &req, false)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *PRHandler) GetLabels(w http.ResponseWriter, r *http.Request) {
+	prID, ok := requireQuery(w, r, "pull_request_id")
+	if !ok {
+		return
+	}
+
+	resp, err := h.prService.GetLabels(r.Context(), prID)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *PRHandler) AddLabels(w http.ResponseWriter, r *http.Request) {
+	var req dto.PRLabels
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	resp, err := h.prService.AddLabels(r.Context(), &req)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *PRHandler) RemoveLabels(w http.ResponseWriter, r *http.Request) {
+	var req dto.PRLabels
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	resp, err := h.prService.RemoveLabels(r.Context(), &req)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
 func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
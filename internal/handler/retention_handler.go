@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/service"
+)
+
+type RetentionHandler struct {
+	retentionService service.RetentionService
+}
+
+func NewRetentionHandler(retentionService service.RetentionService) *RetentionHandler {
+	return &RetentionHandler{retentionService: retentionService}
+}
+
+func (h *RetentionHandler) ListDeleted(w http.ResponseWriter, r *http.Request) {
+	entity, ok := requireQuery(w, r, "entity")
+	if !ok {
+		return
+	}
+
+	limit := 50
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 || n > 500 {
+			status, errResp := MapError(dto.NewFieldError("limit", "must be between 1 and 500"))
+			writeJSON(w, status, errResp)
+			return
+		}
+		limit = n
+	}
+
+	resp, err := h.retentionService.ListDeleted(r.Context(), entity, limit)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *RetentionHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	var req dto.RestoreDeletedRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	resp, err := h.retentionService.Restore(r.Context(), &req)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -4,30 +4,100 @@ import (
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/mink0ff/pr_service/internal/health"
+	"github.com/mink0ff/pr_service/internal/jobs"
 	"github.com/mink0ff/pr_service/internal/service"
 )
 
-func RegisterRoutes(r chi.Router, ts service.TeamService, us service.UserService, prs service.PRService, ss service.StatsService) {
+func RegisterRoutes(r chi.Router, ts service.TeamService, us service.UserService, prs service.PRService, ss service.StatsService, js jobs.Service, is service.ImportService, es service.ExportService, rs service.RetentionService, adminAuth func(http.Handler) http.Handler, checker *health.Checker) {
 	teamHandler := NewTeamHandler(ts)
 	r.Post("/team/add", teamHandler.CreateTeam)
+	r.Put("/team/sync", teamHandler.SyncTeam)
 	r.Get("/team/get", teamHandler.GetTeam)
 	r.Post("/team/deactivate_users", teamHandler.DeactivateTeamUsersHandler)
+	r.Post("/team/setWebhook", teamHandler.SetWebhook)
+	r.Get("/team/getSLA", teamHandler.GetSLA)
+	r.Post("/team/setSLA", teamHandler.SetSLA)
+	r.Get("/team/getRotation", teamHandler.GetRotation)
+	r.Post("/team/setRotation", teamHandler.SetRotation)
+	r.Get("/team/getOwnershipRules", teamHandler.GetOwnershipRules)
+	r.Post("/team/importOwnershipRules", teamHandler.ImportOwnershipRules)
+	r.Post("/team/addOwnershipRule", teamHandler.AddOwnershipRule)
+	r.Post("/team/deleteOwnershipRule", teamHandler.DeleteOwnershipRule)
+	r.Post("/team/addMember", teamHandler.AddMember)
+	r.Post("/team/moveMember", teamHandler.MoveMember)
+	r.Post("/team/removeMember", teamHandler.RemoveMember)
+	r.Post("/team/addMembership", teamHandler.AddMembership)
+	r.Post("/team/removeMembership", teamHandler.RemoveMembership)
+	r.Post("/team/rename", teamHandler.RenameTeam)
+	r.Post("/team/delete", teamHandler.DeleteTeam)
+	r.Post("/team/setParent", teamHandler.SetParent)
+	r.Get("/team/tree", teamHandler.GetTree)
+	r.Get("/team/getAuditLog", teamHandler.GetAuditLog)
 
 	userHandler := NewUserHandler(us)
+	r.Post("/users/add", userHandler.CreateUser)
+	r.Get("/users/get", userHandler.GetUser)
+	r.Get("/users/list", userHandler.ListUsers)
 	r.Post("/users/setIsActive", userHandler.SetActive)
 	r.Get("/users/getReview", userHandler.GetReviewPRs)
+	r.Get("/users/getNotificationSettings", userHandler.GetNotificationSettings)
+	r.Post("/users/setNotificationSettings", userHandler.SetNotificationSettings)
+	r.Get("/users/getSkills", userHandler.GetSkills)
+	r.Post("/users/addSkills", userHandler.AddSkills)
+	r.Post("/users/removeSkills", userHandler.RemoveSkills)
+	r.Get("/users/getReviewLimit", userHandler.GetReviewLimit)
+	r.Post("/users/setReviewLimit", userHandler.SetReviewLimit)
 
 	prHandler := NewPRHandler(prs)
 	r.Post("/pullRequest/create", prHandler.CreatePR)
+	r.Post("/pullRequest/previewReviewers", prHandler.PreviewReviewers)
 	r.Post("/pullRequest/merge", prHandler.MergePR)
+	r.Post("/pullRequest/delete", prHandler.DeletePR)
 	r.Post("/pullRequest/reassign", prHandler.ReassignReviewer)
+	r.Post("/pullRequest/addReviewer", prHandler.AddReviewer)
+	r.Post("/pullRequest/removeReviewer", prHandler.RemoveReviewer)
+	r.Post("/pullRequest/pinReviewer", prHandler.PinReviewer)
+	r.Post("/pullRequest/unpinReviewer", prHandler.UnpinReviewer)
+	r.Get("/pullRequest/getLabels", prHandler.GetLabels)
+	r.Post("/pullRequest/addLabels", prHandler.AddLabels)
+	r.Post("/pullRequest/removeLabels", prHandler.RemoveLabels)
+
+	importHandler := NewImportHandler(is)
+	r.Post("/pullRequest/import", importHandler.ImportPullRequests)
 
 	statsHandler := NewStatsHandler(ss)
 	r.Get("/stats/reviewers", statsHandler.GetReviewerStatsHandler)
+	r.Get("/stats/teams", statsHandler.GetTeamStatsHandler)
+
+	// Admin routes are served only behind adminAuth. Without it they are not
+	// mounted at all rather than left open.
+	if adminAuth != nil {
+		r.Group(func(r chi.Router) {
+			r.Use(adminAuth)
+
+			jobHandler := NewJobHandler(js)
+			r.Get("/admin/jobs", jobHandler.ListJobs)
+			r.Get("/admin/jobs/runs", jobHandler.ListJobRuns)
+			r.Post("/admin/jobs/trigger", jobHandler.TriggerJob)
+			r.Post("/admin/jobs/pause", jobHandler.PauseJob)
+			r.Post("/admin/jobs/resume", jobHandler.ResumeJob)
+
+			r.Post("/admin/pullRequest/create", prHandler.ReplayPR)
+
+			exportHandler := NewExportHandler(es)
+			r.Get("/admin/export", exportHandler.Export)
+			r.Post("/admin/restore", exportHandler.Restore)
+
+			retentionHandler := NewRetentionHandler(rs)
+			r.Get("/admin/deleted", retentionHandler.ListDeleted)
+			r.Post("/admin/deleted/restore", retentionHandler.Restore)
+		})
+	}
 
-	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(`{"status":"ok"}`))
-	})
+	healthHandler := NewHealthHandler(checker)
+	r.Get("/livez", healthHandler.Livez)
+	r.Get("/readyz", healthHandler.Readyz)
+	// Kept for existing probes, same as /livez.
+	r.Get("/health", healthHandler.Livez)
 }
@@ -26,3 +26,16 @@ func (h *StatsHandler) GetReviewerStatsHandler(w http.ResponseWriter, r *http.Re
 
 	writeJSON(w, http.StatusOK, stats)
 }
+
+// GetTeamStatsHandler serves team activity rolled up the hierarchy, for one
+// subtree when team_name is given.
+func (h *StatsHandler) GetTeamStatsHandler(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.statsService.GetTeamStats(r.Context(), r.URL.Query().Get("team_name"))
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, stats)
+}
@@ -1,8 +1,10 @@
 package handler
 
 import (
-	"encoding/json"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/mink0ff/pr_service/internal/dto"
@@ -19,8 +21,7 @@ func NewTeamHandler(teamService service.TeamService) *TeamHandler {
 
 func (h *TeamHandler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 	var req dto.CreateTeamRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
@@ -34,12 +35,27 @@ func (h *TeamHandler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusCreated, resp)
 }
 
+func (h *TeamHandler) SyncTeam(w http.ResponseWriter, r *http.Request) {
+	var req dto.TeamSyncRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	diff, err := h.teamService.SyncTeam(r.Context(), &req)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, diff)
+}
+
 func (h *TeamHandler) GetTeam(w http.ResponseWriter, r *http.Request) {
 	teamName := chi.URLParam(r, "team_name")
 	if teamName == "" {
-		teamName = r.URL.Query().Get("team_name")
-		if teamName == "" {
-			http.Error(w, "team_name query is required", http.StatusBadRequest)
+		var ok bool
+		if teamName, ok = requireQuery(w, r, "team_name"); !ok {
 			return
 		}
 	}
@@ -56,8 +72,7 @@ func (h *TeamHandler) GetTeam(w http.ResponseWriter, r *http.Request) {
 
 func (h *TeamHandler) DeactivateTeamUsersHandler(w http.ResponseWriter, r *http.Request) {
 	var req dto.DeactivateTeamUsersRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
@@ -70,3 +85,326 @@ func (h *TeamHandler) DeactivateTeamUsersHandler(w http.ResponseWriter, r *http.
 
 	writeJSON(w, http.StatusOK, resp)
 }
+
+func (h *TeamHandler) SetWebhook(w http.ResponseWriter, r *http.Request) {
+	var req dto.SetTeamWebhookRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	resp, err := h.teamService.SetWebhook(r.Context(), &req)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *TeamHandler) GetSLA(w http.ResponseWriter, r *http.Request) {
+	teamName, ok := requireQuery(w, r, "team_name")
+	if !ok {
+		return
+	}
+
+	resp, err := h.teamService.GetSLA(r.Context(), teamName)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *TeamHandler) SetSLA(w http.ResponseWriter, r *http.Request) {
+	var req dto.TeamSLA
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	resp, err := h.teamService.SetSLA(r.Context(), &req)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *TeamHandler) GetRotation(w http.ResponseWriter, r *http.Request) {
+	teamName, ok := requireQuery(w, r, "team_name")
+	if !ok {
+		return
+	}
+
+	resp, err := h.teamService.GetRotation(r.Context(), teamName)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *TeamHandler) SetRotation(w http.ResponseWriter, r *http.Request) {
+	var req dto.TeamRotation
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	resp, err := h.teamService.SetRotation(r.Context(), &req)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *TeamHandler) GetOwnershipRules(w http.ResponseWriter, r *http.Request) {
+	teamName, ok := requireQuery(w, r, "team_name")
+	if !ok {
+		return
+	}
+
+	resp, err := h.teamService.GetOwnershipRules(r.Context(), teamName)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ImportOwnershipRules accepts either a JSON body or a raw CODEOWNERS file
+// sent as text/plain with the team in the team_name query parameter.
+func (h *TeamHandler) ImportOwnershipRules(w http.ResponseWriter, r *http.Request) {
+	var req dto.ImportOwnershipRulesRequest
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "text/plain") {
+		content, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_REQUEST_BODY", Message: err.Error()})
+			return
+		}
+		req.TeamName = r.URL.Query().Get("team_name")
+		req.Content = string(content)
+		if !validate(w, &req) {
+			return
+		}
+	} else if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	resp, err := h.teamService.ImportOwnershipRules(r.Context(), &req)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *TeamHandler) AddOwnershipRule(w http.ResponseWriter, r *http.Request) {
+	var req dto.AddOwnershipRuleRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	resp, err := h.teamService.AddOwnershipRule(r.Context(), &req)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func (h *TeamHandler) DeleteOwnershipRule(w http.ResponseWriter, r *http.Request) {
+	var req dto.DeleteOwnershipRuleRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	resp, err := h.teamService.DeleteOwnershipRule(r.Context(), &req)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *TeamHandler) AddMember(w http.ResponseWriter, r *http.Request) {
+	var req dto.AddTeamMemberRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	user, err := h.teamService.AddMember(r.Context(), &req)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"user": user})
+}
+
+func (h *TeamHandler) MoveMember(w http.ResponseWriter, r *http.Request) {
+	var req dto.MoveTeamMemberRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	resp, err := h.teamService.MoveMember(r.Context(), &req)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *TeamHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	var req dto.RemoveTeamMemberRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if err := h.teamService.RemoveMember(r.Context(), &req); err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"team_name": req.TeamName, "user_id": req.UserID, "removed": true})
+}
+
+func (h *TeamHandler) AddMembership(w http.ResponseWriter, r *http.Request) {
+	var req dto.TeamMembershipRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	resp, err := h.teamService.AddMembership(r.Context(), &req)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *TeamHandler) RemoveMembership(w http.ResponseWriter, r *http.Request) {
+	var req dto.RemoveMembershipRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	resp, err := h.teamService.RemoveMembership(r.Context(), &req)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *TeamHandler) RenameTeam(w http.ResponseWriter, r *http.Request) {
+	var req dto.RenameTeamRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	team, err := h.teamService.RenameTeam(r.Context(), &req)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"team": team})
+}
+
+func (h *TeamHandler) SetParent(w http.ResponseWriter, r *http.Request) {
+	var req dto.SetTeamParentRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	team, err := h.teamService.SetParent(r.Context(), &req)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"team": team})
+}
+
+// GetTree serves the whole team hierarchy, or the subtree of the optional
+// team_name query parameter.
+func (h *TeamHandler) GetTree(w http.ResponseWriter, r *http.Request) {
+	tree, err := h.teamService.GetTree(r.Context(), r.URL.Query().Get("team_name"))
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tree)
+}
+
+func (h *TeamHandler) DeleteTeam(w http.ResponseWriter, r *http.Request) {
+	var req dto.DeleteTeamRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if err := h.teamService.DeleteTeam(r.Context(), &req); err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"team_name": req.TeamName, "deleted": true})
+}
+
+func (h *TeamHandler) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	teamName, ok := requireQuery(w, r, "team_name")
+	if !ok {
+		return
+	}
+
+	limit := 100
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 || n > 1000 {
+			status, errResp := MapError(dto.NewFieldError("limit", "must be between 1 and 1000"))
+			writeJSON(w, status, errResp)
+			return
+		}
+		limit = n
+	}
+
+	resp, err := h.teamService.GetAuditLog(r.Context(), teamName, limit)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -1,8 +1,9 @@
 package handler
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/mink0ff/pr_service/internal/dto"
 	"github.com/mink0ff/pr_service/internal/service"
@@ -16,10 +17,88 @@ func NewUserHandler(userService service.UserService) *UserHandler {
 	return &UserHandler{userService: userService}
 }
 
+func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateUserRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	user, err := h.userService.CreateUser(r.Context(), &req)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"user": user})
+}
+
+func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireQuery(w, r, "user_id")
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.GetUser(r.Context(), userID)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := dto.ListUsersRequest{TeamName: query.Get("team_name"), Limit: dto.DefaultUsersPageLimit}
+
+	var details []dto.FieldError
+	if raw := query.Get("is_active"); raw != "" {
+		active, err := strconv.ParseBool(raw)
+		if err != nil {
+			details = append(details, dto.FieldError{Field: "is_active", Message: "must be true or false"})
+		}
+		req.IsActive = &active
+	}
+	intParams := []struct {
+		name string
+		dst  *int
+	}{{"limit", &req.Limit}, {"offset", &req.Offset}}
+	for _, p := range intParams {
+		if raw := query.Get(p.name); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil {
+				details = append(details, dto.FieldError{Field: p.name, Message: "must be an integer"})
+				continue
+			}
+			*p.dst = n
+		}
+	}
+
+	var validationErr *dto.ValidationError
+	if errors.As(req.Validate(), &validationErr) {
+		details = append(details, validationErr.Details...)
+	}
+	if len(details) > 0 {
+		status, errResp := MapError(&dto.ValidationError{Details: details})
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	resp, err := h.userService.ListUsers(r.Context(), &req)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
 func (h *UserHandler) SetActive(w http.ResponseWriter, r *http.Request) {
 	var req dto.SetUserActiveRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
@@ -34,9 +113,8 @@ func (h *UserHandler) SetActive(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *UserHandler) GetReviewPRs(w http.ResponseWriter, r *http.Request) {
-	userID := r.URL.Query().Get("user_id")
-	if userID == "" {
-		http.Error(w, "user_id query is required", http.StatusBadRequest)
+	userID, ok := requireQuery(w, r, "user_id")
+	if !ok {
 		return
 	}
 
@@ -52,3 +130,115 @@ func (h *UserHandler) GetReviewPRs(w http.ResponseWriter, r *http.Request) {
 		"pull_requests": prs,
 	})
 }
+
+func (h *UserHandler) GetNotificationSettings(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireQuery(w, r, "user_id")
+	if !ok {
+		return
+	}
+
+	settings, err := h.userService.GetNotificationSettings(r.Context(), userID)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, settings)
+}
+
+func (h *UserHandler) SetNotificationSettings(w http.ResponseWriter, r *http.Request) {
+	var req dto.NotificationSettings
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	settings, err := h.userService.SetNotificationSettings(r.Context(), &req)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, settings)
+}
+
+func (h *UserHandler) GetSkills(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireQuery(w, r, "user_id")
+	if !ok {
+		return
+	}
+
+	resp, err := h.userService.GetSkills(r.Context(), userID)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *UserHandler) AddSkills(w http.ResponseWriter, r *http.Request) {
+	var req dto.UserSkills
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	resp, err := h.userService.AddSkills(r.Context(), &req)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *UserHandler) RemoveSkills(w http.ResponseWriter, r *http.Request) {
+	var req dto.UserSkills
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	resp, err := h.userService.RemoveSkills(r.Context(), &req)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *UserHandler) GetReviewLimit(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireQuery(w, r, "user_id")
+	if !ok {
+		return
+	}
+
+	resp, err := h.userService.GetReviewLimit(r.Context(), userID)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *UserHandler) SetReviewLimit(w http.ResponseWriter, r *http.Request) {
+	var req dto.UserReviewLimit
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	resp, err := h.userService.SetReviewLimit(r.Context(), &req)
+	if err != nil {
+		status, errResp := MapError(err)
+		writeJSON(w, status, errResp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,41 @@
+package health
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mink0ff/pr_service/internal/repository/migrate"
+	"gorm.io/gorm"
+)
+
+func DatabaseCheck(db *gorm.DB) CheckFunc {
+	return func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	}
+}
+
+// MigrationsCheck fails while the schema is behind the migrations shipped
+// with this build, e.g. when serve runs with -migrate=false before
+// "prservice migrate up".
+func MigrationsCheck(db *gorm.DB, expected uint) CheckFunc {
+	return func(ctx context.Context) error {
+		status, err := migrate.CurrentVersion(ctx, db)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case !status.Applied:
+			return fmt.Errorf("no migrations applied, expected version %d", expected)
+		case status.Dirty:
+			return fmt.Errorf("migration %d is dirty", status.Version)
+		case status.Version != expected:
+			return fmt.Errorf("schema version is %d, expected %d", status.Version, expected)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,122 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mink0ff/pr_service/internal/dto"
+)
+
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+// Checker runs readiness checks concurrently, each under its own timeout,
+// and caches the report for a short while so frequent probes from several
+// sources don't each hit the database.
+type Checker struct {
+	timeout  time.Duration
+	cacheTTL time.Duration
+	checks   []check
+
+	shuttingDown atomic.Bool
+
+	mu       sync.Mutex
+	last     *dto.HealthReport
+	lastTime time.Time
+}
+
+func NewChecker(timeout, cacheTTL time.Duration) *Checker {
+	return &Checker{timeout: timeout, cacheTTL: cacheTTL}
+}
+
+// Add registers a check; it must be called before the checker is used.
+func (c *Checker) Add(name string, fn CheckFunc) {
+	c.checks = append(c.checks, check{name: name, fn: fn})
+}
+
+// SetShuttingDown makes every following report fail without running the
+// checks.
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+func (c *Checker) Check(ctx context.Context) dto.HealthReport {
+	if c.shuttingDown.Load() {
+		return dto.HealthReport{Status: dto.HealthShuttingDown, Checks: []dto.HealthCheckResult{}, CheckedAt: time.Now().UTC()}
+	}
+
+	// Holding the lock while checking lets concurrent probes share one run.
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.last != nil && time.Since(c.lastTime) < c.cacheTTL {
+		report := *c.last
+		report.Cached = true
+		return report
+	}
+
+	report := dto.HealthReport{
+		Status:    dto.HealthOK,
+		Checks:    make([]dto.HealthCheckResult, len(c.checks)),
+		CheckedAt: time.Now().UTC(),
+	}
+
+	// A probe that disconnects must not leave a failed report in the cache.
+	ctx = context.WithoutCancel(ctx)
+
+	var wg sync.WaitGroup
+	for i, chk := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Checks[i] = c.run(ctx, chk)
+		}()
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if result.Status != dto.HealthOK {
+			report.Status = dto.HealthFail
+		}
+	}
+
+	c.last = &report
+	c.lastTime = time.Now()
+	return report
+}
+
+// run gives up on a check at the timeout even if the check itself ignores
+// its context.
+func (c *Checker) run(ctx context.Context, chk check) dto.HealthCheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- chk.fn(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("timed out after %s", c.timeout)
+	}
+
+	result := dto.HealthCheckResult{
+		Name:      chk.name,
+		Status:    dto.HealthOK,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = dto.HealthFail
+		result.Error = err.Error()
+	}
+	return result
+}
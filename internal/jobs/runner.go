@@ -0,0 +1,404 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/models"
+	"github.com/mink0ff/pr_service/internal/repository"
+	"gorm.io/gorm"
+)
+
+var ErrJobNotFound = errors.New("job not found")
+
+// Service is the admin API of the runner behind /admin/jobs.
+type Service interface {
+	ListJobs(ctx context.Context) (*dto.ListJobsResponse, error)
+	ListJobRuns(ctx context.Context, name string, limit int) (*dto.ListJobRunsResponse, error)
+	TriggerJob(ctx context.Context, name string) (*dto.TriggerJobResponse, error)
+	SetJobPaused(ctx context.Context, name string, paused bool) (*dto.JobStatus, error)
+}
+
+var _ Service = (*Runner)(nil)
+
+type JobFunc func(ctx context.Context) error
+
+type job struct {
+	name     string
+	spec     string
+	schedule Schedule
+	run      JobFunc
+	nextRun  time.Time
+	// stopped is set when the schedule loop returns before the runner is
+	// stopped for any reason other than the schedule running out.
+	stopped bool
+}
+
+// Runner executes registered jobs on their schedules. Every execution takes a
+// Postgres session advisory lock keyed by the job name, so when several
+// replicas run the same Runner only one of them executes a given job at a time.
+type Runner struct {
+	db       *gorm.DB
+	repo     repository.JobRepository
+	instance string
+
+	mu    sync.Mutex
+	jobs  map[string]*job
+	order []string
+
+	ctx     context.Context
+	started bool
+	wg      sync.WaitGroup
+}
+
+func NewRunner(db *gorm.DB, repo repository.JobRepository) *Runner {
+	hostname, _ := os.Hostname()
+	return &Runner{
+		db:       db,
+		repo:     repo,
+		instance: fmt.Sprintf("%s/%d", hostname, os.Getpid()),
+		jobs:     map[string]*job{},
+		ctx:      context.Background(),
+	}
+}
+
+func (r *Runner) Register(name, spec string, run JobFunc) error {
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", name, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.jobs[name]; exists {
+		return fmt.Errorf("job %s already registered", name)
+	}
+	r.jobs[name] = &job{name: name, spec: spec, schedule: schedule, run: run}
+	r.order = append(r.order, name)
+	return nil
+}
+
+func (r *Runner) Start(ctx context.Context) error {
+	r.mu.Lock()
+	r.ctx = ctx
+	r.started = true
+	jobs := make([]*job, 0, len(r.order))
+	for _, name := range r.order {
+		jobs = append(jobs, r.jobs[name])
+	}
+	r.mu.Unlock()
+
+	for _, j := range jobs {
+		if err := r.repo.Register(ctx, models.Job{Name: j.name, Schedule: j.spec, UpdatedAt: time.Now()}); err != nil {
+			return err
+		}
+	}
+
+	for _, j := range jobs {
+		r.wg.Add(1)
+		go r.loop(ctx, j)
+	}
+
+	log.Printf("Job runner started with %d jobs on %s", len(jobs), r.instance)
+	return nil
+}
+
+// Wait blocks until every schedule loop and in-flight run has returned.
+func (r *Runner) Wait() {
+	r.wg.Wait()
+}
+
+// Check reports whether every schedule loop is still alive. It backs the
+// "jobs" readiness check.
+func (r *Runner) Check(_ context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.started {
+		if len(r.jobs) == 0 {
+			return nil
+		}
+		return errors.New("job runner not started")
+	}
+	if r.ctx.Err() != nil {
+		return errors.New("job runner stopped")
+	}
+
+	var stopped []string
+	for _, name := range r.order {
+		if r.jobs[name].stopped {
+			stopped = append(stopped, name)
+		}
+	}
+	if len(stopped) > 0 {
+		return fmt.Errorf("job loops stopped: %s", strings.Join(stopped, ", "))
+	}
+	return nil
+}
+
+func (r *Runner) loop(ctx context.Context, j *job) {
+	defer r.wg.Done()
+
+	exhausted := false
+	defer func() {
+		if p := recover(); p != nil {
+			log.Printf("Job %s loop panicked: %v", j.name, p)
+		}
+		if !exhausted && ctx.Err() == nil {
+			r.mu.Lock()
+			j.stopped = true
+			r.mu.Unlock()
+		}
+	}()
+
+	for {
+		next := j.schedule.Next(time.Now())
+		if next.IsZero() {
+			log.Printf("Job %s has no upcoming runs", j.name)
+			exhausted = true
+			return
+		}
+
+		r.mu.Lock()
+		j.nextRun = next
+		r.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			log.Printf("Job %s stopped", j.name)
+			return
+		case <-timer.C:
+		}
+
+		stored, err := r.repo.Get(ctx, j.name)
+		if err != nil {
+			continue
+		}
+		if stored != nil && stored.Paused {
+			log.Printf("Job %s is paused, skipping run at %s", j.name, next.Format(time.RFC3339))
+			continue
+		}
+
+		r.execute(ctx, j, models.JobTriggerSchedule, next)
+	}
+}
+
+func (r *Runner) execute(ctx context.Context, j *job, trigger models.JobTrigger, slot time.Time) {
+	key := lockKey(j.name)
+
+	err := r.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		var locked bool
+		if err := conn.Raw("SELECT pg_try_advisory_lock(?)", key).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			log.Printf("Job %s is running on another instance, skipping", j.name)
+			return nil
+		}
+		defer func() {
+			unlock := conn.WithContext(context.WithoutCancel(ctx))
+			if err := unlock.Exec("SELECT pg_advisory_unlock(?)", key).Error; err != nil {
+				log.Printf("Failed to release lock for job %s: %v", j.name, err)
+			}
+		}()
+
+		if trigger == models.JobTriggerSchedule && r.alreadyRan(ctx, j.name, slot) {
+			log.Printf("Job %s already ran for slot %s, skipping", j.name, slot.Format(time.RFC3339))
+			return nil
+		}
+
+		return r.record(ctx, j, trigger)
+	})
+
+	if err != nil {
+		log.Printf("Job %s could not be executed: %v", j.name, err)
+	}
+}
+
+// alreadyRan reports whether another replica has taken the slot. Only a
+// scheduled run that is running or succeeded counts: a manual trigger or a
+// failed run must not swallow the next scheduled execution.
+func (r *Runner) alreadyRan(ctx context.Context, name string, slot time.Time) bool {
+	ran, err := r.repo.HasScheduledRunSince(ctx, name, slot)
+	return err == nil && ran
+}
+
+func (r *Runner) record(ctx context.Context, j *job, trigger models.JobTrigger) error {
+	run := models.JobRun{
+		JobRunID:  uuid.New(),
+		JobName:   j.name,
+		Trigger:   trigger,
+		Status:    models.JobRunRunning,
+		Instance:  r.instance,
+		StartedAt: time.Now(),
+	}
+	if err := r.repo.StartRun(ctx, run); err != nil {
+		return err
+	}
+
+	log.Printf("Running job %s (%s)", j.name, trigger)
+	runErr := safeRun(ctx, j.run)
+
+	finished := time.Now()
+	run.FinishedAt = &finished
+	run.Status = models.JobRunSucceeded
+	if runErr != nil {
+		msg := runErr.Error()
+		run.Status = models.JobRunFailed
+		run.Error = &msg
+		log.Printf("Job %s failed: %v", j.name, runErr)
+	} else {
+		log.Printf("Job %s finished in %s", j.name, finished.Sub(run.StartedAt))
+	}
+
+	return r.repo.FinishRun(context.WithoutCancel(ctx), run)
+}
+
+func safeRun(ctx context.Context, fn JobFunc) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return fn(ctx)
+}
+
+func (r *Runner) ListJobs(ctx context.Context) (*dto.ListJobsResponse, error) {
+	r.mu.Lock()
+	names := append([]string(nil), r.order...)
+	r.mu.Unlock()
+
+	resp := &dto.ListJobsResponse{Jobs: make([]dto.JobStatus, 0, len(names))}
+	for _, name := range names {
+		status, err := r.status(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		resp.Jobs = append(resp.Jobs, *status)
+	}
+	return resp, nil
+}
+
+func (r *Runner) ListJobRuns(ctx context.Context, name string, limit int) (*dto.ListJobRunsResponse, error) {
+	if _, ok := r.lookup(name); !ok {
+		return nil, ErrJobNotFound
+	}
+
+	runs, err := r.repo.ListRuns(ctx, name, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.ListJobRunsResponse{JobName: name, Runs: make([]dto.JobRun, len(runs))}
+	for i := range runs {
+		resp.Runs[i] = mapJobRunToDTO(runs[i])
+	}
+	return resp, nil
+}
+
+func (r *Runner) TriggerJob(_ context.Context, name string) (*dto.TriggerJobResponse, error) {
+	j, ok := r.lookup(name)
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+
+	r.mu.Lock()
+	ctx := r.ctx
+	r.mu.Unlock()
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.execute(ctx, j, models.JobTriggerManual, time.Time{})
+	}()
+
+	log.Printf("Job %s triggered manually", name)
+	return &dto.TriggerJobResponse{Name: name, Triggered: true}, nil
+}
+
+func (r *Runner) SetJobPaused(ctx context.Context, name string, paused bool) (*dto.JobStatus, error) {
+	if _, ok := r.lookup(name); !ok {
+		return nil, ErrJobNotFound
+	}
+
+	updated, err := r.repo.SetPaused(ctx, name, paused)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrJobNotFound
+	}
+
+	return r.status(ctx, name)
+}
+
+func (r *Runner) lookup(name string) (*job, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	j, ok := r.jobs[name]
+	return j, ok
+}
+
+func (r *Runner) status(ctx context.Context, name string) (*dto.JobStatus, error) {
+	r.mu.Lock()
+	j := r.jobs[name]
+	status := &dto.JobStatus{Name: j.name, Schedule: j.spec}
+	if !j.nextRun.IsZero() {
+		next := j.nextRun
+		status.NextRunAt = &next
+	}
+	r.mu.Unlock()
+
+	stored, err := r.repo.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil {
+		status.Paused = stored.Paused
+	}
+
+	runs, err := r.repo.ListRuns(ctx, name, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) > 0 {
+		last := mapJobRunToDTO(runs[0])
+		status.LastRun = &last
+	}
+
+	return status, nil
+}
+
+func mapJobRunToDTO(run models.JobRun) dto.JobRun {
+	out := dto.JobRun{
+		JobRunID:   run.JobRunID.String(),
+		JobName:    run.JobName,
+		Trigger:    string(run.Trigger),
+		Status:     string(run.Status),
+		Instance:   run.Instance,
+		StartedAt:  run.StartedAt,
+		FinishedAt: run.FinishedAt,
+	}
+	if run.Error != nil {
+		out.Error = *run.Error
+	}
+	return out
+}
+
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte("pr_service/jobs/" + name))
+	return int64(h.Sum64())
+}
@@ -0,0 +1,71 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule_Next(t *testing.T) {
+	base := time.Date(2025, time.March, 7, 10, 30, 0, 0, time.UTC) // пятница
+
+	cases := []struct {
+		name string
+		spec string
+		want time.Time
+	}{
+		{"range", "0 9 * * 1-5", time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC)},
+		{"hour range", "0 12-14 * * *", time.Date(2025, time.March, 7, 12, 0, 0, 0, time.UTC)},
+		{"step", "*/20 * * * *", time.Date(2025, time.March, 7, 10, 40, 0, 0, time.UTC)},
+		{"range with step", "10-50/15 * * * *", time.Date(2025, time.March, 7, 10, 40, 0, 0, time.UTC)},
+		{"value with step", "5/25 * * * *", time.Date(2025, time.March, 7, 10, 55, 0, 0, time.UTC)},
+		{"list", "0 8,20 * * *", time.Date(2025, time.March, 7, 20, 0, 0, 0, time.UTC)},
+		{"list of ranges", "0 0 1-2,15-16 * *", time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC)},
+		{"weekday name", "0 9 * * MON", time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC)},
+		{"weekday name range", "0 9 * * Thu-Sat", time.Date(2025, time.March, 8, 9, 0, 0, 0, time.UTC)},
+		{"month name", "0 0 1 JUN *", time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)},
+		{"sunday as 7", "0 0 * * 7", time.Date(2025, time.March, 9, 0, 0, 0, 0, time.UTC)},
+		{"descriptor", "@monthly", time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"every", "@every 90s", base.Add(90 * time.Second)},
+
+		// Оба поля дня ограничены — подходит любое из них, берётся ближайшее.
+		{"dom or dow picks dow", "0 9 20 * SAT", time.Date(2025, time.March, 8, 9, 0, 0, 0, time.UTC)},
+		{"dom or dow picks dom", "0 9 8 * MON", time.Date(2025, time.March, 8, 9, 0, 0, 0, time.UTC)},
+		// Поле, начинающееся с "*", не ограничено, поэтому нужны оба условия:
+		// понедельник с нечётным числом — 17 марта, а не ближайшее нечётное 9-е.
+		{"star step dom and dow", "0 9 */2 * MON", time.Date(2025, time.March, 17, 9, 0, 0, 0, time.UTC)},
+		{"dom and star step dow", "0 9 10 * */2", time.Date(2025, time.April, 10, 9, 0, 0, 0, time.UTC)},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			schedule, err := ParseSchedule(c.spec)
+			if err != nil {
+				t.Fatalf("ParseSchedule(%q): %v", c.spec, err)
+			}
+			if got := schedule.Next(base); !got.Equal(c.want) {
+				t.Errorf("ParseSchedule(%q).Next = %s, want %s", c.spec, got, c.want)
+			}
+		})
+	}
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * *",
+		"60 * * * *",
+		"0 24 * * *",
+		"0 0 0 * *",
+		"0 9 * * 5-1",
+		"*/0 * * * *",
+		"0 9 * * MON-",
+		"0 9 * FOO *",
+		"0 9 * * JAN",
+		"@every 10ms",
+		"@every soon",
+	} {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("ParseSchedule(%q) succeeded, want error", spec)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"text/template"
+	"time"
+)
+
+const digestTextTemplate = `Hi {{.Username}},
+
+You have {{len .PullRequests}} pull request(s) waiting for your review:
+{{range .PullRequests}}
+  * {{.PullRequestName}} ({{.PullRequestID}}) by {{.AuthorID}}, open for {{age .OpenFor}}
+{{- end}}
+`
+
+const digestHTMLTemplate = `<p>Hi {{.Username}},</p>
+<p>You have {{len .PullRequests}} pull request(s) waiting for your review:</p>
+<table>
+  <tr><th align="left">Pull request</th><th align="left">Author</th><th align="left">Open for</th></tr>
+{{- range .PullRequests}}
+  <tr><td>{{.PullRequestName}} ({{.PullRequestID}})</td><td>{{.AuthorID}}</td><td>{{age .OpenFor}}</td></tr>
+{{- end}}
+</table>
+`
+
+type DigestPR struct {
+	PullRequestID   string
+	PullRequestName string
+	AuthorID        string
+	OpenFor         time.Duration
+}
+
+type DigestData struct {
+	Username     string
+	PullRequests []DigestPR
+}
+
+var (
+	digestText = template.Must(template.New("digest-text").
+			Funcs(template.FuncMap{"age": formatAge}).
+			Parse(digestTextTemplate))
+	digestHTML = htmltemplate.Must(htmltemplate.New("digest-html").
+			Funcs(htmltemplate.FuncMap{"age": formatAge}).
+			Parse(digestHTMLTemplate))
+)
+
+func RenderDigest(to string, data DigestData) (Message, error) {
+	var text, html bytes.Buffer
+	if err := digestText.Execute(&text, data); err != nil {
+		return Message{}, fmt.Errorf("render digest text: %w", err)
+	}
+	if err := digestHTML.Execute(&html, data); err != nil {
+		return Message{}, fmt.Errorf("render digest html: %w", err)
+	}
+
+	return Message{
+		To:      to,
+		Subject: fmt.Sprintf("%d pull request(s) waiting for your review", len(data.PullRequests)),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
+
+func formatAge(d time.Duration) string {
+	switch {
+	case d >= 48*time.Hour:
+		return fmt.Sprintf("%d days", int(d.Hours()/24))
+	case d >= time.Hour:
+		return fmt.Sprintf("%d hours", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%d minutes", int(d.Minutes()))
+	}
+}
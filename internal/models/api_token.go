@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIToken authorizes admin endpoints. Only the SHA-256 of the token is
+// stored; the token itself is shown once when it is created.
+type APIToken struct {
+	TokenID    uuid.UUID  `db:"token_id"`
+	Name       string     `db:"name"`
+	TokenHash  string     `db:"token_hash"`
+	CreatedAt  time.Time  `db:"created_at"`
+	ExpiresAt  *time.Time `db:"expires_at"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+}
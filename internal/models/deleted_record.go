@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// DeletedEntity names a soft-deletable table.
+type DeletedEntity string
+
+const (
+	DeletedTeams        DeletedEntity = "teams"
+	DeletedUsers        DeletedEntity = "users"
+	DeletedPullRequests DeletedEntity = "pull_requests"
+)
+
+var DeletedEntities = []DeletedEntity{DeletedTeams, DeletedUsers, DeletedPullRequests}
+
+// DeletedRecord is a soft-deleted team, user or pull request. ParentID is the
+// row it depends on: the parent team of a team, the primary team of a user
+// and the author of a pull request.
+type DeletedRecord struct {
+	Entity    DeletedEntity
+	ID        string    `db:"id"`
+	Name      string    `db:"name"`
+	ParentID  *string   `db:"parent_id"`
+	DeletedAt time.Time `db:"deleted_at"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type JobTrigger string
+
+const (
+	JobTriggerSchedule JobTrigger = "SCHEDULE"
+	JobTriggerManual   JobTrigger = "MANUAL"
+)
+
+type JobRunStatus string
+
+const (
+	JobRunRunning   JobRunStatus = "RUNNING"
+	JobRunSucceeded JobRunStatus = "SUCCEEDED"
+	JobRunFailed    JobRunStatus = "FAILED"
+)
+
+type Job struct {
+	Name      string    `db:"name"`
+	Schedule  string    `db:"schedule"`
+	Paused    bool      `db:"paused"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+type JobRun struct {
+	JobRunID   uuid.UUID    `db:"job_run_id"`
+	JobName    string       `db:"job_name"`
+	Trigger    JobTrigger   `db:"trigger"`
+	Status     JobRunStatus `db:"status"`
+	Error      *string      `db:"error"`
+	Instance   string       `db:"instance"`
+	StartedAt  time.Time    `db:"started_at"`
+	FinishedAt *time.Time   `db:"finished_at"`
+}
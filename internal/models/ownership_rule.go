@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OwnershipRule is a stored CODEOWNERS line of a team. Owners are kept in the
+// same space separated form they are written in, e.g. "@u1 @backend".
+type OwnershipRule struct {
+	RuleID    uuid.UUID `db:"rule_id"`
+	TeamID    uuid.UUID `db:"team_id"`
+	Position  int       `db:"position"`
+	Pattern   string    `db:"pattern"`
+	Owners    string    `db:"owners"`
+	CreatedAt time.Time `db:"created_at"`
+}
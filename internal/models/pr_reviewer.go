@@ -1,6 +1,22 @@
 package models
 
+import "time"
+
 type PRReviewer struct {
-	PullRequestID string `db:"pull_request_id"`
-	ReviewerID    string `db:"reviewer_id"`
+	PullRequestID string     `db:"pull_request_id"`
+	ReviewerID    string     `db:"reviewer_id"`
+	AssignedAt    time.Time  `db:"assigned_at"`
+	RemindedAt    *time.Time `db:"reminded_at"`
+	Pinned        bool       `db:"pinned"`
+}
+
+type StaleReview struct {
+	PullRequestID         string     `db:"pull_request_id"`
+	ReviewerID            string     `db:"reviewer_id"`
+	TeamID                string     `db:"team_id"`
+	AssignedAt            time.Time  `db:"assigned_at"`
+	RemindedAt            *time.Time `db:"reminded_at"`
+	SLARemindAfterHours   *int       `db:"sla_remind_after_hours"`
+	SLAReassignAfterHours *int       `db:"sla_reassign_after_hours"`
+	Pinned                bool       `db:"pinned"`
 }
@@ -2,6 +2,8 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type PRStatus string
@@ -18,4 +20,8 @@ type PullRequest struct {
 	Status          PRStatus   `db:"status"`
 	CreatedAt       time.Time  `db:"created_at"`
 	MergedAt        *time.Time `db:"merged_at"`
+	SelectionSeed   *int64     `db:"selection_seed"`
+	// DeletedAt is set for soft-deleted pull requests, which GORM queries
+	// skip.
+	DeletedAt gorm.DeletedAt `db:"deleted_at"`
 }
@@ -6,9 +6,30 @@ import (
 	"github.com/google/uuid"
 )
 
+type HistoryEventType string
+
+const (
+	EventAssigned      HistoryEventType = "ASSIGNED"
+	EventSLAReminder   HistoryEventType = "SLA_REMINDER"
+	EventSLAReassigned HistoryEventType = "SLA_REASSIGNED"
+
+	EventManuallyAssigned HistoryEventType = "MANUALLY_ASSIGNED"
+	EventRemoved          HistoryEventType = "REMOVED"
+	EventPinned           HistoryEventType = "PINNED"
+	EventUnpinned         HistoryEventType = "UNPINNED"
+)
+
+// AssignmentEvents are the events that count as a review assignment in
+// statistics and rotation.
+var AssignmentEvents = []HistoryEventType{EventAssigned, EventManuallyAssigned}
+
 type ReviewerAssignmentHistory struct {
-	AssigmentHistoryID uuid.UUID `db:"assigment_history_id"`
-	PrID               string    `db:"pr_id"`
-	UserID             string    `db:"user_id"`
-	CreatedAt          time.Time `db:"created_at"`
+	AssigmentHistoryID uuid.UUID        `db:"assigment_history_id"`
+	PrID               string           `db:"pr_id"`
+	UserID             string           `db:"user_id"`
+	EventType          HistoryEventType `db:"event_type"`
+	// SelectionSeed is the seed the reviewer was picked with; nil for manual
+	// and imported assignments.
+	SelectionSeed *int64    `db:"selection_seed"`
+	CreatedAt     time.Time `db:"created_at"`
 }
@@ -0,0 +1,11 @@
+package models
+
+type UserSkill struct {
+	UserID string `db:"user_id"`
+	Tag    string `db:"tag"`
+}
+
+type PRLabel struct {
+	PullRequestID string `db:"pull_request_id"`
+	Label         string `db:"label"`
+}
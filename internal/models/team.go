@@ -1,8 +1,27 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
 
 type Team struct {
-	TeamID   uuid.UUID `db:"team_id"`
-	TeamName string    `db:"team_name"`
+	TeamID     uuid.UUID `db:"team_id"`
+	TeamName   string    `db:"team_name"`
+	WebhookURL *string   `db:"webhook_url"`
+	// ParentTeamID places the team under a department or another team; nil
+	// for top-level teams.
+	ParentTeamID *uuid.UUID `db:"parent_team_id"`
+	// DeletedAt is set for soft-deleted teams, which GORM queries skip.
+	DeletedAt gorm.DeletedAt `db:"deleted_at"`
+}
+
+// TeamActivity is a team's own headcount and review load, without its
+// sub-teams.
+type TeamActivity struct {
+	TeamID        uuid.UUID `db:"team_id"`
+	Members       int64     `db:"members"`
+	ActiveMembers int64     `db:"active_members"`
+	Assignments   int64     `db:"assignments"`
+	OpenReviews   int64     `db:"open_reviews"`
 }
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TeamAuditAction string
+
+const (
+	AuditTeamCreated       TeamAuditAction = "TEAM_CREATED"
+	AuditTeamRenamed       TeamAuditAction = "TEAM_RENAMED"
+	AuditTeamDeleted       TeamAuditAction = "TEAM_DELETED"
+	AuditTeamRestored      TeamAuditAction = "TEAM_RESTORED"
+	AuditTeamParentChanged TeamAuditAction = "TEAM_PARENT_CHANGED"
+	AuditMemberAdded       TeamAuditAction = "MEMBER_ADDED"
+	AuditMemberRemoved     TeamAuditAction = "MEMBER_REMOVED"
+	AuditMemberRestored    TeamAuditAction = "MEMBER_RESTORED"
+	AuditMemberUpdated     TeamAuditAction = "MEMBER_UPDATED"
+	AuditMemberDeactivated TeamAuditAction = "MEMBER_DEACTIVATED"
+	AuditMemberMovedIn     TeamAuditAction = "MEMBER_MOVED_IN"
+	AuditMemberMovedOut    TeamAuditAction = "MEMBER_MOVED_OUT"
+	AuditReviewReassigned  TeamAuditAction = "REVIEW_REASSIGNED"
+	AuditMembershipAdded   TeamAuditAction = "MEMBERSHIP_ADDED"
+	AuditMembershipRemoved TeamAuditAction = "MEMBERSHIP_REMOVED"
+)
+
+// TeamAuditEvent records a change to a team or its membership. OldValue and
+// NewValue depend on the action: the team names for a rename or a move, the
+// pull request and the new reviewer for a reassigned review.
+type TeamAuditEvent struct {
+	EventID   uuid.UUID       `db:"event_id"`
+	TeamID    uuid.UUID       `db:"team_id"`
+	TeamName  string          `db:"team_name"`
+	Action    TeamAuditAction `db:"action"`
+	UserID    *string         `db:"user_id"`
+	OldValue  *string         `db:"old_value"`
+	NewValue  *string         `db:"new_value"`
+	CreatedAt time.Time       `db:"created_at"`
+}
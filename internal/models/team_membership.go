@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TeamMembership makes a user a member of a team. Every user has exactly one
+// primary membership, the team in User.TeamID; the others let them review for
+// more teams and can be deactivated one by one.
+type TeamMembership struct {
+	UserID    string    `db:"user_id"`
+	TeamID    uuid.UUID `db:"team_id"`
+	IsPrimary bool      `db:"is_primary"`
+	IsActive  bool      `db:"is_active"`
+	CreatedAt time.Time `db:"created_at"`
+}
@@ -0,0 +1,10 @@
+package models
+
+import "github.com/google/uuid"
+
+type TeamSettings struct {
+	TeamID                uuid.UUID `db:"team_id"`
+	SLARemindAfterHours   *int      `db:"sla_remind_after_hours"`
+	SLAReassignAfterHours *int      `db:"sla_reassign_after_hours"`
+	RotationWindow        *int      `db:"rotation_window"`
+}
@@ -1,10 +1,18 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
 
 type User struct {
 	UserID   string    `db:"user_id"`
 	Username string    `db:"username"`
 	TeamID   uuid.UUID `db:"team_id"`
 	IsActive bool      `db:"is_active"`
+	// MaxOpenReviews caps how many open PRs the user reviews at once; nil
+	// means no limit.
+	MaxOpenReviews *int `db:"max_open_reviews"`
+	// DeletedAt is set for soft-deleted users, which GORM queries skip.
+	DeletedAt gorm.DeletedAt `db:"deleted_at"`
 }
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+type DigestFrequency string
+
+const (
+	DigestDaily  DigestFrequency = "DAILY"
+	DigestWeekly DigestFrequency = "WEEKLY"
+)
+
+type UserNotificationSettings struct {
+	UserID           string          `db:"user_id"`
+	Email            *string         `db:"email"`
+	EmailOptOut      bool            `db:"email_opt_out"`
+	DigestFrequency  DigestFrequency `db:"digest_frequency"`
+	LastDigestSentAt *time.Time      `db:"last_digest_sent_at"`
+}
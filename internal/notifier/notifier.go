@@ -0,0 +1,67 @@
+package notifier
+
+import (
+	"context"
+	"time"
+)
+
+type Person struct {
+	ID   string
+	Name string
+}
+
+type Assignment struct {
+	TeamName        string
+	WebhookURL      string
+	PullRequestID   string
+	PullRequestName string
+	Author          Person
+	Reviewers       []Person
+	Replaced        *Person
+}
+
+type DigestPR struct {
+	PullRequestID   string
+	PullRequestName string
+	AuthorID        string
+	CreatedAt       time.Time
+}
+
+type ReviewerQueue struct {
+	Reviewer     Person
+	PullRequests []DigestPR
+}
+
+type Digest struct {
+	TeamName   string
+	WebhookURL string
+	Queues     []ReviewerQueue
+}
+
+type Reminder struct {
+	TeamName        string
+	WebhookURL      string
+	PullRequestID   string
+	PullRequestName string
+	Author          Person
+	Reviewer        Person
+	AssignedAt      time.Time
+}
+
+type Notifier interface {
+	NotifyAssignment(ctx context.Context, a Assignment) error
+	NotifyDigest(ctx context.Context, d Digest) error
+	NotifyReminder(ctx context.Context, r Reminder) error
+}
+
+type NoopNotifier struct{}
+
+func NewNoopNotifier() Notifier {
+	return NoopNotifier{}
+}
+
+func (NoopNotifier) NotifyAssignment(context.Context, Assignment) error { return nil }
+
+func (NoopNotifier) NotifyDigest(context.Context, Digest) error { return nil }
+
+func (NoopNotifier) NotifyReminder(context.Context, Reminder) error { return nil }
@@ -0,0 +1,168 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/mink0ff/pr_service/internal/config"
+)
+
+const assignmentTemplate = `{{if .Replaced}}:arrows_counterclockwise: Reviewer reassigned{{else}}:eyes: Review requested{{end}} in *{{.TeamName}}*
+{{prLink .PullRequestID .PullRequestName}} ({{.PullRequestID}}) by {{.Author.Name}}
+{{- if .Replaced}}
+Replaced: {{.Replaced.Name}}{{end}}
+Reviewers: {{range $i, $r := .Reviewers}}{{if $i}}, {{end}}{{$r.Name}}{{else}}none{{end}}`
+
+const digestTemplate = `:inbox_tray: Daily review digest for *{{.TeamName}}*
+{{- range .Queues}}
+*{{.Reviewer.Name}}* — {{len .PullRequests}} open
+{{- range .PullRequests}}
+  • {{prLink .PullRequestID .PullRequestName}} by {{.AuthorID}}, open {{age .CreatedAt}}
+{{- end}}
+{{- end}}`
+
+const reminderTemplate = `:hourglass: Review overdue in *{{.TeamName}}*
+{{.Reviewer.Name}}, {{prLink .PullRequestID .PullRequestName}} ({{.PullRequestID}}) by {{.Author.Name}} has been waiting for {{age .AssignedAt}}`
+
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+type SlackNotifier struct {
+	client     *http.Client
+	linkFormat string
+	assignment *template.Template
+	digest     *template.Template
+	reminder   *template.Template
+	now        func() time.Time
+}
+
+func NewSlackNotifier(cfg *config.NotifyConfig) (*SlackNotifier, error) {
+	n := &SlackNotifier{
+		client:     &http.Client{Timeout: cfg.Timeout},
+		linkFormat: cfg.PRLinkTemplate,
+		now:        time.Now,
+	}
+
+	funcs := template.FuncMap{
+		"prLink": n.prLink,
+		"age":    n.age,
+	}
+
+	var err error
+	n.assignment, err = template.New("assignment").Funcs(funcs).Parse(assignmentTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("parse assignment template: %w", err)
+	}
+
+	n.digest, err = template.New("digest").Funcs(funcs).Parse(digestTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("parse digest template: %w", err)
+	}
+
+	n.reminder, err = template.New("reminder").Funcs(funcs).Parse(reminderTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("parse reminder template: %w", err)
+	}
+
+	return n, nil
+}
+
+func (n *SlackNotifier) NotifyAssignment(ctx context.Context, a Assignment) error {
+	if a.WebhookURL == "" {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := n.assignment.Execute(&buf, a); err != nil {
+		return fmt.Errorf("render assignment message: %w", err)
+	}
+
+	return n.post(ctx, a.WebhookURL, buf.String())
+}
+
+func (n *SlackNotifier) NotifyDigest(ctx context.Context, d Digest) error {
+	if d.WebhookURL == "" || len(d.Queues) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := n.digest.Execute(&buf, d); err != nil {
+		return fmt.Errorf("render digest message: %w", err)
+	}
+
+	return n.post(ctx, d.WebhookURL, buf.String())
+}
+
+func (n *SlackNotifier) NotifyReminder(ctx context.Context, r Reminder) error {
+	if r.WebhookURL == "" {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := n.reminder.Execute(&buf, r); err != nil {
+		return fmt.Errorf("render reminder message: %w", err)
+	}
+
+	return n.post(ctx, r.WebhookURL, buf.String())
+}
+
+func (n *SlackNotifier) post(ctx context.Context, webhookURL string, text string) error {
+	body, err := json.Marshal(slackMessage{Text: text})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		log.Printf("Failed to post chat notification: %v", err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		log.Printf("Chat webhook responded with status %d", resp.StatusCode)
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (n *SlackNotifier) prLink(id, name string) string {
+	name = slackEscaper.Replace(name)
+	if n.linkFormat == "" {
+		return name
+	}
+	link := strings.ReplaceAll(n.linkFormat, "{pull_request_id}", url.PathEscape(id))
+	return "<" + link + "|" + name + ">"
+}
+
+// slackEscaper escapes the characters Slack mrkdwn treats as control
+// characters, so a PR name cannot break or forge a link.
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func (n *SlackNotifier) age(createdAt time.Time) string {
+	d := n.now().Sub(createdAt)
+	switch {
+	case d >= 48*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+}
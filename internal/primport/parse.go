@@ -0,0 +1,154 @@
+package primport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/mink0ff/pr_service/internal/dto"
+)
+
+const (
+	FormatNDJSON = "ndjson"
+	FormatCSV    = "csv"
+)
+
+var ErrUnknownFormat = errors.New("unknown import format")
+
+// csvColumns lists the accepted CSV header names; reviewers and merged_at may
+// be omitted.
+var csvColumns = []string{"pull_request_id", "pull_request_name", "author_id", "status", "reviewers", "created_at", "merged_at"}
+
+var requiredCSVColumns = []string{"pull_request_id", "pull_request_name", "author_id", "status", "created_at"}
+
+// Parse reads pull requests in the given format. Rows that cannot be decoded
+// are reported as row errors, Row being the line number in the input; the
+// returned error is reserved for an unknown format or a failing reader.
+func Parse(format string, r io.Reader) ([]dto.ImportPullRequest, []dto.ImportRowError, error) {
+	switch strings.ToLower(format) {
+	case "", FormatNDJSON:
+		return parseNDJSON(r)
+	case FormatCSV:
+		return parseCSV(r)
+	default:
+		return nil, nil, fmt.Errorf("%w %q, expected ndjson or csv", ErrUnknownFormat, format)
+	}
+}
+
+func parseNDJSON(r io.Reader) ([]dto.ImportPullRequest, []dto.ImportRowError, error) {
+	var (
+		rows   []dto.ImportPullRequest
+		errs   []dto.ImportRowError
+		lineNo int
+	)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lineNo++
+
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var row dto.ImportPullRequest
+		dec := json.NewDecoder(bytes.NewReader(line))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&row); err != nil {
+			errs = append(errs, dto.ImportRowError{Row: lineNo, Error: "invalid json: " + err.Error()})
+			continue
+		}
+
+		row.Row = lineNo
+		rows = append(rows, row)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return rows, errs, nil
+}
+
+func parseCSV(r io.Reader) ([]dto.ImportPullRequest, []dto.ImportRowError, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, []dto.ImportRowError{{Row: 1, Error: "invalid csv header: " + err.Error()}}, nil
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(csvColumns, name) {
+			return nil, []dto.ImportRowError{{Row: 1, Error: fmt.Sprintf("unknown column %q", name)}}, nil
+		}
+		columns[name] = i
+	}
+	for _, name := range requiredCSVColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, []dto.ImportRowError{{Row: 1, Error: fmt.Sprintf("missing column %q", name)}}, nil
+		}
+	}
+
+	var (
+		rows []dto.ImportPullRequest
+		errs []dto.ImportRowError
+	)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			errs = append(errs, dto.ImportRowError{Row: parseErr.StartLine, Error: "invalid csv: " + parseErr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		rows = append(rows, dto.ImportPullRequest{
+			Row:             line,
+			PullRequestID:   field("pull_request_id"),
+			PullRequestName: field("pull_request_name"),
+			AuthorID:        field("author_id"),
+			Status:          dto.PRStatus(strings.ToUpper(field("status"))),
+			Reviewers:       splitReviewers(field("reviewers")),
+			CreatedAt:       field("created_at"),
+			MergedAt:        field("merged_at"),
+		})
+	}
+
+	return rows, errs, nil
+}
+
+// splitReviewers accepts reviewers separated by semicolons, commas or spaces,
+// so a spreadsheet cell like "u2; u3" works as is.
+func splitReviewers(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ';' || r == ',' || r == ' '
+	})
+}
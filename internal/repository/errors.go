@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// Postgres SQLSTATE codes translated by TranslateErrors.
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+)
+
+var (
+	ErrDuplicate  = errors.New("duplicate key")
+	ErrForeignKey = errors.New("referenced row does not exist")
+)
+
+// ConstraintError is a translated constraint violation. errors.Is matches it
+// against ErrDuplicate or ErrForeignKey, and it unwraps to the driver error.
+type ConstraintError struct {
+	Kind       error
+	Table      string
+	Constraint string
+	Err        error
+}
+
+func (e *ConstraintError) Error() string {
+	return fmt.Sprintf("%v: %s violates %s", e.Kind, e.Table, e.Constraint)
+}
+
+func (e *ConstraintError) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *ConstraintError) Unwrap() error {
+	return e.Err
+}
+
+func translateError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		return &ConstraintError{Kind: ErrDuplicate, Table: pgErr.TableName, Constraint: pgErr.ConstraintName, Err: err}
+	case pgForeignKeyViolation:
+		return &ConstraintError{Kind: ErrForeignKey, Table: pgErr.TableName, Constraint: pgErr.ConstraintName, Err: err}
+	}
+	return err
+}
+
+// TranslateErrors registers callbacks that turn unique and foreign key
+// violations into ConstraintError for every statement run through db, so
+// services can tell "already exists" from "references something missing"
+// without knowing Postgres error codes.
+func TranslateErrors(db *gorm.DB) error {
+	translate := func(tx *gorm.DB) {
+		if tx.Error != nil {
+			tx.Error = translateError(tx.Error)
+		}
+	}
+
+	cb := db.Callback()
+	const name = "pr_service:translate_error"
+	return errors.Join(
+		cb.Create().After("*").Register(name, translate),
+		cb.Update().After("*").Register(name, translate),
+		cb.Delete().After("*").Register(name, translate),
+		cb.Query().After("*").Register(name, translate),
+		cb.Raw().After("*").Register(name, translate),
+		cb.Row().After("*").Register(name, translate),
+	)
+}
@@ -3,6 +3,7 @@ package gormdb
 import (
 	"time"
 
+	"github.com/mink0ff/pr_service/internal/repository"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -26,6 +27,10 @@ func NewGormDB(cfg *GormConfig) (*gorm.DB, error) {
 		return nil, err
 	}
 
+	if err := repository.TranslateErrors(db); err != nil {
+		return nil, err
+	}
+
 	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
 	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
 	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
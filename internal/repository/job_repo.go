@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/mink0ff/pr_service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type JobRepo struct {
+	db *gorm.DB
+}
+
+func NewJobRepo(db *gorm.DB) JobRepository {
+	return &JobRepo{db: db}
+}
+
+func (r *JobRepo) Register(ctx context.Context, job models.Job) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"schedule", "updated_at"}),
+		}).
+		Create(&job).Error
+	if err != nil {
+		log.Printf("Failed to register job %v: %v\n", job.Name, err)
+	}
+	return err
+}
+
+func (r *JobRepo) Get(ctx context.Context, name string) (*models.Job, error) {
+	var job models.Job
+	err := r.db.WithContext(ctx).First(&job, "name = ?", name).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("Error fetching job %v: %v\n", name, err)
+	}
+	return &job, err
+}
+
+func (r *JobRepo) List(ctx context.Context) ([]models.Job, error) {
+	var jobs []models.Job
+	err := r.db.WithContext(ctx).Order("name").Find(&jobs).Error
+	if err != nil {
+		log.Printf("Failed to list jobs: %v\n", err)
+	}
+	return jobs, err
+}
+
+func (r *JobRepo) SetPaused(ctx context.Context, name string, paused bool) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&models.Job{}).
+		Where("name = ?", name).
+		Updates(map[string]interface{}{"paused": paused, "updated_at": time.Now()})
+	if res.Error != nil {
+		log.Printf("Failed to set paused=%v for job %v: %v\n", paused, name, res.Error)
+		return false, res.Error
+	}
+	log.Printf("Job %v paused=%v\n", name, paused)
+	return res.RowsAffected == 1, nil
+}
+
+func (r *JobRepo) StartRun(ctx context.Context, run models.JobRun) error {
+	err := r.db.WithContext(ctx).Create(&run).Error
+	if err != nil {
+		log.Printf("Failed to record start of job %v: %v\n", run.JobName, err)
+	}
+	return err
+}
+
+func (r *JobRepo) FinishRun(ctx context.Context, run models.JobRun) error {
+	err := r.db.WithContext(ctx).
+		Model(&models.JobRun{}).
+		Where("job_run_id = ?", run.JobRunID).
+		Updates(map[string]interface{}{
+			"status":      run.Status,
+			"error":       run.Error,
+			"finished_at": run.FinishedAt,
+		}).Error
+	if err != nil {
+		log.Printf("Failed to record finish of job %v: %v\n", run.JobName, err)
+	}
+	return err
+}
+
+func (r *JobRepo) ListRuns(ctx context.Context, name string, limit int) ([]models.JobRun, error) {
+	var runs []models.JobRun
+	err := r.db.WithContext(ctx).
+		Where("job_name = ?", name).
+		Order("started_at DESC").
+		Limit(limit).
+		Find(&runs).Error
+	if err != nil {
+		log.Printf("Failed to list runs for job %v: %v\n", name, err)
+	}
+	return runs, err
+}
+
+// HasScheduledRunSince reports whether a scheduled run started at or after
+// since is running or has succeeded. Manual and failed runs do not count, so
+// they never take the place of a scheduled one.
+func (r *JobRepo) HasScheduledRunSince(ctx context.Context, name string, since time.Time) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.JobRun{}).
+		Where("job_name = ? AND trigger = ? AND status IN ? AND started_at >= ?",
+			name, models.JobTriggerSchedule, []models.JobRunStatus{models.JobRunRunning, models.JobRunSucceeded}, since).
+		Count(&count).Error
+	if err != nil {
+		log.Printf("Failed to check scheduled runs of job %v: %v\n", name, err)
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -1,8 +1,13 @@
 package migrate
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"log"
+	"os"
+	"regexp"
+	"strconv"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
@@ -10,29 +15,142 @@ import (
 	"gorm.io/gorm"
 )
 
-func RunMigrations(db *gorm.DB, migratePath string) error {
-	sqlDB, _ := db.DB()
+type Status struct {
+	Version uint `json:"version"`
+	Dirty   bool `json:"dirty"`
+	// Applied is false when no migration has run yet.
+	Applied bool `json:"applied"`
+}
+
+func newMigrate(db *gorm.DB, migratePath string) (*migrate.Migrate, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
 
 	driver, err := postgres.WithInstance(sqlDB, &postgres.Config{})
 	if err != nil {
-		log.Fatalf("create driver: %v", err)
-		return err
+		return nil, fmt.Errorf("create driver: %w", err)
 	}
 
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://"+migratePath,
-		"postgres", driver)
+	m, err := migrate.NewWithDatabaseInstance("file://"+migratePath, "postgres", driver)
+	if err != nil {
+		return nil, fmt.Errorf("create migrate instance: %w", err)
+	}
+
+	return m, nil
+}
+
+func RunMigrations(db *gorm.DB, migratePath string) error {
+	m, err := newMigrate(db, migratePath)
 	if err != nil {
-		log.Fatalf("create migrate instance: %v", err)
 		return err
 	}
 
 	if err = m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
-		log.Fatalf("migrate up failed: %v", err)
-		return err
+		return fmt.Errorf("migrate up: %w", err)
 	}
 
 	log.Println("Migrations applied successfully")
 
 	return nil
 }
+
+// Down rolls back the given number of migrations.
+func Down(db *gorm.DB, migratePath string, steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("steps must be positive, got %d", steps)
+	}
+
+	m, err := newMigrate(db, migratePath)
+	if err != nil {
+		return err
+	}
+
+	if err = m.Steps(-steps); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("migrate down: %w", err)
+	}
+
+	log.Printf("Rolled back %d migration(s)", steps)
+
+	return nil
+}
+
+func GetStatus(db *gorm.DB, migratePath string) (*Status, error) {
+	m, err := newMigrate(db, migratePath)
+	if err != nil {
+		return nil, err
+	}
+
+	version, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return &Status{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Status{Version: version, Dirty: dirty, Applied: true}, nil
+}
+
+// Force sets the recorded version without running anything, which is how a
+// dirty state left by a failed migration is cleared after fixing it by hand.
+func Force(db *gorm.DB, migratePath string, version int) error {
+	m, err := newMigrate(db, migratePath)
+	if err != nil {
+		return err
+	}
+
+	if err := m.Force(version); err != nil {
+		return fmt.Errorf("migrate force: %w", err)
+	}
+
+	log.Printf("Migration version forced to %d", version)
+
+	return nil
+}
+
+var migrationFile = regexp.MustCompile(`^(\d+)_.+\.up\.sql$`)
+
+// LatestVersion returns the highest migration version found in migratePath,
+// i.e. the version a fully migrated database reports.
+func LatestVersion(migratePath string) (uint, error) {
+	entries, err := os.ReadDir(migratePath)
+	if err != nil {
+		return 0, err
+	}
+
+	var latest uint
+	for _, entry := range entries {
+		m := migrationFile.FindStringSubmatch(entry.Name())
+		if m == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(m[1], 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("migration %s: %w", entry.Name(), err)
+		}
+		latest = max(latest, uint(version))
+	}
+
+	if latest == 0 {
+		return 0, fmt.Errorf("no migrations found in %s", migratePath)
+	}
+	return latest, nil
+}
+
+// CurrentVersion reads the schema_migrations table directly, which is much
+// cheaper than setting up a migrate instance on every health check.
+func CurrentVersion(ctx context.Context, db *gorm.DB) (*Status, error) {
+	var rows []Status
+	err := db.WithContext(ctx).Raw("SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return &Status{}, nil
+	}
+
+	rows[0].Applied = true
+	return &rows[0], nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/mink0ff/pr_service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationSettingsRepo struct {
+	db *gorm.DB
+}
+
+func NewNotificationSettingsRepo(db *gorm.DB) NotificationSettingsRepository {
+	return &NotificationSettingsRepo{db: db}
+}
+
+func (r *NotificationSettingsRepo) Get(ctx context.Context, userID string) (*models.UserNotificationSettings, error) {
+	var settings models.UserNotificationSettings
+	err := r.db.WithContext(ctx).First(&settings, "user_id = ?", userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("Notification settings for user %v not found\n", userID)
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("Error fetching notification settings for user %v: %v\n", userID, err)
+	}
+	return &settings, err
+}
+
+func (r *NotificationSettingsRepo) Upsert(ctx context.Context, settings models.UserNotificationSettings) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"email", "email_opt_out", "digest_frequency"}),
+		}).
+		Create(&settings).Error
+	if err != nil {
+		log.Printf("Failed to save notification settings for user %v: %v\n", settings.UserID, err)
+	} else {
+		log.Printf("Notification settings for user %v saved successfully\n", settings.UserID)
+	}
+	return err
+}
+
+func (r *NotificationSettingsRepo) ListEmailRecipients(ctx context.Context) ([]models.UserNotificationSettings, error) {
+	var settings []models.UserNotificationSettings
+	err := r.db.WithContext(ctx).
+		Joins("JOIN users u ON u.user_id = user_notification_settings.user_id").
+		Where("u.is_active = TRUE AND u.deleted_at IS NULL AND user_notification_settings.email_opt_out = FALSE").
+		Where("user_notification_settings.email IS NOT NULL AND user_notification_settings.email <> ''").
+		Find(&settings).Error
+	if err != nil {
+		log.Printf("Failed to list email digest recipients: %v\n", err)
+	} else {
+		log.Printf("Found %d email digest recipients\n", len(settings))
+	}
+	return settings, err
+}
+
+func (r *NotificationSettingsRepo) MarkDigestSent(ctx context.Context, userID string, sentAt time.Time) error {
+	err := r.db.WithContext(ctx).
+		Model(&models.UserNotificationSettings{}).
+		Where("user_id = ?", userID).
+		Update("last_digest_sent_at", sentAt).Error
+	if err != nil {
+		log.Printf("Failed to mark digest sent for user %v: %v\n", userID, err)
+	}
+	return err
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/mink0ff/pr_service/internal/models"
+	"gorm.io/gorm"
+)
+
+type OwnershipRuleRepo struct {
+	db *gorm.DB
+}
+
+func NewOwnershipRuleRepo(db *gorm.DB) OwnershipRuleRepository {
+	return &OwnershipRuleRepo{db: db}
+}
+
+func (r *OwnershipRuleRepo) ListByTeam(ctx context.Context, teamID uuid.UUID) ([]models.OwnershipRule, error) {
+	var rules []models.OwnershipRule
+	err := r.db.WithContext(ctx).
+		Where("team_id = ?", teamID).
+		Order("position").
+		Find(&rules).Error
+	if err != nil {
+		log.Printf("Failed to list ownership rules for team %v: %v\n", teamID, err)
+	} else {
+		log.Printf("Found %d ownership rules for team %v\n", len(rules), teamID)
+	}
+	return rules, err
+}
+
+// Append stores the rule after the existing ones of its team, so it takes
+// precedence over all of them.
+func (r *OwnershipRuleRepo) Append(ctx context.Context, rule *models.OwnershipRule) error {
+	err := r.db.WithContext(ctx).Raw(`
+		INSERT INTO ownership_rules (rule_id, team_id, position, pattern, owners, created_at)
+		SELECT ?, ?, COALESCE(MAX(position), 0) + 1, ?, ?, ?
+		FROM ownership_rules
+		WHERE team_id = ?
+		RETURNING position`,
+		rule.RuleID, rule.TeamID, rule.Pattern, rule.Owners, rule.CreatedAt, rule.TeamID,
+	).Scan(&rule.Position).Error
+	if err != nil {
+		log.Printf("Failed to add ownership rule %q for team %v: %v\n", rule.Pattern, rule.TeamID, err)
+	} else {
+		log.Printf("Ownership rule %q added for team %v at position %d\n", rule.Pattern, rule.TeamID, rule.Position)
+	}
+	return err
+}
+
+func (r *OwnershipRuleRepo) ReplaceForTeam(ctx context.Context, teamID uuid.UUID, rules []models.OwnershipRule) error {
+	if err := r.db.WithContext(ctx).Where("team_id = ?", teamID).Delete(&models.OwnershipRule{}).Error; err != nil {
+		log.Printf("Failed to clear ownership rules for team %v: %v\n", teamID, err)
+		return err
+	}
+
+	if len(rules) == 0 {
+		return nil
+	}
+
+	err := r.db.WithContext(ctx).Create(&rules).Error
+	if err != nil {
+		log.Printf("Failed to import ownership rules for team %v: %v\n", teamID, err)
+	} else {
+		log.Printf("Imported %d ownership rules for team %v\n", len(rules), teamID)
+	}
+	return err
+}
+
+func (r *OwnershipRuleRepo) Delete(ctx context.Context, teamID uuid.UUID, ruleID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("team_id = ? AND rule_id = ?", teamID, ruleID).
+		Delete(&models.OwnershipRule{})
+	if result.Error != nil {
+		log.Printf("Failed to delete ownership rule %v: %v\n", ruleID, result.Error)
+		return false, result.Error
+	}
+	log.Printf("Ownership rule %v deleted: %v\n", ruleID, result.RowsAffected > 0)
+	return result.RowsAffected > 0, nil
+}
+
+func (r *OwnershipRuleRepo) WithTx(tx *gorm.DB) OwnershipRuleRepository {
+	return &OwnershipRuleRepo{db: tx}
+}
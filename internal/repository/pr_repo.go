@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"log"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/mink0ff/pr_service/internal/models"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -47,6 +49,47 @@ func (r *PrRepo) GetByID(ctx context.Context, id string) (*models.PullRequest, e
 	return &pr, err
 }
 
+func (r *PrRepo) ListExistingIDs(ctx context.Context, ids []string) ([]string, error) {
+	var existing []string
+	if len(ids) == 0 {
+		return existing, nil
+	}
+
+	// Deleted pull requests still hold their IDs.
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&models.PullRequest{}).
+		Where("pull_request_id IN ?", ids).
+		Pluck("pull_request_id", &existing).Error
+	if err != nil {
+		log.Printf("Failed to look up existing PullRequests: %v\n", err)
+	}
+	return existing, err
+}
+
+// CreateBatch inserts pull requests together with their reviewer rows,
+// batchSize rows per INSERT.
+func (r *PrRepo) CreateBatch(ctx context.Context, prs []models.PullRequest, reviewers []models.PRReviewer, batchSize int) error {
+	db := r.db.WithContext(ctx)
+
+	if len(prs) > 0 {
+		if err := db.CreateInBatches(&prs, batchSize).Error; err != nil {
+			log.Printf("Failed to insert %d PullRequests: %v\n", len(prs), err)
+			return err
+		}
+	}
+
+	if len(reviewers) > 0 {
+		if err := db.CreateInBatches(&reviewers, batchSize).Error; err != nil {
+			log.Printf("Failed to insert %d reviewer rows: %v\n", len(reviewers), err)
+			return err
+		}
+	}
+
+	log.Printf("Inserted %d PullRequests with %d reviewers\n", len(prs), len(reviewers))
+	return nil
+}
+
 func (r *PrRepo) Update(ctx context.Context, pr models.PullRequest) error {
 	err := r.db.WithContext(ctx).
 		Where("pull_request_id = ?", pr.PullRequestID).
@@ -59,10 +102,24 @@ func (r *PrRepo) Update(ctx context.Context, pr models.PullRequest) error {
 	return err
 }
 
+// Delete marks the pull request deleted; its reviewers and history are kept.
+func (r *PrRepo) Delete(ctx context.Context, id string) error {
+	err := r.db.WithContext(ctx).
+		Where("pull_request_id = ?", id).
+		Delete(&models.PullRequest{}).Error
+	if err != nil {
+		log.Printf("Failed to delete PullRequest %v: %v\n", id, err)
+	} else {
+		log.Printf("PullRequest %v deleted\n", id)
+	}
+	return err
+}
+
 func (r *PrRepo) AddReviewer(ctx context.Context, prID string, reviewerID string) error {
 	record := models.PRReviewer{
 		PullRequestID: prID,
 		ReviewerID:    reviewerID,
+		AssignedAt:    time.Now(),
 	}
 	err := r.db.WithContext(ctx).Create(&record).Error
 	if err != nil {
@@ -85,6 +142,32 @@ func (r *PrRepo) RemoveReviewer(ctx context.Context, prID string, reviewerID str
 	return err
 }
 
+func (r *PrRepo) SetReviewerPinned(ctx context.Context, prID string, reviewerID string, pinned bool) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&models.PRReviewer{}).
+		Where("pull_request_id = ? AND reviewer_id = ?", prID, reviewerID).
+		Update("pinned", pinned)
+	if res.Error != nil {
+		log.Printf("Failed to set pinned=%v for reviewer %v on PR %v: %v\n", pinned, reviewerID, prID, res.Error)
+		return false, res.Error
+	}
+	log.Printf("Reviewer %v on PR %v pinned=%v\n", reviewerID, prID, pinned)
+	return res.RowsAffected == 1, nil
+}
+
+func (r *PrRepo) ListPinnedReviewers(ctx context.Context, prID string) ([]string, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).
+		Model(&models.PRReviewer{}).
+		Where("pull_request_id = ? AND pinned", prID).
+		Order("reviewer_id").
+		Pluck("reviewer_id", &ids).Error
+	if err != nil {
+		log.Printf("Failed to list pinned reviewers for PR %v: %v\n", prID, err)
+	}
+	return ids, err
+}
+
 func (r *PrRepo) ListReviewers(ctx context.Context, prID string) ([]models.User, error) {
 	var users []models.User
 	err := r.db.WithContext(ctx).
@@ -120,9 +203,10 @@ func (r *PrRepo) WithTx(tx *gorm.DB) PullRequestRepository {
 	return &PrRepo{db: tx}
 }
 
+// RemoveReviewerFromAllPRs drops the user from every PR they were not pinned to.
 func (r *PrRepo) RemoveReviewerFromAllPRs(ctx context.Context, userID string) error {
 	err := r.db.WithContext(ctx).
-		Where("reviewer_id = ?", userID).
+		Where("reviewer_id = ? AND NOT pinned", userID).
 		Delete(&models.PRReviewer{}).Error
 	if err != nil {
 		log.Printf("Failed to remove reviewer %v from all PRs: %v\n", userID, err)
@@ -131,3 +215,57 @@ func (r *PrRepo) RemoveReviewerFromAllPRs(ctx context.Context, userID string) er
 	}
 	return err
 }
+
+// RemoveReviewerFromTeamPRs unassigns the reviewer from open pull requests
+// authored in the team, pinned assignments excepted.
+func (r *PrRepo) RemoveReviewerFromTeamPRs(ctx context.Context, userID string, teamID uuid.UUID) error {
+	err := r.db.WithContext(ctx).
+		Where("reviewer_id = ? AND NOT pinned", userID).
+		Where(`pull_request_id IN (
+			SELECT p.pull_request_id FROM pull_requests p
+			JOIN users a ON a.user_id = p.author_id
+			WHERE a.team_id = ? AND p.status = ?)`, teamID, models.PROpen).
+		Delete(&models.PRReviewer{}).Error
+	if err != nil {
+		log.Printf("Failed to remove reviewer %v from PRs of team %v: %v\n", userID, teamID, err)
+	} else {
+		log.Printf("Reviewer %v removed from PRs of team %v\n", userID, teamID)
+	}
+	return err
+}
+
+func (r *PrRepo) ListStaleReviews(ctx context.Context, now time.Time) ([]models.StaleReview, error) {
+	var stale []models.StaleReview
+	err := r.db.WithContext(ctx).
+		Table("pr_reviewers prr").
+		Select(`prr.pull_request_id, prr.reviewer_id, u.team_id, prr.assigned_at, prr.reminded_at, prr.pinned,
+			ts.sla_remind_after_hours, ts.sla_reassign_after_hours`).
+		Joins("JOIN pull_requests pr ON pr.pull_request_id = prr.pull_request_id").
+		Joins("JOIN users u ON u.user_id = pr.author_id").
+		Joins("JOIN team_settings ts ON ts.team_id = u.team_id").
+		Where("pr.status = ? AND pr.deleted_at IS NULL", models.PROpen).
+		Where(`(ts.sla_reassign_after_hours IS NOT NULL
+				AND prr.assigned_at <= ?::timestamptz - make_interval(hours => ts.sla_reassign_after_hours))
+			OR (ts.sla_remind_after_hours IS NOT NULL AND prr.reminded_at IS NULL
+				AND prr.assigned_at <= ?::timestamptz - make_interval(hours => ts.sla_remind_after_hours))`, now, now).
+		Order("prr.assigned_at").
+		Scan(&stale).Error
+	if err != nil {
+		log.Printf("Failed to list stale reviews: %v\n", err)
+	} else {
+		log.Printf("Found %d stale reviews\n", len(stale))
+	}
+	return stale, err
+}
+
+func (r *PrRepo) MarkReminded(ctx context.Context, prID string, reviewerID string, at time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&models.PRReviewer{}).
+		Where("pull_request_id = ? AND reviewer_id = ? AND reminded_at IS NULL", prID, reviewerID).
+		Update("reminded_at", at)
+	if res.Error != nil {
+		log.Printf("Failed to mark reviewer %v reminded on PR %v: %v\n", reviewerID, prID, res.Error)
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/mink0ff/pr_service/internal/dto"
@@ -12,37 +13,165 @@ import (
 type UserRepository interface {
 	Create(ctx context.Context, user models.User) error
 	GetByID(ctx context.Context, id string) (*models.User, error)
+	ListByIDs(ctx context.Context, ids []string) ([]models.User, error)
 	ListActiveByTeam(ctx context.Context, teamID uuid.UUID) ([]models.User, error)
 	Update(ctx context.Context, user models.User) error
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context, filter UserFilter) ([]models.User, int64, error)
 	ListReviewPRs(ctx context.Context, userID string) ([]models.PullRequest, error)
+	ListAuthoredPRs(ctx context.Context, userID string) ([]models.PullRequest, error)
+	CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error)
+
+	ListMemberships(ctx context.Context, userID string) ([]models.TeamMembership, error)
+	AddMembership(ctx context.Context, membership models.TeamMembership) error
+	SetMembershipActive(ctx context.Context, userID string, teamID uuid.UUID, active bool) error
+	RemoveMembership(ctx context.Context, userID string, teamID uuid.UUID) error
 	WithTx(tx *gorm.DB) UserRepository
 }
 
+// UserFilter selects a page of users; nil TeamID and IsActive match every
+// user. TeamID matches secondary members of the team too.
+type UserFilter struct {
+	TeamID   *uuid.UUID
+	IsActive *bool
+	Limit    int
+	Offset   int
+}
+
 type TeamRepository interface {
 	Create(ctx context.Context, team models.Team) error
 	GetByID(ctx context.Context, teamID uuid.UUID) (*models.Team, error)
 	GetByName(ctx context.Context, teamName string) (*models.Team, error)
+	Update(ctx context.Context, team models.Team) error
+	Delete(ctx context.Context, teamID uuid.UUID) error
+	List(ctx context.Context) ([]models.Team, error)
 
 	ListUsersByTeam(ctx context.Context, teamID uuid.UUID) ([]models.User, error)
+
+	ListAncestors(ctx context.Context, teamID uuid.UUID) ([]models.Team, error)
+	ListSubtree(ctx context.Context, teamID uuid.UUID) ([]models.Team, error)
+	LockHierarchy(ctx context.Context) error
+	CountTeamActivity(ctx context.Context, teamIDs []uuid.UUID) ([]models.TeamActivity, error)
 	WithTx(tx *gorm.DB) TeamRepository
 }
 
 type PullRequestRepository interface {
 	Create(ctx context.Context, pr models.PullRequest) error
 	GetByID(ctx context.Context, id string) (*models.PullRequest, error)
+	ListExistingIDs(ctx context.Context, ids []string) ([]string, error)
+	CreateBatch(ctx context.Context, prs []models.PullRequest, reviewers []models.PRReviewer, batchSize int) error
 	Update(ctx context.Context, pr models.PullRequest) error
+	Delete(ctx context.Context, id string) error
 
 	AddReviewer(ctx context.Context, prID string, reviewerID string) error
 	RemoveReviewer(ctx context.Context, prID string, reviewerID string) error
+	SetReviewerPinned(ctx context.Context, prID string, reviewerID string, pinned bool) (bool, error)
+	ListPinnedReviewers(ctx context.Context, prID string) ([]string, error)
 
 	ListReviewers(ctx context.Context, prID string) ([]models.User, error)
 	ListByReviewer(ctx context.Context, reviewerID string) ([]models.PullRequest, error)
 	WithTx(tx *gorm.DB) PullRequestRepository
 	RemoveReviewerFromAllPRs(ctx context.Context, userID string) error
+	RemoveReviewerFromTeamPRs(ctx context.Context, userID string, teamID uuid.UUID) error
+
+	ListStaleReviews(ctx context.Context, now time.Time) ([]models.StaleReview, error)
+	MarkReminded(ctx context.Context, prID string, reviewerID string, at time.Time) (bool, error)
 }
 
 type ReviewerHistoryRepository interface {
 	AddEvent(ctx context.Context, event models.ReviewerAssignmentHistory) error
+	AddEvents(ctx context.Context, events []models.ReviewerAssignmentHistory, batchSize int) error
 	CountAssignmentsByUsers(ctx context.Context) ([]dto.ReviewerStatsItem, error)
+	CountRecentReviewsOfAuthor(ctx context.Context, authorID string, excludePRID string, window int) (map[string]int, error)
 	WithTx(tx *gorm.DB) ReviewerHistoryRepository
 }
+
+type NotificationSettingsRepository interface {
+	Get(ctx context.Context, userID string) (*models.UserNotificationSettings, error)
+	Upsert(ctx context.Context, settings models.UserNotificationSettings) error
+	ListEmailRecipients(ctx context.Context) ([]models.UserNotificationSettings, error)
+	MarkDigestSent(ctx context.Context, userID string, sentAt time.Time) error
+}
+
+type TeamSettingsRepository interface {
+	Get(ctx context.Context, teamID uuid.UUID) (*models.TeamSettings, error)
+	Upsert(ctx context.Context, settings models.TeamSettings) error
+}
+
+type JobRepository interface {
+	Register(ctx context.Context, job models.Job) error
+	Get(ctx context.Context, name string) (*models.Job, error)
+	List(ctx context.Context) ([]models.Job, error)
+	SetPaused(ctx context.Context, name string, paused bool) (bool, error)
+
+	StartRun(ctx context.Context, run models.JobRun) error
+	FinishRun(ctx context.Context, run models.JobRun) error
+	ListRuns(ctx context.Context, name string, limit int) ([]models.JobRun, error)
+	HasScheduledRunSince(ctx context.Context, name string, since time.Time) (bool, error)
+}
+
+type OwnershipRuleRepository interface {
+	ListByTeam(ctx context.Context, teamID uuid.UUID) ([]models.OwnershipRule, error)
+	Append(ctx context.Context, rule *models.OwnershipRule) error
+	ReplaceForTeam(ctx context.Context, teamID uuid.UUID, rules []models.OwnershipRule) error
+	Delete(ctx context.Context, teamID uuid.UUID, ruleID uuid.UUID) (bool, error)
+	WithTx(tx *gorm.DB) OwnershipRuleRepository
+}
+
+type TagRepository interface {
+	ListUserSkills(ctx context.Context, userID string) ([]string, error)
+	ListSkillsByUsers(ctx context.Context, userIDs []string) (map[string][]string, error)
+	AddUserSkills(ctx context.Context, userID string, tags []string) error
+	RemoveUserSkills(ctx context.Context, userID string, tags []string) error
+
+	ListPRLabels(ctx context.Context, prID string) ([]string, error)
+	AddPRLabels(ctx context.Context, prID string, labels []string) error
+	RemovePRLabels(ctx context.Context, prID string, labels []string) error
+	WithTx(tx *gorm.DB) TagRepository
+}
+
+type SnapshotRepository interface {
+	StreamTeams(ctx context.Context, fn func(models.Team) error) error
+	StreamUsers(ctx context.Context, fn func(models.User) error) error
+	StreamMemberships(ctx context.Context, fn func(models.TeamMembership) error) error
+	StreamPullRequests(ctx context.Context, fn func(models.PullRequest) error) error
+	StreamReviewers(ctx context.Context, fn func(models.PRReviewer) error) error
+	StreamHistory(ctx context.Context, fn func(models.ReviewerAssignmentHistory) error) error
+	StreamTeamSettings(ctx context.Context, fn func(models.TeamSettings) error) error
+	StreamOwnershipRules(ctx context.Context, fn func(models.OwnershipRule) error) error
+	StreamUserSkills(ctx context.Context, fn func(models.UserSkill) error) error
+	StreamNotificationSettings(ctx context.Context, fn func(models.UserNotificationSettings) error) error
+	StreamLabels(ctx context.Context, fn func(models.PRLabel) error) error
+	StreamAuditEvents(ctx context.Context, fn func(models.TeamAuditEvent) error) error
+	StreamAPITokens(ctx context.Context, fn func(models.APIToken) error) error
+
+	IsEmpty(ctx context.Context) (bool, error)
+	SchemaVersion(ctx context.Context) (uint, error)
+	Insert(ctx context.Context, rows any, batchSize int) error
+	InsertNew(ctx context.Context, rows any, batchSize int) error
+	WithTx(tx *gorm.DB) SnapshotRepository
+}
+
+type TeamAuditRepository interface {
+	Add(ctx context.Context, events ...models.TeamAuditEvent) error
+	ListByTeam(ctx context.Context, teamID uuid.UUID, limit int) ([]models.TeamAuditEvent, error)
+	WithTx(tx *gorm.DB) TeamAuditRepository
+}
+
+// RetentionRepository sees soft-deleted rows, which the other repositories
+// skip.
+type RetentionRepository interface {
+	ListDeleted(ctx context.Context, entity models.DeletedEntity, limit int) ([]models.DeletedRecord, error)
+	GetDeleted(ctx context.Context, entity models.DeletedEntity, id string) (*models.DeletedRecord, error)
+	Restore(ctx context.Context, entity models.DeletedEntity, id string) (bool, error)
+	Purge(ctx context.Context, entity models.DeletedEntity, before time.Time, archive bool) (int64, error)
+	WithTx(tx *gorm.DB) RetentionRepository
+}
+
+type TokenRepository interface {
+	Create(ctx context.Context, token models.APIToken) error
+	GetByName(ctx context.Context, name string) (*models.APIToken, error)
+	GetByHash(ctx context.Context, hash string) (*models.APIToken, error)
+	Delete(ctx context.Context, name string) (bool, error)
+	MarkUsed(ctx context.Context, tokenID string, at time.Time) error
+}
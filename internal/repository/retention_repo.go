@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mink0ff/pr_service/internal/models"
+	"gorm.io/gorm"
+)
+
+// deletableTable describes how to list, restore and purge one soft-deletable
+// table. Every query aliases the table as t.
+type deletableTable struct {
+	table        string
+	idColumn     string
+	nameColumn   string
+	parentColumn string
+	// purgeable holds back rows that other rows still reference, since
+	// removing them would cascade into live data.
+	purgeable string
+	// archived is the JSON kept in archive.records, with the rows that are
+	// removed together with t.
+	archived string
+}
+
+var deletableTables = map[models.DeletedEntity]deletableTable{
+	models.DeletedPullRequests: {
+		table:        "pull_requests",
+		idColumn:     "pull_request_id",
+		nameColumn:   "pull_request_name",
+		parentColumn: "author_id",
+		archived: `to_jsonb(t) || jsonb_build_object(
+			'reviewers', (SELECT COALESCE(jsonb_agg(to_jsonb(r)), '[]') FROM pr_reviewers r WHERE r.pull_request_id = t.pull_request_id),
+			'labels', (SELECT COALESCE(jsonb_agg(l.label), '[]') FROM pr_labels l WHERE l.pull_request_id = t.pull_request_id),
+			'history', (SELECT COALESCE(jsonb_agg(to_jsonb(h)), '[]') FROM reviewer_assignment_histories h WHERE h.pr_id = t.pull_request_id))`,
+	},
+	models.DeletedUsers: {
+		table:        "users",
+		idColumn:     "user_id",
+		nameColumn:   "username",
+		parentColumn: "team_id",
+		purgeable: `NOT EXISTS (SELECT 1 FROM pull_requests p WHERE p.author_id = t.user_id)
+			AND NOT EXISTS (SELECT 1 FROM pr_reviewers r WHERE r.reviewer_id = t.user_id)
+			AND NOT EXISTS (SELECT 1 FROM reviewer_assignment_histories h WHERE h.user_id = t.user_id)`,
+		archived: `to_jsonb(t) || jsonb_build_object(
+			'memberships', (SELECT COALESCE(jsonb_agg(to_jsonb(m)), '[]') FROM team_memberships m WHERE m.user_id = t.user_id),
+			'skills', (SELECT COALESCE(jsonb_agg(s.tag), '[]') FROM user_skills s WHERE s.user_id = t.user_id),
+			'notification_settings', (SELECT to_jsonb(n) FROM user_notification_settings n WHERE n.user_id = t.user_id))`,
+	},
+	models.DeletedTeams: {
+		table:        "teams",
+		idColumn:     "team_id",
+		nameColumn:   "team_name",
+		parentColumn: "parent_team_id",
+		purgeable: `NOT EXISTS (SELECT 1 FROM users u WHERE u.team_id = t.team_id)
+			AND NOT EXISTS (SELECT 1 FROM teams c WHERE c.parent_team_id = t.team_id)`,
+		archived: `to_jsonb(t) || jsonb_build_object(
+			'settings', (SELECT to_jsonb(s) FROM team_settings s WHERE s.team_id = t.team_id),
+			'ownership_rules', (SELECT COALESCE(jsonb_agg(to_jsonb(o) ORDER BY o.position), '[]') FROM ownership_rules o WHERE o.team_id = t.team_id))`,
+	},
+}
+
+func lookupDeletable(entity models.DeletedEntity) (deletableTable, error) {
+	dt, ok := deletableTables[entity]
+	if !ok {
+		return deletableTable{}, fmt.Errorf("unknown deleted entity %q", entity)
+	}
+	return dt, nil
+}
+
+type RetentionRepo struct {
+	db *gorm.DB
+}
+
+func NewRetentionRepo(db *gorm.DB) RetentionRepository {
+	return &RetentionRepo{db: db}
+}
+
+func (r *RetentionRepo) WithTx(tx *gorm.DB) RetentionRepository {
+	return &RetentionRepo{db: tx}
+}
+
+// ListDeleted returns the most recently deleted records first.
+func (r *RetentionRepo) ListDeleted(ctx context.Context, entity models.DeletedEntity, limit int) ([]models.DeletedRecord, error) {
+	dt, err := lookupDeletable(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []models.DeletedRecord
+	err = r.db.WithContext(ctx).Raw(fmt.Sprintf(`
+		SELECT t.%s::text AS id, t.%s AS name, t.%s::text AS parent_id, t.deleted_at
+		FROM %s t
+		WHERE t.deleted_at IS NOT NULL
+		ORDER BY t.deleted_at DESC, t.%[1]s
+		LIMIT ?`, dt.idColumn, dt.nameColumn, dt.parentColumn, dt.table), limit).
+		Scan(&records).Error
+	if err != nil {
+		log.Printf("Failed to list deleted %s: %v\n", entity, err)
+		return nil, err
+	}
+
+	for i := range records {
+		records[i].Entity = entity
+	}
+	log.Printf("Found %d deleted %s\n", len(records), entity)
+	return records, nil
+}
+
+func (r *RetentionRepo) GetDeleted(ctx context.Context, entity models.DeletedEntity, id string) (*models.DeletedRecord, error) {
+	dt, err := lookupDeletable(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	var record models.DeletedRecord
+	res := r.db.WithContext(ctx).Raw(fmt.Sprintf(`
+		SELECT t.%s::text AS id, t.%s AS name, t.%s::text AS parent_id, t.deleted_at
+		FROM %s t
+		WHERE t.%[1]s = ? AND t.deleted_at IS NOT NULL
+		FOR UPDATE`, dt.idColumn, dt.nameColumn, dt.parentColumn, dt.table), id).
+		Scan(&record)
+	if res.Error != nil {
+		log.Printf("Error fetching deleted %s %v: %v\n", entity, id, res.Error)
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		log.Printf("No deleted %s with id %v\n", entity, id)
+		return nil, nil
+	}
+	record.Entity = entity
+	return &record, nil
+}
+
+func (r *RetentionRepo) Restore(ctx context.Context, entity models.DeletedEntity, id string) (bool, error) {
+	dt, err := lookupDeletable(entity)
+	if err != nil {
+		return false, err
+	}
+
+	res := r.db.WithContext(ctx).Exec(fmt.Sprintf(
+		"UPDATE %s SET deleted_at = NULL WHERE %s = ? AND deleted_at IS NOT NULL", dt.table, dt.idColumn), id)
+	if res.Error != nil {
+		log.Printf("Failed to restore %s %v: %v\n", entity, id, res.Error)
+		return false, res.Error
+	}
+	log.Printf("Restored %s %v\n", entity, id)
+	return res.RowsAffected == 1, nil
+}
+
+// Purge hard-deletes the records deleted before the cutoff, except those
+// still referenced by other rows. With archive set each record is first
+// copied to archive.records along with the rows removed by the cascade; one
+// statement does both, so the copy sees the rows before they are deleted.
+func (r *RetentionRepo) Purge(ctx context.Context, entity models.DeletedEntity, before time.Time, archive bool) (int64, error) {
+	dt, err := lookupDeletable(entity)
+	if err != nil {
+		return 0, err
+	}
+
+	where := "t.deleted_at < @before"
+	if dt.purgeable != "" {
+		where += " AND " + dt.purgeable
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s t WHERE %s", dt.table, where)
+	if archive {
+		query = fmt.Sprintf(`
+			WITH purged AS (%s RETURNING t.*)
+			INSERT INTO archive.records (entity, record_id, data, deleted_at)
+			SELECT @entity, t.%s::text, %s, t.deleted_at
+			FROM purged t`, query, dt.idColumn, dt.archived)
+	}
+
+	args := []any{sql.Named("before", before)}
+	if archive {
+		args = append(args, sql.Named("entity", string(entity)))
+	}
+
+	res := r.db.WithContext(ctx).Exec(query, args...)
+	if res.Error != nil {
+		log.Printf("Failed to purge deleted %s: %v\n", entity, res.Error)
+		return 0, res.Error
+	}
+	log.Printf("Purged %d deleted %s (archive=%v)\n", res.RowsAffected, entity, archive)
+	return res.RowsAffected, nil
+}
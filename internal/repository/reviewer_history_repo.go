@@ -21,12 +21,25 @@ func (r *ReviewerHistoryRepo) AddEvent(ctx context.Context, event models.Reviewe
 	return r.db.WithContext(ctx).Create(&event).Error
 }
 
+func (r *ReviewerHistoryRepo) AddEvents(ctx context.Context, events []models.ReviewerAssignmentHistory, batchSize int) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	err := r.db.WithContext(ctx).CreateInBatches(&events, batchSize).Error
+	if err != nil {
+		log.Printf("Failed to insert %d history events: %v\n", len(events), err)
+	}
+	return err
+}
+
 func (r *ReviewerHistoryRepo) CountAssignmentsByUsers(ctx context.Context) ([]dto.ReviewerStatsItem, error) {
 	var statsItems []dto.ReviewerStatsItem
 
 	err := r.db.WithContext(ctx).
 		Model(&models.ReviewerAssignmentHistory{}).
 		Select("user_id, COUNT(*) AS count").
+		Where("event_type IN ?", models.AssignmentEvents).
 		Group("user_id").
 		Order("count DESC").
 		Scan(&statsItems).Error
@@ -40,6 +53,39 @@ func (r *ReviewerHistoryRepo) CountAssignmentsByUsers(ctx context.Context) ([]dt
 	return statsItems, nil
 }
 
+// CountRecentReviewsOfAuthor returns, per reviewer, on how many of the
+// author's last window pull requests they were assigned.
+func (r *ReviewerHistoryRepo) CountRecentReviewsOfAuthor(ctx context.Context, authorID string, excludePRID string, window int) (map[string]int, error) {
+	var rows []struct {
+		UserID  string
+		Reviews int
+	}
+
+	recentPRs := r.db.
+		Model(&models.PullRequest{}).
+		Select("pull_request_id").
+		Where("author_id = ? AND pull_request_id <> ?", authorID, excludePRID).
+		Order("created_at DESC").
+		Limit(window)
+
+	err := r.db.WithContext(ctx).
+		Model(&models.ReviewerAssignmentHistory{}).
+		Select("user_id, COUNT(DISTINCT pr_id) AS reviews").
+		Where("event_type IN ? AND pr_id IN (?)", models.AssignmentEvents, recentPRs).
+		Group("user_id").
+		Scan(&rows).Error
+	if err != nil {
+		log.Printf("Failed to count recent reviewers of author %v: %v\n", authorID, err)
+		return nil, err
+	}
+
+	result := make(map[string]int, len(rows))
+	for _, row := range rows {
+		result[row.UserID] = row.Reviews
+	}
+	return result, nil
+}
+
 func (r *ReviewerHistoryRepo) WithTx(tx *gorm.DB) ReviewerHistoryRepository {
 	return &ReviewerHistoryRepo{db: tx}
 }
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	"github.com/mink0ff/pr_service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SnapshotRepo struct {
+	db *gorm.DB
+}
+
+func NewSnapshotRepo(db *gorm.DB) SnapshotRepository {
+	return &SnapshotRepo{db: db}
+}
+
+func (r *SnapshotRepo) WithTx(tx *gorm.DB) SnapshotRepository {
+	return &SnapshotRepo{db: tx}
+}
+
+// Snapshots include soft-deleted teams, users and pull requests.
+func (r *SnapshotRepo) StreamTeams(ctx context.Context, fn func(models.Team) error) error {
+	return streamRows(r.db.WithContext(ctx).Unscoped().Model(&models.Team{}).Order("team_id"), fn)
+}
+
+func (r *SnapshotRepo) StreamUsers(ctx context.Context, fn func(models.User) error) error {
+	return streamRows(r.db.WithContext(ctx).Unscoped().Model(&models.User{}).Order("user_id"), fn)
+}
+
+func (r *SnapshotRepo) StreamMemberships(ctx context.Context, fn func(models.TeamMembership) error) error {
+	return streamRows(r.db.WithContext(ctx).Model(&models.TeamMembership{}).Order("user_id, team_id"), fn)
+}
+
+func (r *SnapshotRepo) StreamPullRequests(ctx context.Context, fn func(models.PullRequest) error) error {
+	return streamRows(r.db.WithContext(ctx).Unscoped().Model(&models.PullRequest{}).Order("pull_request_id"), fn)
+}
+
+func (r *SnapshotRepo) StreamReviewers(ctx context.Context, fn func(models.PRReviewer) error) error {
+	return streamRows(r.db.WithContext(ctx).Model(&models.PRReviewer{}).Order("pull_request_id, reviewer_id"), fn)
+}
+
+func (r *SnapshotRepo) StreamHistory(ctx context.Context, fn func(models.ReviewerAssignmentHistory) error) error {
+	return streamRows(r.db.WithContext(ctx).Model(&models.ReviewerAssignmentHistory{}).Order("created_at, assigment_history_id"), fn)
+}
+
+func (r *SnapshotRepo) StreamTeamSettings(ctx context.Context, fn func(models.TeamSettings) error) error {
+	return streamRows(r.db.WithContext(ctx).Model(&models.TeamSettings{}).Order("team_id"), fn)
+}
+
+func (r *SnapshotRepo) StreamOwnershipRules(ctx context.Context, fn func(models.OwnershipRule) error) error {
+	return streamRows(r.db.WithContext(ctx).Model(&models.OwnershipRule{}).Order("team_id, position"), fn)
+}
+
+func (r *SnapshotRepo) StreamUserSkills(ctx context.Context, fn func(models.UserSkill) error) error {
+	return streamRows(r.db.WithContext(ctx).Model(&models.UserSkill{}).Order("user_id, tag"), fn)
+}
+
+func (r *SnapshotRepo) StreamNotificationSettings(ctx context.Context, fn func(models.UserNotificationSettings) error) error {
+	return streamRows(r.db.WithContext(ctx).Model(&models.UserNotificationSettings{}).Order("user_id"), fn)
+}
+
+func (r *SnapshotRepo) StreamLabels(ctx context.Context, fn func(models.PRLabel) error) error {
+	return streamRows(r.db.WithContext(ctx).Model(&models.PRLabel{}).Order("pull_request_id, label"), fn)
+}
+
+func (r *SnapshotRepo) StreamAuditEvents(ctx context.Context, fn func(models.TeamAuditEvent) error) error {
+	return streamRows(r.db.WithContext(ctx).Model(&models.TeamAuditEvent{}).Order("created_at, event_id"), fn)
+}
+
+func (r *SnapshotRepo) StreamAPITokens(ctx context.Context, fn func(models.APIToken) error) error {
+	return streamRows(r.db.WithContext(ctx).Model(&models.APIToken{}).Order("name"), fn)
+}
+
+// streamRows scans one row at a time so exports don't hold whole tables in
+// memory.
+func streamRows[T any](query *gorm.DB, fn func(T) error) error {
+	rows, err := query.Rows()
+	if err != nil {
+		log.Printf("Failed to start export query: %v\n", err)
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row T
+		if err := query.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// IsEmpty reports whether none of the snapshot tables has rows. api_tokens
+// is left out: a restore over HTTP is itself made with a token, so restored
+// tokens are merged into the existing ones instead.
+func (r *SnapshotRepo) IsEmpty(ctx context.Context) (bool, error) {
+	var nonEmpty bool
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT EXISTS (SELECT 1 FROM teams)
+		    OR EXISTS (SELECT 1 FROM users)
+		    OR EXISTS (SELECT 1 FROM team_memberships)
+		    OR EXISTS (SELECT 1 FROM team_settings)
+		    OR EXISTS (SELECT 1 FROM ownership_rules)
+		    OR EXISTS (SELECT 1 FROM user_skills)
+		    OR EXISTS (SELECT 1 FROM user_notification_settings)
+		    OR EXISTS (SELECT 1 FROM pull_requests)
+		    OR EXISTS (SELECT 1 FROM pr_reviewers)
+		    OR EXISTS (SELECT 1 FROM pr_labels)
+		    OR EXISTS (SELECT 1 FROM reviewer_assignment_histories)
+		    OR EXISTS (SELECT 1 FROM team_audit_events)
+	`).Scan(&nonEmpty).Error
+	if err != nil {
+		log.Printf("Failed to check whether the database is empty: %v\n", err)
+		return false, err
+	}
+	return !nonEmpty, nil
+}
+
+// SchemaVersion returns the migration version recorded by golang-migrate.
+func (r *SnapshotRepo) SchemaVersion(ctx context.Context) (uint, error) {
+	var version uint
+	err := r.db.WithContext(ctx).Raw("SELECT version FROM schema_migrations LIMIT 1").Scan(&version).Error
+	if err != nil {
+		log.Printf("Failed to read schema version: %v\n", err)
+	}
+	return version, err
+}
+
+// Insert writes a slice of models in batches; rows must point to a slice.
+func (r *SnapshotRepo) Insert(ctx context.Context, rows any, batchSize int) error {
+	err := r.db.WithContext(ctx).CreateInBatches(rows, batchSize).Error
+	if err != nil {
+		log.Printf("Failed to restore rows: %v\n", err)
+	}
+	return err
+}
+
+// InsertNew is Insert that skips rows conflicting with existing ones.
+func (r *SnapshotRepo) InsertNew(ctx context.Context, rows any, batchSize int) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(rows, batchSize).Error
+	if err != nil {
+		log.Printf("Failed to restore rows: %v\n", err)
+	}
+	return err
+}
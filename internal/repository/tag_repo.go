@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	"github.com/mink0ff/pr_service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagRepo struct {
+	db *gorm.DB
+}
+
+func NewTagRepo(db *gorm.DB) TagRepository {
+	return &TagRepo{db: db}
+}
+
+func (r *TagRepo) ListUserSkills(ctx context.Context, userID string) ([]string, error) {
+	var tags []string
+	err := r.db.WithContext(ctx).
+		Model(&models.UserSkill{}).
+		Where("user_id = ?", userID).
+		Order("tag").
+		Pluck("tag", &tags).Error
+	if err != nil {
+		log.Printf("Failed to list skills of user %v: %v\n", userID, err)
+	}
+	return tags, err
+}
+
+func (r *TagRepo) ListSkillsByUsers(ctx context.Context, userIDs []string) (map[string][]string, error) {
+	result := make(map[string][]string, len(userIDs))
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	var skills []models.UserSkill
+	err := r.db.WithContext(ctx).
+		Where("user_id IN ?", userIDs).
+		Order("user_id, tag").
+		Find(&skills).Error
+	if err != nil {
+		log.Printf("Failed to list skills of %d users: %v\n", len(userIDs), err)
+		return nil, err
+	}
+
+	for _, s := range skills {
+		result[s.UserID] = append(result[s.UserID], s.Tag)
+	}
+	return result, nil
+}
+
+func (r *TagRepo) AddUserSkills(ctx context.Context, userID string, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	rows := make([]models.UserSkill, len(tags))
+	for i, tag := range tags {
+		rows[i] = models.UserSkill{UserID: userID, Tag: tag}
+	}
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&rows).Error
+	if err != nil {
+		log.Printf("Failed to add skills to user %v: %v\n", userID, err)
+	} else {
+		log.Printf("Skills %v added to user %v\n", tags, userID)
+	}
+	return err
+}
+
+func (r *TagRepo) RemoveUserSkills(ctx context.Context, userID string, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND tag IN ?", userID, tags).
+		Delete(&models.UserSkill{}).Error
+	if err != nil {
+		log.Printf("Failed to remove skills from user %v: %v\n", userID, err)
+	} else {
+		log.Printf("Skills %v removed from user %v\n", tags, userID)
+	}
+	return err
+}
+
+func (r *TagRepo) ListPRLabels(ctx context.Context, prID string) ([]string, error) {
+	var labels []string
+	err := r.db.WithContext(ctx).
+		Model(&models.PRLabel{}).
+		Where("pull_request_id = ?", prID).
+		Order("label").
+		Pluck("label", &labels).Error
+	if err != nil {
+		log.Printf("Failed to list labels of PR %v: %v\n", prID, err)
+	}
+	return labels, err
+}
+
+func (r *TagRepo) AddPRLabels(ctx context.Context, prID string, labels []string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	rows := make([]models.PRLabel, len(labels))
+	for i, label := range labels {
+		rows[i] = models.PRLabel{PullRequestID: prID, Label: label}
+	}
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&rows).Error
+	if err != nil {
+		log.Printf("Failed to add labels to PR %v: %v\n", prID, err)
+	} else {
+		log.Printf("Labels %v added to PR %v\n", labels, prID)
+	}
+	return err
+}
+
+func (r *TagRepo) RemovePRLabels(ctx context.Context, prID string, labels []string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Where("pull_request_id = ? AND label IN ?", prID, labels).
+		Delete(&models.PRLabel{}).Error
+	if err != nil {
+		log.Printf("Failed to remove labels from PR %v: %v\n", prID, err)
+	} else {
+		log.Printf("Labels %v removed from PR %v\n", labels, prID)
+	}
+	return err
+}
+
+func (r *TagRepo) WithTx(tx *gorm.DB) TagRepository {
+	return &TagRepo{db: tx}
+}
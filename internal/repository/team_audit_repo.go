@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/mink0ff/pr_service/internal/models"
+	"gorm.io/gorm"
+)
+
+type TeamAuditRepo struct {
+	db *gorm.DB
+}
+
+func NewTeamAuditRepo(db *gorm.DB) TeamAuditRepository {
+	return &TeamAuditRepo{db: db}
+}
+
+func (r *TeamAuditRepo) Add(ctx context.Context, events ...models.TeamAuditEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	err := r.db.WithContext(ctx).Create(&events).Error
+	if err != nil {
+		log.Printf("Failed to write %d team audit events: %v\n", len(events), err)
+	}
+	return err
+}
+
+// ListByTeam returns the newest events first.
+func (r *TeamAuditRepo) ListByTeam(ctx context.Context, teamID uuid.UUID, limit int) ([]models.TeamAuditEvent, error) {
+	var events []models.TeamAuditEvent
+	err := r.db.WithContext(ctx).
+		Where("team_id = ?", teamID).
+		Order("created_at DESC, event_id").
+		Limit(limit).
+		Find(&events).Error
+	if err != nil {
+		log.Printf("Failed to list audit events for team %v: %v\n", teamID, err)
+	}
+	return events, err
+}
+
+func (r *TeamAuditRepo) WithTx(tx *gorm.DB) TeamAuditRepository {
+	return &TeamAuditRepo{db: tx}
+}
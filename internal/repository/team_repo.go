@@ -58,10 +58,56 @@ func (r *TeamRepo) GetByName(ctx context.Context, teamName string) (*models.Team
 	return &team, err
 }
 
+func (r *TeamRepo) Update(ctx context.Context, team models.Team) error {
+	err := r.db.WithContext(ctx).
+		Where("team_id = ?", team.TeamID).
+		Save(&team).Error
+	if err != nil {
+		log.Printf("Failed to update team %v: %v\n", team.TeamName, err)
+	} else {
+		log.Printf("Team %v updated successfully\n", team.TeamName)
+	}
+	return err
+}
+
+// Delete marks the team deleted. Its settings and ownership rules stay until
+// the retention job removes the team for good.
+func (r *TeamRepo) Delete(ctx context.Context, teamID uuid.UUID) error {
+	err := r.db.WithContext(ctx).
+		Where("team_id = ?", teamID).
+		Delete(&models.Team{}).Error
+	if err != nil {
+		log.Printf("Failed to delete team %v: %v\n", teamID, err)
+	} else {
+		log.Printf("Team %v deleted\n", teamID)
+	}
+	return err
+}
+
+func (r *TeamRepo) List(ctx context.Context) ([]models.Team, error) {
+	var teams []models.Team
+	err := r.db.WithContext(ctx).
+		Order("team_name").
+		Find(&teams).Error
+	if err != nil {
+		log.Printf("Failed to list teams: %v\n", err)
+	} else {
+		log.Printf("Found %d teams\n", len(teams))
+	}
+	return teams, err
+}
+
+// ListUsersByTeam returns every member of the team, secondary ones included.
+// TeamID is still the user's primary team and IsActive is the state within
+// this team: the user's own flag and the membership's together.
 func (r *TeamRepo) ListUsersByTeam(ctx context.Context, teamID uuid.UUID) ([]models.User, error) {
 	var users []models.User
 	err := r.db.WithContext(ctx).
-		Where("team_id = ?", teamID).
+		Model(&models.User{}).
+		Select("users.user_id, users.username, users.team_id, users.is_active AND m.is_active AS is_active, users.max_open_reviews").
+		Joins("JOIN team_memberships m ON m.user_id = users.user_id").
+		Where("m.team_id = ?", teamID).
+		Order("m.is_primary DESC, users.user_id").
 		Find(&users).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		log.Printf("No users found for team %v\n", teamID)
@@ -75,6 +121,90 @@ func (r *TeamRepo) ListUsersByTeam(ctx context.Context, teamID uuid.UUID) ([]mod
 	return users, err
 }
 
+// ListAncestors returns the parent chain of the team, nearest first. The path
+// array stops the recursion should the data ever contain a cycle. The chain
+// ends at a deleted team.
+func (r *TeamRepo) ListAncestors(ctx context.Context, teamID uuid.UUID) ([]models.Team, error) {
+	var teams []models.Team
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE ancestors AS (
+			SELECT p.*, 1 AS depth, ARRAY[t.team_id, p.team_id] AS path
+			FROM teams t
+			JOIN teams p ON p.team_id = t.parent_team_id AND p.deleted_at IS NULL
+			WHERE t.team_id = ?
+			UNION ALL
+			SELECT p.*, a.depth + 1, a.path || p.team_id
+			FROM ancestors a
+			JOIN teams p ON p.team_id = a.parent_team_id AND p.deleted_at IS NULL
+			WHERE NOT p.team_id = ANY(a.path)
+		)
+		SELECT team_id, team_name, webhook_url, parent_team_id FROM ancestors ORDER BY depth`, teamID).
+		Scan(&teams).Error
+	if err != nil {
+		log.Printf("Failed to list ancestors of team %v: %v\n", teamID, err)
+	}
+	return teams, err
+}
+
+// ListSubtree returns the team and all live teams below it, parents before
+// their children.
+func (r *TeamRepo) ListSubtree(ctx context.Context, teamID uuid.UUID) ([]models.Team, error) {
+	var teams []models.Team
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE subtree AS (
+			SELECT t.*, 0 AS depth, ARRAY[t.team_id] AS path
+			FROM teams t
+			WHERE t.team_id = ? AND t.deleted_at IS NULL
+			UNION ALL
+			SELECT c.*, s.depth + 1, s.path || c.team_id
+			FROM subtree s
+			JOIN teams c ON c.parent_team_id = s.team_id AND c.deleted_at IS NULL
+			WHERE NOT c.team_id = ANY(s.path)
+		)
+		SELECT team_id, team_name, webhook_url, parent_team_id FROM subtree ORDER BY depth, team_name`, teamID).
+		Scan(&teams).Error
+	if err != nil {
+		log.Printf("Failed to list subtree of team %v: %v\n", teamID, err)
+	}
+	return teams, err
+}
+
+// LockHierarchy serializes changes to team parents for the rest of the
+// transaction, so two concurrent moves cannot together form a cycle.
+func (r *TeamRepo) LockHierarchy(ctx context.Context) error {
+	return r.db.WithContext(ctx).Exec("SELECT pg_advisory_xact_lock(hashtext('teams.parent_team_id'))").Error
+}
+
+// CountTeamActivity counts members, review assignments and open reviews of
+// each given team's own members. Assignments of deleted members still count.
+func (r *TeamRepo) CountTeamActivity(ctx context.Context, teamIDs []uuid.UUID) ([]models.TeamActivity, error) {
+	if len(teamIDs) == 0 {
+		return nil, nil
+	}
+
+	var items []models.TeamActivity
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT t.team_id,
+			(SELECT COUNT(*) FROM users u WHERE u.team_id = t.team_id AND u.deleted_at IS NULL) AS members,
+			(SELECT COUNT(*) FROM users u WHERE u.team_id = t.team_id AND u.deleted_at IS NULL AND u.is_active) AS active_members,
+			(SELECT COUNT(*)
+				FROM reviewer_assignment_histories h
+				JOIN users u ON u.user_id = h.user_id
+				WHERE u.team_id = t.team_id AND h.event_type IN ?) AS assignments,
+			(SELECT COUNT(*)
+				FROM pr_reviewers r
+				JOIN pull_requests p ON p.pull_request_id = r.pull_request_id
+				JOIN users u ON u.user_id = r.reviewer_id
+				WHERE u.team_id = t.team_id AND p.status = ? AND p.deleted_at IS NULL) AS open_reviews
+		FROM teams t
+		WHERE t.team_id IN ?`, models.AssignmentEvents, models.PROpen, teamIDs).
+		Scan(&items).Error
+	if err != nil {
+		log.Printf("Failed to count activity of %d teams: %v\n", len(teamIDs), err)
+	}
+	return items, err
+}
+
 func (r *TeamRepo) WithTx(tx *gorm.DB) TeamRepository {
 	log.Println("Creating TeamRepository with transaction")
 	return &TeamRepo{db: tx}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/mink0ff/pr_service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TeamSettingsRepo struct {
+	db *gorm.DB
+}
+
+func NewTeamSettingsRepo(db *gorm.DB) TeamSettingsRepository {
+	return &TeamSettingsRepo{db: db}
+}
+
+func (r *TeamSettingsRepo) Get(ctx context.Context, teamID uuid.UUID) (*models.TeamSettings, error) {
+	var settings models.TeamSettings
+	err := r.db.WithContext(ctx).First(&settings, "team_id = ?", teamID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("Settings for team %v not found\n", teamID)
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("Error fetching settings for team %v: %v\n", teamID, err)
+	}
+	return &settings, err
+}
+
+func (r *TeamSettingsRepo) Upsert(ctx context.Context, settings models.TeamSettings) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "team_id"}},
+			UpdateAll: true,
+		}).
+		Create(&settings).Error
+	if err != nil {
+		log.Printf("Failed to save settings for team %v: %v\n", settings.TeamID, err)
+	} else {
+		log.Printf("Settings for team %v saved successfully\n", settings.TeamID)
+	}
+	return err
+}
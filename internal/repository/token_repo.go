@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/mink0ff/pr_service/internal/models"
+	"gorm.io/gorm"
+)
+
+type TokenRepo struct {
+	db *gorm.DB
+}
+
+func NewTokenRepo(db *gorm.DB) TokenRepository {
+	return &TokenRepo{db: db}
+}
+
+func (r *TokenRepo) Create(ctx context.Context, token models.APIToken) error {
+	err := r.db.WithContext(ctx).Create(&token).Error
+	if err != nil {
+		log.Printf("Failed to create API token %v: %v\n", token.Name, err)
+	}
+	return err
+}
+
+func (r *TokenRepo) GetByName(ctx context.Context, name string) (*models.APIToken, error) {
+	var token models.APIToken
+	err := r.db.WithContext(ctx).First(&token, "name = ?", name).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("Error fetching API token %v: %v\n", name, err)
+	}
+	return &token, err
+}
+
+func (r *TokenRepo) GetByHash(ctx context.Context, hash string) (*models.APIToken, error) {
+	var token models.APIToken
+	err := r.db.WithContext(ctx).First(&token, "token_hash = ?", hash).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("Error fetching API token by hash: %v\n", err)
+	}
+	return &token, err
+}
+
+func (r *TokenRepo) Delete(ctx context.Context, name string) (bool, error) {
+	res := r.db.WithContext(ctx).Where("name = ?", name).Delete(&models.APIToken{})
+	if res.Error != nil {
+		log.Printf("Failed to delete API token %v: %v\n", name, res.Error)
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *TokenRepo) MarkUsed(ctx context.Context, tokenID string, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.APIToken{}).
+		Where("token_id = ?", tokenID).
+		Update("last_used_at", at).Error
+}
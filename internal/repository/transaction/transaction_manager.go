@@ -2,6 +2,7 @@ package transaction
 
 import (
 	"context"
+	"database/sql"
 
 	"gorm.io/gorm"
 )
@@ -19,3 +20,11 @@ func (t *Manager) Do(ctx context.Context, fn func(ctx context.Context, tx *gorm.
 		return fn(ctx, tx)
 	})
 }
+
+// DoSnapshot runs fn in a read-only REPEATABLE READ transaction, so every
+// query inside it sees the database as of the first one.
+func (t *Manager) DoSnapshot(ctx context.Context, fn func(ctx context.Context, tx *gorm.DB) error) error {
+	return t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(ctx, tx)
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+}
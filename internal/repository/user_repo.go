@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"log"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/mink0ff/pr_service/internal/models"
@@ -19,8 +20,15 @@ func NewUserRepo(db *gorm.DB) UserRepository {
 	return &UserRepo{db: db}
 }
 
+// Create inserts the user together with the membership in their primary
+// team.
 func (r *UserRepo) Create(ctx context.Context, user models.User) error {
-	err := r.db.WithContext(ctx).Create(&user).Error
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		return syncPrimaryMembership(tx, user)
+	})
 	if err != nil {
 		log.Printf("Failed to create user %v: %v\n", user.UserID, err)
 	} else {
@@ -50,10 +58,33 @@ func (r *UserRepo) GetByID(ctx context.Context, id string) (*models.User, error)
 	return &user, err
 }
 
-func (r *UserRepo) Update(ctx context.Context, user models.User) error {
+func (r *UserRepo) ListByIDs(ctx context.Context, ids []string) ([]models.User, error) {
+	var users []models.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+
 	err := r.db.WithContext(ctx).
-		Where("user_id = ?", user.UserID).
-		Save(&user).Error
+		Where("user_id IN ?", ids).
+		Find(&users).Error
+	if err != nil {
+		log.Printf("Failed to list users by ids: %v\n", err)
+	} else {
+		log.Printf("Found %d of %d requested users\n", len(users), len(ids))
+	}
+	return users, err
+}
+
+// Update saves the user. A changed TeamID moves the primary membership: the
+// old primary team is left and an existing membership in the new team
+// becomes primary.
+func (r *UserRepo) Update(ctx context.Context, user models.User) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", user.UserID).Save(&user).Error; err != nil {
+			return err
+		}
+		return syncPrimaryMembership(tx, user)
+	})
 	if err != nil {
 		log.Printf("Failed to update user %v: %v\n", user.UserID, err)
 	} else {
@@ -62,11 +93,49 @@ func (r *UserRepo) Update(ctx context.Context, user models.User) error {
 	return err
 }
 
+func syncPrimaryMembership(tx *gorm.DB, user models.User) error {
+	err := tx.Where("user_id = ? AND is_primary AND team_id <> ?", user.UserID, user.TeamID).
+		Delete(&models.TeamMembership{}).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "team_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"is_primary", "is_active"}),
+	}).Create(&models.TeamMembership{
+		UserID:    user.UserID,
+		TeamID:    user.TeamID,
+		IsPrimary: true,
+		IsActive:  user.IsActive,
+		CreatedAt: time.Now(),
+	}).Error
+}
+
+// Delete marks the user deleted. Their pull requests, reviews and history
+// are kept, so stats don't change.
+func (r *UserRepo) Delete(ctx context.Context, id string) error {
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", id).
+		Delete(&models.User{}).Error
+	if err != nil {
+		log.Printf("Failed to delete user %v: %v\n", id, err)
+	} else {
+		log.Printf("User %v deleted\n", id)
+	}
+	return err
+}
+
+// ListActiveByTeam returns the active members of the team, secondary ones
+// included. A secondary member counts only when both the user and their
+// membership are active.
 func (r *UserRepo) ListActiveByTeam(ctx context.Context, teamID uuid.UUID) ([]models.User, error) {
 	var users []models.User
 	err := r.db.WithContext(ctx).
-		Where("team_id = ? AND is_active = TRUE", teamID).
-		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Joins("JOIN team_memberships m ON m.user_id = users.user_id").
+		Where("m.team_id = ? AND m.is_active AND users.is_active", teamID).
+		Order("users.user_id").
+		Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "users"}}).
 		Find(&users).Error
 	if err != nil {
 		log.Printf("Failed to list active users for team %v: %v\n", teamID, err)
@@ -76,6 +145,61 @@ func (r *UserRepo) ListActiveByTeam(ctx context.Context, teamID uuid.UUID) ([]mo
 	return users, err
 }
 
+// ListMemberships returns the user's memberships, the primary one first.
+func (r *UserRepo) ListMemberships(ctx context.Context, userID string) ([]models.TeamMembership, error) {
+	var memberships []models.TeamMembership
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("is_primary DESC, created_at, team_id").
+		Find(&memberships).Error
+	if err != nil {
+		log.Printf("Failed to list memberships of user %v: %v\n", userID, err)
+	}
+	return memberships, err
+}
+
+// AddMembership adds a secondary membership or updates the state of an
+// existing one. The primary membership is managed through Create and Update.
+func (r *UserRepo) AddMembership(ctx context.Context, membership models.TeamMembership) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "team_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"is_active"}),
+		}).
+		Create(&membership).Error
+	if err != nil {
+		log.Printf("Failed to add user %v to team %v: %v\n", membership.UserID, membership.TeamID, err)
+	} else {
+		log.Printf("User %v added to team %v\n", membership.UserID, membership.TeamID)
+	}
+	return err
+}
+
+// SetMembershipActive changes a secondary membership; the primary one follows
+// the user's is_active.
+func (r *UserRepo) SetMembershipActive(ctx context.Context, userID string, teamID uuid.UUID, active bool) error {
+	err := r.db.WithContext(ctx).
+		Model(&models.TeamMembership{}).
+		Where("user_id = ? AND team_id = ? AND NOT is_primary", userID, teamID).
+		Update("is_active", active).Error
+	if err != nil {
+		log.Printf("Failed to set membership of user %v in team %v active=%v: %v\n", userID, teamID, active, err)
+	}
+	return err
+}
+
+func (r *UserRepo) RemoveMembership(ctx context.Context, userID string, teamID uuid.UUID) error {
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND team_id = ? AND NOT is_primary", userID, teamID).
+		Delete(&models.TeamMembership{}).Error
+	if err != nil {
+		log.Printf("Failed to remove user %v from team %v: %v\n", userID, teamID, err)
+	} else {
+		log.Printf("User %v removed from team %v\n", userID, teamID)
+	}
+	return err
+}
+
 func (r *UserRepo) ListReviewPRs(ctx context.Context, userID string) ([]models.PullRequest, error) {
 	var prs []models.PullRequest
 	err := r.db.WithContext(ctx).
@@ -90,6 +214,89 @@ func (r *UserRepo) ListReviewPRs(ctx context.Context, userID string) ([]models.P
 	return prs, err
 }
 
+// List returns one page of users ordered by user_id and the number of users
+// matching the filter across all pages.
+func (r *UserRepo) List(ctx context.Context, filter UserFilter) ([]models.User, int64, error) {
+	matches := func(db *gorm.DB) *gorm.DB {
+		if filter.TeamID != nil {
+			db = db.Where("EXISTS (SELECT 1 FROM team_memberships m WHERE m.user_id = users.user_id AND m.team_id = ?)", *filter.TeamID)
+		}
+		if filter.IsActive != nil {
+			db = db.Where("is_active = ?", *filter.IsActive)
+		}
+		return db
+	}
+
+	var total int64
+	err := r.db.WithContext(ctx).
+		Model(&models.User{}).
+		Scopes(matches).
+		Count(&total).Error
+	if err != nil {
+		log.Printf("Failed to count users: %v\n", err)
+		return nil, 0, err
+	}
+
+	var users []models.User
+	err = r.db.WithContext(ctx).
+		Scopes(matches).
+		Order("user_id").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&users).Error
+	if err != nil {
+		log.Printf("Failed to list users: %v\n", err)
+		return nil, 0, err
+	}
+
+	log.Printf("Listed %d of %d users\n", len(users), total)
+	return users, total, nil
+}
+
+func (r *UserRepo) ListAuthoredPRs(ctx context.Context, userID string) ([]models.PullRequest, error) {
+	var prs []models.PullRequest
+	err := r.db.WithContext(ctx).
+		Where("author_id = ?", userID).
+		Order("created_at").
+		Find(&prs).Error
+	if err != nil {
+		log.Printf("Failed to list PRs authored by %v: %v\n", userID, err)
+	} else {
+		log.Printf("Found %d PRs authored by %v\n", len(prs), userID)
+	}
+	return prs, err
+}
+
+// CountOpenReviews returns, per user, on how many OPEN pull requests they are
+// currently a reviewer. Users without open reviews are absent from the map.
+func (r *UserRepo) CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error) {
+	result := make(map[string]int, len(userIDs))
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		ReviewerID string
+		Reviews    int
+	}
+	err := r.db.WithContext(ctx).
+		Table("pr_reviewers prr").
+		Select("prr.reviewer_id, COUNT(*) AS reviews").
+		Joins("JOIN pull_requests pr ON pr.pull_request_id = prr.pull_request_id").
+		Where("pr.status = ? AND pr.deleted_at IS NULL AND prr.reviewer_id IN ?", models.PROpen, userIDs).
+		Group("prr.reviewer_id").
+		Scan(&rows).Error
+	if err != nil {
+		log.Printf("Failed to count open reviews: %v\n", err)
+		return nil, err
+	}
+
+	for _, row := range rows {
+		result[row.ReviewerID] = row.Reviews
+	}
+	return result, nil
+}
+
 func (r *UserRepo) WithTx(tx *gorm.DB) UserRepository {
 	log.Println("Creating UserRepository with transaction")
 	return &UserRepo{db: tx}
@@ -2,16 +2,115 @@ package service
 
 import (
 	"errors"
+	"net/http"
 )
 
+// Error is a domain error with everything the API needs to report it. The
+// package-level values are sentinels: compare with errors.Is and attach a
+// cause with Wrap or extra data with WithDetails, both of which return a
+// copy that still matches the sentinel.
+type Error struct {
+	Code    string
+	Status  int
+	Message string
+	Details map[string]any
+	cause   error
+}
+
+func newError(status int, code, message string) *Error {
+	return &Error{Code: code, Status: status, Message: message}
+}
+
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return e.Message + ": " + e.cause.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Cause is the underlying error, if any. Causes are for logs; they are not
+// shown to API clients.
+func (e *Error) Cause() error {
+	return e.cause
+}
+
+func (e *Error) Wrap(cause error) *Error {
+	out := *e
+	out.cause = cause
+	return &out
+}
+
+func (e *Error) WithDetails(key string, value any) *Error {
+	out := *e
+	out.Details = make(map[string]any, len(e.Details)+1)
+	for k, v := range e.Details {
+		out.Details[k] = v
+	}
+	out.Details[key] = value
+	return &out
+}
+
 var (
-	ErrTeamExists          = errors.New("team already exists")
-	ErrTeamNotFound        = errors.New("team not found")
-	ErrUserExists          = errors.New("user already exists")
-	ErrUserNotFound        = errors.New("user not found")
-	ErrPRExists            = errors.New("pull request already exists")
-	ErrPRNotFound          = errors.New("pull request not found")
-	ErrPRMerged            = errors.New("pull request already merged")
-	ErrReviewerNotAssigned = errors.New("reviewer is not assigned")
-	ErrNoCandidate         = errors.New("no active candidate for reassignment")
+	ErrTeamExists          = newError(http.StatusConflict, "TEAM_EXISTS", "team already exists")
+	ErrTeamNotFound        = newError(http.StatusNotFound, "TEAM_NOT_FOUND", "team not found")
+	ErrTeamNotEmpty        = newError(http.StatusConflict, "TEAM_NOT_EMPTY", "team still has members")
+	ErrTeamHasChildren     = newError(http.StatusConflict, "TEAM_HAS_CHILDREN", "team still has sub-teams")
+	ErrTeamCycle           = newError(http.StatusConflict, "TEAM_CYCLE", "team cannot be placed under itself or its sub-team")
+	ErrUserInOtherTeam     = newError(http.StatusConflict, "USER_IN_OTHER_TEAM", "user belongs to another team, move them instead")
+	ErrAlreadyInTeam       = newError(http.StatusConflict, "ALREADY_IN_TEAM", "user is already in this team")
+	ErrPrimaryMembership   = newError(http.StatusConflict, "PRIMARY_MEMBERSHIP", "this is the user's primary team, use moveMember or removeMember")
+	ErrUserNotInTeam       = newError(http.StatusNotFound, "USER_NOT_IN_TEAM", "user is not a member of this team")
+	ErrUserExists          = newError(http.StatusConflict, "USER_EXISTS", "user already exists")
+	ErrUserNotFound        = newError(http.StatusNotFound, "USER_NOT_FOUND", "user not found")
+	ErrPRExists            = newError(http.StatusConflict, "PR_EXISTS", "pull request already exists")
+	ErrPRNotFound          = newError(http.StatusNotFound, "PR_NOT_FOUND", "pull request not found")
+	ErrPRMerged            = newError(http.StatusConflict, "PR_MERGED", "pull request already merged")
+	ErrReviewerNotAssigned = newError(http.StatusBadRequest, "NOT_ASSIGNED", "reviewer is not assigned")
+	ErrNoCandidate         = newError(http.StatusConflict, "NO_CANDIDATE", "no active candidate for reassignment")
+	ErrReviewerPinned      = newError(http.StatusConflict, "REVIEWER_PINNED", "reviewer is pinned and cannot be reassigned")
+	ErrAlreadyAssigned     = newError(http.StatusConflict, "ALREADY_ASSIGNED", "reviewer is already assigned")
+	ErrReviewerInactive    = newError(http.StatusBadRequest, "REVIEWER_INACTIVE", "reviewer is not active")
+	ErrAuthorAsReviewer    = newError(http.StatusBadRequest, "AUTHOR_AS_REVIEWER", "author cannot review their own pull request")
+	ErrTooManyReviewers    = newError(http.StatusConflict, "TOO_MANY_REVIEWERS", "pull request already has the maximum number of reviewers")
+	ErrInvalidReviewLimit  = newError(http.StatusBadRequest, "INVALID_REVIEW_LIMIT", "max open reviews must be between 0 and 100")
+	ErrReviewersAtCapacity = newError(http.StatusConflict, "REVIEWERS_AT_CAPACITY", "every candidate reviewer is at their open review limit")
+	ErrInvalidImportFormat = newError(http.StatusBadRequest, "INVALID_IMPORT_FORMAT", "import format must be ndjson or csv")
+	ErrInvalidExportFormat = newError(http.StatusBadRequest, "INVALID_EXPORT_FORMAT", "export format must be ndjson, csv or snapshot")
+	ErrUnknownExportEntity = newError(http.StatusBadRequest, "UNKNOWN_EXPORT_ENTITY", "entity must be one of teams, users, team_memberships, team_settings, ownership_rules, user_skills, user_notification_settings, pull_requests, pr_reviewers, pr_labels, assignment_history, team_audit_events, api_tokens")
+	ErrUnknownDeleted      = newError(http.StatusBadRequest, "UNKNOWN_DELETED_ENTITY", "entity must be one of teams, users, pull_requests")
+	ErrNotDeleted          = newError(http.StatusNotFound, "NOT_DELETED", "no deleted record with this id")
+	ErrParentDeleted       = newError(http.StatusConflict, "PARENT_DELETED", "record depends on a deleted team or user, restore that first")
+	ErrInvalidSnapshot     = newError(http.StatusBadRequest, "INVALID_SNAPSHOT", "invalid snapshot archive")
+	ErrSchemaMismatch      = newError(http.StatusConflict, "SCHEMA_MISMATCH", "snapshot was taken on a different schema version")
+	ErrDatabaseNotEmpty    = newError(http.StatusConflict, "DATABASE_NOT_EMPTY", "restore requires an empty database")
+	ErrUnauthorized        = newError(http.StatusUnauthorized, "UNAUTHORIZED", "missing, invalid or expired api token")
+	ErrInvalidToken        = newError(http.StatusBadRequest, "INVALID_TOKEN", "token name is required and ttl must not be negative")
+	ErrTokenExists         = newError(http.StatusConflict, "TOKEN_EXISTS", "token with this name already exists")
+	ErrTokenNotFound       = newError(http.StatusNotFound, "TOKEN_NOT_FOUND", "token not found")
+	ErrInvalidWebhookURL   = newError(http.StatusBadRequest, "INVALID_WEBHOOK_URL", "webhook url must be an absolute http(s) url")
+	ErrInvalidEmail        = newError(http.StatusBadRequest, "INVALID_EMAIL", "invalid email address")
+	ErrInvalidFrequency    = newError(http.StatusBadRequest, "INVALID_FREQUENCY", "digest frequency must be DAILY or WEEKLY")
+	ErrInvalidSLA          = newError(http.StatusBadRequest, "INVALID_SLA", "sla hours must be positive and reassignment must come after reminder")
+	ErrJobNotFound         = newError(http.StatusNotFound, "JOB_NOT_FOUND", "job not found")
+	ErrInvalidOwnership    = newError(http.StatusBadRequest, "INVALID_OWNERSHIP_RULE", "invalid ownership rule")
+	ErrUnknownOwner        = newError(http.StatusBadRequest, "UNKNOWN_OWNER", "owner is neither a user nor a team")
+	ErrRuleNotFound        = newError(http.StatusNotFound, "RULE_NOT_FOUND", "ownership rule not found")
+	ErrInvalidRotation     = newError(http.StatusBadRequest, "INVALID_ROTATION", "rotation window must be between 1 and 50 pull requests")
+	ErrInvalidTag          = newError(http.StatusBadRequest, "INVALID_TAG", "tags must be 1-50 chars of a-z, 0-9, '+', '#', '.', '_' or '-'")
 )
+
+// AsError returns the domain error in err's chain, if there is one.
+func AsError(err error) (*Error, bool) {
+	var e *Error
+	ok := errors.As(err, &e)
+	return e, ok
+}
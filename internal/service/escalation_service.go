@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/models"
+	"github.com/mink0ff/pr_service/internal/notifier"
+	"github.com/mink0ff/pr_service/internal/repository"
+)
+
+type EscalationServiceImpl struct {
+	prRepo      repository.PullRequestRepository
+	userRepo    repository.UserRepository
+	teamRepo    repository.TeamRepository
+	historyRepo repository.ReviewerHistoryRepository
+	prService   PRService
+	notifier    notifier.Notifier
+	now         func() time.Time
+}
+
+func NewEscalationService(
+	prRepo repository.PullRequestRepository,
+	userRepo repository.UserRepository,
+	teamRepo repository.TeamRepository,
+	historyRepo repository.ReviewerHistoryRepository,
+	prService PRService,
+	n notifier.Notifier,
+) EscalationService {
+	return &EscalationServiceImpl{
+		prRepo:      prRepo,
+		userRepo:    userRepo,
+		teamRepo:    teamRepo,
+		historyRepo: historyRepo,
+		prService:   prService,
+		notifier:    n,
+		now:         time.Now,
+	}
+}
+
+// EscalateStaleReviews is safe to run concurrently on several replicas:
+// reassignment relies on the row locks taken by ReassignReviewer, so a slot
+// already handled elsewhere surfaces as ErrReviewerNotAssigned, and reminders
+// are claimed with a conditional update on pr_reviewers.reminded_at.
+func (s *EscalationServiceImpl) EscalateStaleReviews(ctx context.Context) (*dto.EscalationReport, error) {
+	now := s.now()
+
+	stale, err := s.prRepo.ListStaleReviews(ctx, now)
+	if err != nil {
+		log.Printf("Failed to list stale reviews: %v", err)
+		return nil, err
+	}
+
+	report := &dto.EscalationReport{}
+	for _, st := range stale {
+		waiting := now.Sub(st.AssignedAt)
+
+		if st.SLAReassignAfterHours != nil && !st.Pinned && waiting >= hours(*st.SLAReassignAfterHours) {
+			outcome, err := s.reassign(ctx, st)
+			if err != nil {
+				return report, err
+			}
+			switch outcome {
+			case reassignDone:
+				report.Reassigned++
+				continue
+			case reassignHandled:
+				report.Skipped++
+				continue
+			}
+		}
+
+		if st.RemindedAt != nil || st.SLARemindAfterHours == nil || waiting < hours(*st.SLARemindAfterHours) {
+			report.Skipped++
+			continue
+		}
+
+		reminded, err := s.remind(ctx, st, now)
+		if err != nil {
+			return report, err
+		}
+		if reminded {
+			report.Reminded++
+		} else {
+			report.Skipped++
+		}
+	}
+
+	log.Printf("Stale reviews escalated: reminded=%d, reassigned=%d, skipped=%d",
+		report.Reminded, report.Reassigned, report.Skipped)
+	return report, nil
+}
+
+type reassignOutcome int
+
+const (
+	// reassignFailed means no replacement was found; the reviewer may still
+	// be reminded.
+	reassignFailed reassignOutcome = iota
+	reassignDone
+	// reassignHandled means another worker or a user already dealt with the
+	// slot, so this run changed nothing.
+	reassignHandled
+)
+
+// reassign records SLA_REASSIGNED in the ReassignReviewer transaction, so a
+// reassignment never lands without its escalation event.
+func (s *EscalationServiceImpl) reassign(ctx context.Context, st models.StaleReview) (reassignOutcome, error) {
+	_, err := s.prService.ReassignReviewer(ctx, &dto.ReassignReviewerRequest{
+		PullRequestID: st.PullRequestID,
+		OldUserID:     st.ReviewerID,
+		SLAEscalation: true,
+	})
+
+	switch {
+	case err == nil:
+		log.Printf("Overdue reviewer %s reassigned on PR %s", st.ReviewerID, st.PullRequestID)
+		return reassignDone, nil
+
+	case errors.Is(err, ErrReviewerNotAssigned), errors.Is(err, ErrPRMerged), errors.Is(err, ErrPRNotFound):
+		log.Printf("Overdue review %s/%s already handled: %v", st.PullRequestID, st.ReviewerID, err)
+		return reassignHandled, nil
+
+	case errors.Is(err, ErrNoCandidate), errors.Is(err, ErrReviewersAtCapacity):
+		log.Printf("No replacement for overdue reviewer %s on PR %s", st.ReviewerID, st.PullRequestID)
+		return reassignFailed, nil
+
+	case errors.Is(err, ErrReviewerPinned):
+		log.Printf("Overdue reviewer %s is pinned to PR %s, only reminding", st.ReviewerID, st.PullRequestID)
+		return reassignFailed, nil
+	}
+
+	log.Printf("Failed to reassign overdue reviewer %s on PR %s: %v", st.ReviewerID, st.PullRequestID, err)
+	return reassignFailed, err
+}
+
+func (s *EscalationServiceImpl) remind(ctx context.Context, st models.StaleReview, now time.Time) (bool, error) {
+	claimed, err := s.prRepo.MarkReminded(ctx, st.PullRequestID, st.ReviewerID, now)
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	if err := s.recordEvent(ctx, st, models.EventSLAReminder); err != nil {
+		return false, err
+	}
+
+	s.notifyReminder(ctx, st)
+	return true, nil
+}
+
+func (s *EscalationServiceImpl) recordEvent(ctx context.Context, st models.StaleReview, eventType models.HistoryEventType) error {
+	event := models.ReviewerAssignmentHistory{
+		AssigmentHistoryID: uuid.New(),
+		PrID:               st.PullRequestID,
+		UserID:             st.ReviewerID,
+		EventType:          eventType,
+		CreatedAt:          s.now(),
+	}
+
+	if err := s.historyRepo.AddEvent(ctx, event); err != nil {
+		log.Printf("Failed to record %s for PR %s: %v", eventType, st.PullRequestID, err)
+		return err
+	}
+	return nil
+}
+
+func (s *EscalationServiceImpl) notifyReminder(ctx context.Context, st models.StaleReview) {
+	teamID, err := uuid.Parse(st.TeamID)
+	if err != nil {
+		return
+	}
+
+	team, err := s.teamRepo.GetByID(ctx, teamID)
+	if err != nil || team == nil || team.WebhookURL == nil {
+		return
+	}
+
+	pr, err := s.prRepo.GetByID(ctx, st.PullRequestID)
+	if err != nil || pr == nil {
+		return
+	}
+
+	reminder := notifier.Reminder{
+		TeamName:        team.TeamName,
+		WebhookURL:      *team.WebhookURL,
+		PullRequestID:   pr.PullRequestID,
+		PullRequestName: pr.PullRequestName,
+		Author:          lookupPerson(ctx, s.userRepo, pr.AuthorID),
+		Reviewer:        lookupPerson(ctx, s.userRepo, st.ReviewerID),
+		AssignedAt:      st.AssignedAt,
+	}
+
+	if err := s.notifier.NotifyReminder(ctx, reminder); err != nil {
+		log.Printf("Failed to send reminder for PR %s: %v", st.PullRequestID, err)
+	}
+}
+
+func hours(n int) time.Duration {
+	return time.Duration(n) * time.Hour
+}
@@ -0,0 +1,636 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/export"
+	"github.com/mink0ff/pr_service/internal/models"
+	"github.com/mink0ff/pr_service/internal/repository"
+	"github.com/mink0ff/pr_service/internal/repository/transaction"
+	"gorm.io/gorm"
+)
+
+const restoreBatchSize = 500
+
+type ExportServiceImpl struct {
+	snapshotRepo repository.SnapshotRepository
+	txManager    *transaction.Manager
+}
+
+func NewExportService(snapshotRepo repository.SnapshotRepository, txManager *transaction.Manager) ExportService {
+	return &ExportServiceImpl{
+		snapshotRepo: snapshotRepo,
+		txManager:    txManager,
+	}
+}
+
+// Export streams a single entity. Arguments are checked before anything is
+// written, so the caller can still report them as a normal error response.
+func (s *ExportServiceImpl) Export(ctx context.Context, entity, format string, w io.Writer) error {
+	sample, ok := exportSamples[entity]
+	if !ok {
+		return ErrUnknownExportEntity
+	}
+
+	enc, err := export.NewEncoder(format, w, sample)
+	if errors.Is(err, export.ErrUnknownFormat) {
+		return ErrInvalidExportFormat
+	}
+	if err != nil {
+		return err
+	}
+
+	var count int64
+	err = s.txManager.DoSnapshot(ctx, func(txCtx context.Context, tx *gorm.DB) error {
+		return streamEntity(txCtx, s.snapshotRepo.WithTx(tx), entity, func(row any) error {
+			count++
+			return enc.Encode(row)
+		})
+	})
+	if err == nil {
+		err = enc.Close()
+	}
+	if err != nil {
+		log.Printf("Export of %s failed after %d rows: %v", entity, count, err)
+		return err
+	}
+
+	log.Printf("Exported %d %s rows as %s", count, entity, format)
+	return nil
+}
+
+// Snapshot writes every entity into one gzipped tar. All entities are read in
+// a single REPEATABLE READ transaction and spooled to temporary files first,
+// so the transaction doesn't stay open while a slow client downloads.
+func (s *ExportServiceImpl) Snapshot(ctx context.Context, w io.Writer) (*dto.SnapshotManifest, error) {
+	dir, err := os.MkdirTemp("", "pr-snapshot-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	manifest := &dto.SnapshotManifest{Version: export.SnapshotVersion, Counts: map[string]int64{}}
+
+	err = s.txManager.DoSnapshot(ctx, func(txCtx context.Context, tx *gorm.DB) error {
+		repo := s.snapshotRepo.WithTx(tx)
+
+		version, err := repo.SchemaVersion(txCtx)
+		if err != nil {
+			return err
+		}
+		manifest.SchemaVersion = version
+		manifest.ExportedAt = time.Now().UTC()
+
+		for _, entity := range export.Entities {
+			count, err := spoolEntity(txCtx, repo, entity, filepath.Join(dir, export.FileName(entity)))
+			if err != nil {
+				return err
+			}
+			manifest.Counts[entity] = count
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Transaction failed for Snapshot: %v", err)
+		return nil, err
+	}
+
+	if err := writeSnapshotArchive(w, dir, manifest); err != nil {
+		log.Printf("Failed to write snapshot archive: %v", err)
+		return nil, err
+	}
+
+	log.Printf("Snapshot written at schema version %d: %v", manifest.SchemaVersion, manifest.Counts)
+	return manifest, nil
+}
+
+func spoolEntity(ctx context.Context, repo repository.SnapshotRepository, entity, path string) (int64, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	buf := bufio.NewWriter(f)
+	enc := json.NewEncoder(buf)
+
+	var count int64
+	err = streamEntity(ctx, repo, entity, func(row any) error {
+		count++
+		return enc.Encode(row)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, buf.Flush()
+}
+
+func writeSnapshotArchive(w io.Writer, dir string, manifest *dto.SnapshotManifest) error {
+	archive := export.NewArchiveWriter(w)
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = archive.Add(export.ManifestName, int64(len(manifestJSON)), manifest.ExportedAt, bytes.NewReader(manifestJSON))
+	if err != nil {
+		return err
+	}
+
+	for _, entity := range export.Entities {
+		if err := addSpooledFile(archive, filepath.Join(dir, export.FileName(entity)), manifest.ExportedAt); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+func addSpooledFile(archive *export.ArchiveWriter, path string, modTime time.Time) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	return archive.Add(filepath.Base(path), info.Size(), modTime, f)
+}
+
+// Restore loads a snapshot into an empty database in one transaction. The
+// snapshot must come from the same schema version, since rows are inserted
+// as they were exported.
+func (s *ExportServiceImpl) Restore(ctx context.Context, r io.Reader) (*dto.RestoreReport, error) {
+	archive, err := export.NewArchiveReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+
+	manifest, err := readManifest(archive)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.RestoreReport{
+		SchemaVersion: manifest.SchemaVersion,
+		ExportedAt:    manifest.ExportedAt,
+		Restored:      map[string]int64{},
+	}
+
+	err = s.txManager.Do(ctx, func(txCtx context.Context, tx *gorm.DB) error {
+		repo := s.snapshotRepo.WithTx(tx)
+
+		version, err := repo.SchemaVersion(txCtx)
+		if err != nil {
+			return err
+		}
+		if version != manifest.SchemaVersion {
+			return ErrSchemaMismatch.WithDetails("snapshot_version", manifest.SchemaVersion).WithDetails("database_version", version)
+		}
+
+		empty, err := repo.IsEmpty(txCtx)
+		if err != nil {
+			return err
+		}
+		if !empty {
+			return ErrDatabaseNotEmpty
+		}
+
+		for _, entity := range export.Entities {
+			name, body, err := archive.Next()
+			if errors.Is(err, io.EOF) {
+				return fmt.Errorf("%w: missing %s", ErrInvalidSnapshot, export.FileName(entity))
+			}
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+			}
+			if name != export.FileName(entity) {
+				return fmt.Errorf("%w: expected %s, found %s", ErrInvalidSnapshot, export.FileName(entity), name)
+			}
+
+			count, err := restoreEntity(txCtx, repo, entity, body)
+			if err != nil {
+				return err
+			}
+			if count != manifest.Counts[entity] {
+				return fmt.Errorf("%w: %s has %d rows, manifest says %d", ErrInvalidSnapshot, entity, count, manifest.Counts[entity])
+			}
+			report.Restored[entity] = count
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Transaction failed for Restore: %v", err)
+		return nil, err
+	}
+
+	log.Printf("Restored snapshot from %s: %v", manifest.ExportedAt.Format(time.RFC3339), report.Restored)
+	return report, nil
+}
+
+func readManifest(archive *export.ArchiveReader) (*dto.SnapshotManifest, error) {
+	name, body, err := archive.Next()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+	if name != export.ManifestName {
+		return nil, fmt.Errorf("%w: %s must be the first entry, found %s", ErrInvalidSnapshot, export.ManifestName, name)
+	}
+
+	var manifest dto.SnapshotManifest
+	if err := json.NewDecoder(body).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("%w: manifest: %v", ErrInvalidSnapshot, err)
+	}
+	if manifest.Version != export.SnapshotVersion {
+		return nil, fmt.Errorf("%w: unsupported snapshot version %d", ErrInvalidSnapshot, manifest.Version)
+	}
+
+	return &manifest, nil
+}
+
+var exportSamples = map[string]any{
+	export.EntityTeams:                dto.ExportTeam{},
+	export.EntityUsers:                dto.ExportUser{},
+	export.EntityMemberships:          dto.ExportMembership{},
+	export.EntityTeamSettings:         dto.ExportTeamSettings{},
+	export.EntityOwnershipRules:       dto.ExportOwnershipRule{},
+	export.EntityUserSkills:           dto.ExportUserSkill{},
+	export.EntityNotificationSettings: dto.ExportNotificationSettings{},
+	export.EntityPullRequests:         dto.ExportPullRequest{},
+	export.EntityReviewers:            dto.ExportReviewer{},
+	export.EntityLabels:               dto.ExportLabel{},
+	export.EntityHistory:              dto.ExportHistoryEvent{},
+	export.EntityAuditEvents:          dto.ExportAuditEvent{},
+	export.EntityAPITokens:            dto.ExportAPIToken{},
+}
+
+func streamEntity(ctx context.Context, repo repository.SnapshotRepository, entity string, emit func(any) error) error {
+	switch entity {
+	case export.EntityTeams:
+		return repo.StreamTeams(ctx, func(t models.Team) error {
+			var parentID *string
+			if t.ParentTeamID != nil {
+				id := t.ParentTeamID.String()
+				parentID = &id
+			}
+			return emit(dto.ExportTeam{
+				TeamID:       t.TeamID.String(),
+				TeamName:     t.TeamName,
+				WebhookURL:   t.WebhookURL,
+				ParentTeamID: parentID,
+				DeletedAt:    deletedAtToPtr(t.DeletedAt),
+			})
+		})
+	case export.EntityUsers:
+		return repo.StreamUsers(ctx, func(u models.User) error {
+			return emit(dto.ExportUser{
+				UserID:         u.UserID,
+				Username:       u.Username,
+				TeamID:         u.TeamID.String(),
+				IsActive:       u.IsActive,
+				MaxOpenReviews: u.MaxOpenReviews,
+				DeletedAt:      deletedAtToPtr(u.DeletedAt),
+			})
+		})
+	case export.EntityMemberships:
+		return repo.StreamMemberships(ctx, func(m models.TeamMembership) error {
+			return emit(dto.ExportMembership{
+				UserID:    m.UserID,
+				TeamID:    m.TeamID.String(),
+				IsPrimary: m.IsPrimary,
+				IsActive:  m.IsActive,
+				CreatedAt: m.CreatedAt,
+			})
+		})
+	case export.EntityPullRequests:
+		return repo.StreamPullRequests(ctx, func(pr models.PullRequest) error {
+			return emit(dto.ExportPullRequest{
+				PullRequestID:   pr.PullRequestID,
+				PullRequestName: pr.PullRequestName,
+				AuthorID:        pr.AuthorID,
+				Status:          dto.PRStatus(pr.Status),
+				CreatedAt:       pr.CreatedAt,
+				MergedAt:        pr.MergedAt,
+				SelectionSeed:   pr.SelectionSeed,
+				DeletedAt:       deletedAtToPtr(pr.DeletedAt),
+			})
+		})
+	case export.EntityReviewers:
+		return repo.StreamReviewers(ctx, func(r models.PRReviewer) error {
+			return emit(dto.ExportReviewer{
+				PullRequestID: r.PullRequestID,
+				ReviewerID:    r.ReviewerID,
+				AssignedAt:    r.AssignedAt,
+				RemindedAt:    r.RemindedAt,
+				Pinned:        r.Pinned,
+			})
+		})
+	case export.EntityHistory:
+		return repo.StreamHistory(ctx, func(e models.ReviewerAssignmentHistory) error {
+			return emit(dto.ExportHistoryEvent{
+				EventID:       e.AssigmentHistoryID.String(),
+				PullRequestID: e.PrID,
+				UserID:        e.UserID,
+				EventType:     string(e.EventType),
+				SelectionSeed: e.SelectionSeed,
+				CreatedAt:     e.CreatedAt,
+			})
+		})
+	case export.EntityTeamSettings:
+		return repo.StreamTeamSettings(ctx, func(ts models.TeamSettings) error {
+			return emit(dto.ExportTeamSettings{
+				TeamID:                ts.TeamID.String(),
+				SLARemindAfterHours:   ts.SLARemindAfterHours,
+				SLAReassignAfterHours: ts.SLAReassignAfterHours,
+				RotationWindow:        ts.RotationWindow,
+			})
+		})
+	case export.EntityOwnershipRules:
+		return repo.StreamOwnershipRules(ctx, func(r models.OwnershipRule) error {
+			return emit(dto.ExportOwnershipRule{
+				RuleID:    r.RuleID.String(),
+				TeamID:    r.TeamID.String(),
+				Position:  r.Position,
+				Pattern:   r.Pattern,
+				Owners:    r.Owners,
+				CreatedAt: r.CreatedAt,
+			})
+		})
+	case export.EntityUserSkills:
+		return repo.StreamUserSkills(ctx, func(sk models.UserSkill) error {
+			return emit(dto.ExportUserSkill{UserID: sk.UserID, Tag: sk.Tag})
+		})
+	case export.EntityNotificationSettings:
+		return repo.StreamNotificationSettings(ctx, func(ns models.UserNotificationSettings) error {
+			return emit(dto.ExportNotificationSettings{
+				UserID:           ns.UserID,
+				Email:            ns.Email,
+				EmailOptOut:      ns.EmailOptOut,
+				DigestFrequency:  string(ns.DigestFrequency),
+				LastDigestSentAt: ns.LastDigestSentAt,
+			})
+		})
+	case export.EntityLabels:
+		return repo.StreamLabels(ctx, func(l models.PRLabel) error {
+			return emit(dto.ExportLabel{PullRequestID: l.PullRequestID, Label: l.Label})
+		})
+	case export.EntityAuditEvents:
+		return repo.StreamAuditEvents(ctx, func(e models.TeamAuditEvent) error {
+			return emit(dto.ExportAuditEvent{
+				EventID:   e.EventID.String(),
+				TeamID:    e.TeamID.String(),
+				TeamName:  e.TeamName,
+				Action:    string(e.Action),
+				UserID:    e.UserID,
+				OldValue:  e.OldValue,
+				NewValue:  e.NewValue,
+				CreatedAt: e.CreatedAt,
+			})
+		})
+	case export.EntityAPITokens:
+		return repo.StreamAPITokens(ctx, func(t models.APIToken) error {
+			return emit(dto.ExportAPIToken{
+				TokenID:    t.TokenID.String(),
+				Name:       t.Name,
+				TokenHash:  t.TokenHash,
+				CreatedAt:  t.CreatedAt,
+				ExpiresAt:  t.ExpiresAt,
+				LastUsedAt: t.LastUsedAt,
+			})
+		})
+	default:
+		return ErrUnknownExportEntity
+	}
+}
+
+func deletedAtToPtr(d gorm.DeletedAt) *time.Time {
+	if !d.Valid {
+		return nil
+	}
+	return &d.Time
+}
+
+func ptrToDeletedAt(t *time.Time) gorm.DeletedAt {
+	if t == nil {
+		return gorm.DeletedAt{}
+	}
+	return gorm.DeletedAt{Time: *t, Valid: true}
+}
+
+func restoreEntity(ctx context.Context, repo repository.SnapshotRepository, entity string, body io.Reader) (int64, error) {
+	var (
+		count int64
+		err   error
+	)
+
+	switch entity {
+	case export.EntityTeams:
+		count, err = restoreRows(ctx, repo.Insert, body, func(t dto.ExportTeam) (models.Team, error) {
+			teamID, err := uuid.Parse(t.TeamID)
+			if err != nil {
+				return models.Team{}, err
+			}
+			team := models.Team{TeamID: teamID, TeamName: t.TeamName, WebhookURL: t.WebhookURL, DeletedAt: ptrToDeletedAt(t.DeletedAt)}
+			if t.ParentTeamID != nil {
+				parentID, err := uuid.Parse(*t.ParentTeamID)
+				if err != nil {
+					return models.Team{}, err
+				}
+				team.ParentTeamID = &parentID
+			}
+			return team, nil
+		})
+	case export.EntityUsers:
+		count, err = restoreRows(ctx, repo.Insert, body, func(u dto.ExportUser) (models.User, error) {
+			teamID, err := uuid.Parse(u.TeamID)
+			return models.User{
+				UserID:         u.UserID,
+				Username:       u.Username,
+				TeamID:         teamID,
+				IsActive:       u.IsActive,
+				MaxOpenReviews: u.MaxOpenReviews,
+				DeletedAt:      ptrToDeletedAt(u.DeletedAt),
+			}, err
+		})
+	case export.EntityMemberships:
+		count, err = restoreRows(ctx, repo.Insert, body, func(m dto.ExportMembership) (models.TeamMembership, error) {
+			teamID, err := uuid.Parse(m.TeamID)
+			return models.TeamMembership{
+				UserID:    m.UserID,
+				TeamID:    teamID,
+				IsPrimary: m.IsPrimary,
+				IsActive:  m.IsActive,
+				CreatedAt: m.CreatedAt,
+			}, err
+		})
+	case export.EntityPullRequests:
+		count, err = restoreRows(ctx, repo.Insert, body, func(pr dto.ExportPullRequest) (models.PullRequest, error) {
+			return models.PullRequest{
+				PullRequestID:   pr.PullRequestID,
+				PullRequestName: pr.PullRequestName,
+				AuthorID:        pr.AuthorID,
+				Status:          models.PRStatus(pr.Status),
+				CreatedAt:       pr.CreatedAt,
+				MergedAt:        pr.MergedAt,
+				SelectionSeed:   pr.SelectionSeed,
+				DeletedAt:       ptrToDeletedAt(pr.DeletedAt),
+			}, nil
+		})
+	case export.EntityReviewers:
+		count, err = restoreRows(ctx, repo.Insert, body, func(r dto.ExportReviewer) (models.PRReviewer, error) {
+			return models.PRReviewer{
+				PullRequestID: r.PullRequestID,
+				ReviewerID:    r.ReviewerID,
+				AssignedAt:    r.AssignedAt,
+				RemindedAt:    r.RemindedAt,
+				Pinned:        r.Pinned,
+			}, nil
+		})
+	case export.EntityHistory:
+		count, err = restoreRows(ctx, repo.Insert, body, func(e dto.ExportHistoryEvent) (models.ReviewerAssignmentHistory, error) {
+			eventID, err := uuid.Parse(e.EventID)
+			return models.ReviewerAssignmentHistory{
+				AssigmentHistoryID: eventID,
+				PrID:               e.PullRequestID,
+				UserID:             e.UserID,
+				EventType:          models.HistoryEventType(e.EventType),
+				SelectionSeed:      e.SelectionSeed,
+				CreatedAt:          e.CreatedAt,
+			}, err
+		})
+	case export.EntityTeamSettings:
+		count, err = restoreRows(ctx, repo.Insert, body, func(ts dto.ExportTeamSettings) (models.TeamSettings, error) {
+			teamID, err := uuid.Parse(ts.TeamID)
+			return models.TeamSettings{
+				TeamID:                teamID,
+				SLARemindAfterHours:   ts.SLARemindAfterHours,
+				SLAReassignAfterHours: ts.SLAReassignAfterHours,
+				RotationWindow:        ts.RotationWindow,
+			}, err
+		})
+	case export.EntityOwnershipRules:
+		count, err = restoreRows(ctx, repo.Insert, body, func(r dto.ExportOwnershipRule) (models.OwnershipRule, error) {
+			ruleID, err := uuid.Parse(r.RuleID)
+			if err != nil {
+				return models.OwnershipRule{}, err
+			}
+			teamID, err := uuid.Parse(r.TeamID)
+			return models.OwnershipRule{
+				RuleID:    ruleID,
+				TeamID:    teamID,
+				Position:  r.Position,
+				Pattern:   r.Pattern,
+				Owners:    r.Owners,
+				CreatedAt: r.CreatedAt,
+			}, err
+		})
+	case export.EntityUserSkills:
+		count, err = restoreRows(ctx, repo.Insert, body, func(sk dto.ExportUserSkill) (models.UserSkill, error) {
+			return models.UserSkill{UserID: sk.UserID, Tag: sk.Tag}, nil
+		})
+	case export.EntityNotificationSettings:
+		count, err = restoreRows(ctx, repo.Insert, body, func(ns dto.ExportNotificationSettings) (models.UserNotificationSettings, error) {
+			return models.UserNotificationSettings{
+				UserID:           ns.UserID,
+				Email:            ns.Email,
+				EmailOptOut:      ns.EmailOptOut,
+				DigestFrequency:  models.DigestFrequency(ns.DigestFrequency),
+				LastDigestSentAt: ns.LastDigestSentAt,
+			}, nil
+		})
+	case export.EntityLabels:
+		count, err = restoreRows(ctx, repo.Insert, body, func(l dto.ExportLabel) (models.PRLabel, error) {
+			return models.PRLabel{PullRequestID: l.PullRequestID, Label: l.Label}, nil
+		})
+	case export.EntityAuditEvents:
+		count, err = restoreRows(ctx, repo.Insert, body, func(e dto.ExportAuditEvent) (models.TeamAuditEvent, error) {
+			eventID, err := uuid.Parse(e.EventID)
+			if err != nil {
+				return models.TeamAuditEvent{}, err
+			}
+			teamID, err := uuid.Parse(e.TeamID)
+			return models.TeamAuditEvent{
+				EventID:   eventID,
+				TeamID:    teamID,
+				TeamName:  e.TeamName,
+				Action:    models.TeamAuditAction(e.Action),
+				UserID:    e.UserID,
+				OldValue:  e.OldValue,
+				NewValue:  e.NewValue,
+				CreatedAt: e.CreatedAt,
+			}, err
+		})
+	case export.EntityAPITokens:
+		// The database may already hold the token the restore was made with.
+		count, err = restoreRows(ctx, repo.InsertNew, body, func(t dto.ExportAPIToken) (models.APIToken, error) {
+			tokenID, err := uuid.Parse(t.TokenID)
+			return models.APIToken{
+				TokenID:    tokenID,
+				Name:       t.Name,
+				TokenHash:  t.TokenHash,
+				CreatedAt:  t.CreatedAt,
+				ExpiresAt:  t.ExpiresAt,
+				LastUsedAt: t.LastUsedAt,
+			}, err
+		})
+	default:
+		return 0, ErrUnknownExportEntity
+	}
+
+	if errors.Is(err, export.ErrMalformedRow) {
+		return count, fmt.Errorf("%w: %s: %v", ErrInvalidSnapshot, entity, err)
+	}
+	return count, err
+}
+
+// restoreRows decodes NDJSON rows, converts them to models and inserts them
+// in batches with insert. Conversion errors mean the archive is broken, not
+// the database.
+func restoreRows[R, M any](ctx context.Context, insert func(ctx context.Context, rows any, batchSize int) error, body io.Reader, convert func(R) (M, error)) (int64, error) {
+	var count int64
+	batch := make([]M, 0, restoreBatchSize)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := insert(ctx, &batch, restoreBatchSize)
+		batch = batch[:0]
+		return err
+	}
+
+	err := export.DecodeNDJSON(body, func(row R) error {
+		m, err := convert(row)
+		if err != nil {
+			return fmt.Errorf("%w: row %d: %v", export.ErrMalformedRow, count+1, err)
+		}
+		batch = append(batch, m)
+		count++
+		if len(batch) == restoreBatchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+
+	return count, flush()
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/models"
+	"github.com/mink0ff/pr_service/internal/primport"
+	"github.com/mink0ff/pr_service/internal/repository"
+	"github.com/mink0ff/pr_service/internal/repository/transaction"
+	"gorm.io/gorm"
+)
+
+const importBatchSize = 500
+
+// importTimeLayouts are tried in order; spreadsheet exports often carry plain
+// dates, which are read as midnight UTC.
+var importTimeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"}
+
+type ImportServiceImpl struct {
+	prRepo      repository.PullRequestRepository
+	userRepo    repository.UserRepository
+	historyRepo repository.ReviewerHistoryRepository
+	txManager   *transaction.Manager
+}
+
+func NewImportService(
+	prRepo repository.PullRequestRepository,
+	userRepo repository.UserRepository,
+	historyRepo repository.ReviewerHistoryRepository,
+	txManager *transaction.Manager,
+) ImportService {
+	return &ImportServiceImpl{
+		prRepo:      prRepo,
+		userRepo:    userRepo,
+		historyRepo: historyRepo,
+		txManager:   txManager,
+	}
+}
+
+// ImportPullRequests loads historical pull requests with the reviewers given
+// in the input instead of selecting them. Every row is validated, with the
+// same ID and name rules as /pullRequest/create, before anything is written
+// and a single invalid row rejects the whole import, so a corrected file can
+// simply be sent again.
+func (s *ImportServiceImpl) ImportPullRequests(ctx context.Context, format string, data io.Reader, dryRun bool) (*dto.ImportReport, error) {
+	rows, rowErrs, err := primport.Parse(format, data)
+	if errors.Is(err, primport.ErrUnknownFormat) {
+		return nil, ErrInvalidImportFormat
+	}
+	if err != nil {
+		log.Printf("Failed to read import data: %v", err)
+		return nil, err
+	}
+
+	report := &dto.ImportReport{Total: len(rows) + len(rowErrs), DryRun: dryRun, Errors: rowErrs}
+
+	prs, reviewers, events, validationErrs, err := s.validateImport(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+	report.Errors = append(report.Errors, validationErrs...)
+	slices.SortStableFunc(report.Errors, func(a, b dto.ImportRowError) int { return a.Row - b.Row })
+
+	if len(report.Errors) > 0 {
+		log.Printf("Import rejected: %d of %d rows are invalid", len(report.Errors), report.Total)
+		return report, nil
+	}
+	report.Errors = []dto.ImportRowError{}
+	if dryRun {
+		log.Printf("Import dry run: %d rows are valid", len(prs))
+		return report, nil
+	}
+
+	err = s.txManager.Do(ctx, func(txCtx context.Context, tx *gorm.DB) error {
+		if err := s.prRepo.WithTx(tx).CreateBatch(txCtx, prs, reviewers, importBatchSize); err != nil {
+			return err
+		}
+		return s.historyRepo.WithTx(tx).AddEvents(txCtx, events, importBatchSize)
+	})
+	if err != nil {
+		log.Printf("Transaction failed for ImportPullRequests: %v", err)
+		return nil, err
+	}
+
+	report.Imported = len(prs)
+	log.Printf("Imported %d pull requests with %d reviewers", len(prs), len(reviewers))
+	return report, nil
+}
+
+func (s *ImportServiceImpl) validateImport(ctx context.Context, rows []dto.ImportPullRequest) (
+	[]models.PullRequest, []models.PRReviewer, []models.ReviewerAssignmentHistory, []dto.ImportRowError, error,
+) {
+	var prIDs, userIDs []string
+	for _, row := range rows {
+		prIDs = append(prIDs, row.PullRequestID)
+		userIDs = append(userIDs, row.AuthorID)
+		userIDs = append(userIDs, row.Reviewers...)
+	}
+
+	existing, err := s.existingPRs(ctx, prIDs)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	users, err := s.knownUsers(ctx, userIDs)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	var (
+		prs       []models.PullRequest
+		reviewers []models.PRReviewer
+		events    []models.ReviewerAssignmentHistory
+		rowErrs   []dto.ImportRowError
+	)
+	seen := map[string]int{}
+
+	for i, row := range rows {
+		if row.Row == 0 {
+			row.Row = i + 1
+		}
+
+		var problems []string
+		fail := func(format string, args ...any) {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+
+		var verr *dto.ValidationError
+		if err := row.Validate(); errors.As(err, &verr) {
+			for _, d := range verr.Details {
+				fail("%s %s", d.Field, d.Message)
+			}
+		}
+
+		if row.PullRequestID != "" {
+			if first, dup := seen[row.PullRequestID]; dup {
+				fail("duplicate of row %d", first)
+			} else {
+				seen[row.PullRequestID] = row.Row
+				if _, ok := existing[row.PullRequestID]; ok {
+					fail("pull request already exists")
+				}
+			}
+		}
+
+		if row.AuthorID != "" {
+			if _, ok := users[row.AuthorID]; !ok {
+				fail("unknown author %q", row.AuthorID)
+			}
+		}
+
+		if len(row.Reviewers) > maxReviewers {
+			fail("at most %d reviewers allowed", maxReviewers)
+		}
+		for j, reviewerID := range row.Reviewers {
+			switch {
+			case reviewerID == row.AuthorID:
+				fail("author %q cannot be a reviewer", reviewerID)
+			case slices.Contains(row.Reviewers[:j], reviewerID):
+				fail("reviewer %q listed twice", reviewerID)
+			default:
+				if _, ok := users[reviewerID]; !ok {
+					fail("unknown reviewer %q", reviewerID)
+				}
+			}
+		}
+
+		createdAt, err := parseImportTime(row.CreatedAt)
+		if err != nil {
+			fail("created_at: %v", err)
+		}
+
+		var mergedAt *time.Time
+		switch models.PRStatus(row.Status) {
+		case models.PROpen:
+			if row.MergedAt != "" {
+				fail("merged_at is only allowed for MERGED pull requests")
+			}
+		case models.PRMerged:
+			t, err := parseImportTime(row.MergedAt)
+			if err != nil {
+				fail("merged_at: %v", err)
+			} else if !createdAt.IsZero() && t.Before(createdAt) {
+				fail("merged_at is before created_at")
+			} else {
+				mergedAt = &t
+			}
+		default:
+			fail("status must be OPEN or MERGED")
+		}
+
+		if len(problems) > 0 {
+			rowErrs = append(rowErrs, dto.ImportRowError{
+				Row:           row.Row,
+				PullRequestID: row.PullRequestID,
+				Error:         strings.Join(problems, "; "),
+			})
+			continue
+		}
+
+		prs = append(prs, models.PullRequest{
+			PullRequestID:   row.PullRequestID,
+			PullRequestName: row.PullRequestName,
+			AuthorID:        row.AuthorID,
+			Status:          models.PRStatus(row.Status),
+			CreatedAt:       createdAt,
+			MergedAt:        mergedAt,
+		})
+		for _, reviewerID := range row.Reviewers {
+			reviewers = append(reviewers, models.PRReviewer{
+				PullRequestID: row.PullRequestID,
+				ReviewerID:    reviewerID,
+				AssignedAt:    createdAt,
+			})
+			events = append(events, models.ReviewerAssignmentHistory{
+				AssigmentHistoryID: uuid.New(),
+				PrID:               row.PullRequestID,
+				UserID:             reviewerID,
+				EventType:          models.EventAssigned,
+				CreatedAt:          createdAt,
+			})
+		}
+	}
+
+	return prs, reviewers, events, rowErrs, nil
+}
+
+func (s *ImportServiceImpl) existingPRs(ctx context.Context, ids []string) (map[string]struct{}, error) {
+	result := map[string]struct{}{}
+	for chunk := range slices.Chunk(ids, importBatchSize) {
+		existing, err := s.prRepo.ListExistingIDs(ctx, chunk)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range existing {
+			result[id] = struct{}{}
+		}
+	}
+	return result, nil
+}
+
+func (s *ImportServiceImpl) knownUsers(ctx context.Context, ids []string) (map[string]struct{}, error) {
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	result := map[string]struct{}{}
+	for chunk := range slices.Chunk(ids, importBatchSize) {
+		users, err := s.userRepo.ListByIDs(ctx, chunk)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			result[u.UserID] = struct{}{}
+		}
+	}
+	return result, nil
+}
+
+func parseImportTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.New("is required")
+	}
+	for _, layout := range importTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q, use RFC 3339 or YYYY-MM-DD", value)
+}
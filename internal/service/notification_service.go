@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/mink0ff/pr_service/internal/mailer"
+	"github.com/mink0ff/pr_service/internal/models"
+	"github.com/mink0ff/pr_service/internal/notifier"
+	"github.com/mink0ff/pr_service/internal/repository"
+)
+
+type NotificationServiceImpl struct {
+	teamRepo     repository.TeamRepository
+	userRepo     repository.UserRepository
+	settingsRepo repository.NotificationSettingsRepository
+	notifier     notifier.Notifier
+	mailer       mailer.Mailer
+	now          func() time.Time
+}
+
+func NewNotificationService(
+	teamRepo repository.TeamRepository,
+	userRepo repository.UserRepository,
+	settingsRepo repository.NotificationSettingsRepository,
+	n notifier.Notifier,
+	m mailer.Mailer,
+) NotificationService {
+	return &NotificationServiceImpl{
+		teamRepo:     teamRepo,
+		userRepo:     userRepo,
+		settingsRepo: settingsRepo,
+		notifier:     n,
+		mailer:       m,
+		now:          time.Now,
+	}
+}
+
+func (s *NotificationServiceImpl) SendReviewDigests(ctx context.Context) error {
+	teams, err := s.teamRepo.List(ctx)
+	if err != nil {
+		log.Printf("Failed to list teams for review digest: %v", err)
+		return err
+	}
+
+	// A team whose digest cannot be built does not hold back the others;
+	// the failures are reported together once every team was tried.
+	var errs []error
+	for _, team := range teams {
+		if team.WebhookURL == nil {
+			continue
+		}
+
+		digest, err := s.buildDigest(ctx, team)
+		if err != nil {
+			log.Printf("Failed to build review digest for team %s: %v", team.TeamName, err)
+			errs = append(errs, fmt.Errorf("team %s: %w", team.TeamName, err))
+			continue
+		}
+
+		if err := s.notifier.NotifyDigest(ctx, *digest); err != nil {
+			log.Printf("Failed to send review digest for team %s: %v", team.TeamName, err)
+			continue
+		}
+
+		log.Printf("Review digest sent: teamName=%s, reviewers=%d", team.TeamName, len(digest.Queues))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *NotificationServiceImpl) buildDigest(ctx context.Context, team models.Team) (*notifier.Digest, error) {
+	users, err := s.teamRepo.ListUsersByTeam(ctx, team.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := &notifier.Digest{
+		TeamName:   team.TeamName,
+		WebhookURL: *team.WebhookURL,
+	}
+
+	for _, u := range users {
+		if !u.IsActive {
+			continue
+		}
+
+		prs, err := s.userRepo.ListReviewPRs(ctx, u.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		queue := notifier.ReviewerQueue{
+			Reviewer: notifier.Person{ID: u.UserID, Name: u.Username},
+		}
+		for _, pr := range prs {
+			if pr.Status != models.PROpen {
+				continue
+			}
+			queue.PullRequests = append(queue.PullRequests, notifier.DigestPR{
+				PullRequestID:   pr.PullRequestID,
+				PullRequestName: pr.PullRequestName,
+				AuthorID:        pr.AuthorID,
+				CreatedAt:       pr.CreatedAt,
+			})
+		}
+
+		if len(queue.PullRequests) == 0 {
+			continue
+		}
+
+		sort.Slice(queue.PullRequests, func(i, j int) bool {
+			return queue.PullRequests[i].CreatedAt.Before(queue.PullRequests[j].CreatedAt)
+		})
+		digest.Queues = append(digest.Queues, queue)
+	}
+
+	return digest, nil
+}
+
+func (s *NotificationServiceImpl) SendEmailDigests(ctx context.Context) error {
+	recipients, err := s.settingsRepo.ListEmailRecipients(ctx)
+	if err != nil {
+		log.Printf("Failed to list email digest recipients: %v", err)
+		return err
+	}
+
+	now := s.now()
+	sent := 0
+	// As with the chat digests, one recipient's failure does not hold back
+	// the others.
+	var errs []error
+	for _, r := range recipients {
+		if !digestDue(r, now) {
+			continue
+		}
+
+		msg, ok, err := s.buildEmailDigest(ctx, r, now)
+		if err != nil {
+			log.Printf("Failed to build email digest for user %s: %v", r.UserID, err)
+			errs = append(errs, fmt.Errorf("user %s: %w", r.UserID, err))
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		if err := s.mailer.Send(ctx, msg); err != nil {
+			log.Printf("Failed to send email digest to user %s: %v", r.UserID, err)
+			continue
+		}
+
+		if err := s.settingsRepo.MarkDigestSent(ctx, r.UserID, now); err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", r.UserID, err))
+			continue
+		}
+		sent++
+	}
+
+	log.Printf("Email digests sent: %d", sent)
+	return errors.Join(errs...)
+}
+
+func (s *NotificationServiceImpl) buildEmailDigest(
+	ctx context.Context,
+	settings models.UserNotificationSettings,
+	now time.Time,
+) (mailer.Message, bool, error) {
+
+	user, err := s.userRepo.GetByID(ctx, settings.UserID)
+	if err != nil || user == nil {
+		return mailer.Message{}, false, err
+	}
+
+	prs, err := s.userRepo.ListReviewPRs(ctx, settings.UserID)
+	if err != nil {
+		return mailer.Message{}, false, err
+	}
+
+	data := mailer.DigestData{Username: user.Username}
+	for _, pr := range prs {
+		if pr.Status != models.PROpen {
+			continue
+		}
+		data.PullRequests = append(data.PullRequests, mailer.DigestPR{
+			PullRequestID:   pr.PullRequestID,
+			PullRequestName: pr.PullRequestName,
+			AuthorID:        pr.AuthorID,
+			OpenFor:         now.Sub(pr.CreatedAt),
+		})
+	}
+
+	if len(data.PullRequests) == 0 {
+		return mailer.Message{}, false, nil
+	}
+
+	sort.Slice(data.PullRequests, func(i, j int) bool {
+		return data.PullRequests[i].OpenFor > data.PullRequests[j].OpenFor
+	})
+
+	msg, err := mailer.RenderDigest(*settings.Email, data)
+	if err != nil {
+		return mailer.Message{}, false, err
+	}
+
+	return msg, true, nil
+}
+
+func digestDue(settings models.UserNotificationSettings, now time.Time) bool {
+	if settings.LastDigestSentAt == nil {
+		return true
+	}
+
+	period := 24 * time.Hour
+	if settings.DigestFrequency == models.DigestWeekly {
+		period = 7 * 24 * time.Hour
+	}
+
+	// Allow an hour of slack so a daily schedule does not drift past its slot.
+	return now.Sub(*settings.LastDigestSentAt) >= period-time.Hour
+}
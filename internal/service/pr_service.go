@@ -2,50 +2,116 @@ package service
 
 import (
 	"context"
-	_ "errors"
+	"errors"
 	"log"
 	"math/rand"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/mink0ff/pr_service/internal/codeowners"
 	"github.com/mink0ff/pr_service/internal/dto"
 	"github.com/mink0ff/pr_service/internal/models"
+	"github.com/mink0ff/pr_service/internal/notifier"
 	"github.com/mink0ff/pr_service/internal/repository"
 	"github.com/mink0ff/pr_service/internal/repository/transaction"
 	"gorm.io/gorm"
 )
 
 type PRServiceImpl struct {
-	prRepo      repository.PullRequestRepository
-	userRepo    repository.UserRepository
-	teamRepo    repository.TeamRepository
-	historyRepo repository.ReviewerHistoryRepository
-	txManager   *transaction.Manager
+	prRepo           repository.PullRequestRepository
+	userRepo         repository.UserRepository
+	teamRepo         repository.TeamRepository
+	historyRepo      repository.ReviewerHistoryRepository
+	ownershipRepo    repository.OwnershipRuleRepository
+	tagRepo          repository.TagRepository
+	teamSettingsRepo repository.TeamSettingsRepository
+	txManager        *transaction.Manager
+	notifier         notifier.Notifier
+	seeds            SeedFunc
 }
 
 func NewPRService(prRepo repository.PullRequestRepository,
 	userRepo repository.UserRepository,
 	teamRepo repository.TeamRepository,
 	historyRepo repository.ReviewerHistoryRepository,
-	txManager *transaction.Manager) PRService {
+	ownershipRepo repository.OwnershipRuleRepository,
+	tagRepo repository.TagRepository,
+	teamSettingsRepo repository.TeamSettingsRepository,
+	txManager *transaction.Manager,
+	n notifier.Notifier,
+	seeds SeedFunc) PRService {
+	if seeds == nil {
+		seeds, _ = NewSeedFunc(SelectionModeRandom, 0)
+	}
 	return &PRServiceImpl{
-		prRepo:      prRepo,
-		userRepo:    userRepo,
-		teamRepo:    teamRepo,
-		historyRepo: historyRepo,
-		txManager:   txManager,
+		prRepo:           prRepo,
+		userRepo:         userRepo,
+		teamRepo:         teamRepo,
+		historyRepo:      historyRepo,
+		ownershipRepo:    ownershipRepo,
+		tagRepo:          tagRepo,
+		teamSettingsRepo: teamSettingsRepo,
+		txManager:        txManager,
+		notifier:         n,
+		seeds:            seeds,
 	}
 }
 
+// maxReviewers is how many reviewers a pull request can have at most.
+const maxReviewers = 2
+
+// codeOwner is an active user owning at least one of the changed files,
+// together with the first matching rule that made them an owner.
+type codeOwner struct {
+	user models.User
+	rule models.OwnershipRule
+}
+
+// selection holds everything that influenced the reviewer choice, so the
+// response can explain each pick.
+type selection struct {
+	reviewers     []string
+	owners        []codeOwner
+	matchedSkills map[string][]string
+	recentReviews map[string]int
+	atCapacity    map[string]struct{}
+	// fallback holds the reviewers taken from parent teams by user ID.
+	fallback map[string]fallbackPick
+}
+
+type fallbackPick struct {
+	user     models.User
+	teamName string
+}
+
 func (s *PRServiceImpl) CreatePR(ctx context.Context, req *dto.CreatePRRequest) (*dto.CreatePRResponse, error) {
-	var resp *dto.CreatePRResponse
+	labels, err := normalizeTags(req.Labels)
+	if err != nil {
+		return nil, err
+	}
 
-	err := s.txManager.Do(ctx, func(txCtx context.Context, tx *gorm.DB) error {
+	seed := s.seeds(req.PullRequestID)
+	if req.Seed != nil {
+		seed = *req.Seed
+	}
+
+	var (
+		resp      *dto.CreatePRResponse
+		createdPR *models.PullRequest
+		teamID    uuid.UUID
+	)
+
+	err = s.txManager.Do(ctx, func(txCtx context.Context, tx *gorm.DB) error {
 		txUserRepo := s.userRepo.WithTx(tx)
+		txTeamRepo := s.teamRepo.WithTx(tx)
 		txPrRepo := s.prRepo.WithTx(tx)
 		txHistoryRepo := s.historyRepo.WithTx(tx)
+		txOwnershipRepo := s.ownershipRepo.WithTx(tx)
+		txTagRepo := s.tagRepo.WithTx(tx)
 
-		pr, err := s.createPullRequest(txCtx, req, txPrRepo)
+		pr, err := s.createPullRequest(txCtx, req, seed, txPrRepo)
 		if err != nil {
 			log.Printf("Failed to create PR: %v", err)
 			return err
@@ -57,224 +123,1189 @@ func (s *PRServiceImpl) CreatePR(ctx context.Context, req *dto.CreatePRRequest)
 			return err
 		}
 
-		reviewers := s.selectReviewers(txCtx, author.UserID, author.TeamID, txUserRepo)
+		if err := txTagRepo.AddPRLabels(txCtx, pr.PullRequestID, labels); err != nil {
+			log.Printf("Failed to save PR labels: %v", err)
+			return err
+		}
+
+		owners, err := s.findCodeOwners(txCtx, author, req.ChangedFiles, txOwnershipRepo, txUserRepo, txTeamRepo)
+		if err != nil {
+			log.Printf("Failed to resolve code owners: %v", err)
+			return err
+		}
+
+		rng := rand.New(rand.NewSource(seed))
+		sel, err := s.selectReviewers(txCtx, rng, author, pr.PullRequestID, owners, labels, txUserRepo, txTeamRepo, txTagRepo, txHistoryRepo)
+		if err != nil {
+			log.Printf("Failed to select reviewers: %v", err)
+			return err
+		}
+		if len(sel.reviewers) == 0 && len(sel.atCapacity) > 0 {
+			log.Printf("All candidate reviewers for PR %s are at capacity", pr.PullRequestID)
+			return ErrReviewersAtCapacity
+		}
+		reviewers := sel.reviewers
 
 		if err := s.assignReviewers(txCtx, pr.PullRequestID, reviewers, txPrRepo); err != nil {
 			log.Printf("Failed to assign reviewers: %v", err)
 			return err
 		}
 
-		if err := s.logReviewerAssignments(txCtx, txHistoryRepo, pr.PullRequestID, reviewers); err != nil {
+		if err := s.logReviewerAssignments(txCtx, txHistoryRepo, pr.PullRequestID, reviewers, seed); err != nil {
 			log.Printf("Failed to log reviewer assignments: %v", err)
 			return err
 		}
 
-		resp = &dto.CreatePRResponse{
-			PR: dto.PullRequestDTO{
-				PullRequestID:     pr.PullRequestID,
-				PullRequestName:   pr.PullRequestName,
-				AuthorID:          pr.AuthorID,
-				Status:            dto.PRStatusOpen,
-				AssignedReviewers: reviewers,
-				CreatedAt:         &pr.CreatedAt,
-			},
+		resp = &dto.CreatePRResponse{
+			PR: dto.PullRequestDTO{
+				PullRequestID:     pr.PullRequestID,
+				PullRequestName:   pr.PullRequestName,
+				AuthorID:          pr.AuthorID,
+				Status:            dto.PRStatusOpen,
+				AssignedReviewers: reviewers,
+				Labels:            labels,
+				SelectionSeed:     pr.SelectionSeed,
+				CreatedAt:         &pr.CreatedAt,
+			},
+			ReviewerMatches: mapReviewerMatches(sel),
+		}
+		if len(reviewers) < maxReviewers {
+			resp.AtCapacity = shortfallAtCapacity(sel, author.UserID)
+		}
+		createdPR = pr
+		teamID = author.TeamID
+
+		return nil
+	})
+
+	if err != nil {
+		log.Printf("Transaction failed for CreatePR: %v", err)
+		return nil, err
+	}
+
+	s.notifyAssignment(ctx, teamID, createdPR, nil, resp.PR.AssignedReviewers)
+
+	log.Printf("CreatePR completed successfully for PRID=%s", req.PullRequestID)
+	return resp, nil
+}
+
+// PreviewReviewers runs the CreatePR selection against the current state
+// without writing anything. Passing the returned seed to the admin
+// /admin/pullRequest/create assigns exactly the previewed reviewers as long
+// as the team does not change in between; the public /pullRequest/create
+// does not accept a seed.
+func (s *PRServiceImpl) PreviewReviewers(ctx context.Context, req *dto.PreviewReviewersRequest) (*dto.PreviewReviewersResponse, error) {
+	labels, err := normalizeTags(req.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	author, err := s.userRepo.GetByID(ctx, req.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	if author == nil {
+		log.Printf("Author not found: %v", req.AuthorID)
+		return nil, ErrUserNotFound
+	}
+
+	team, err := s.teamRepo.GetByID(ctx, author.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		log.Printf("Team not found for author: %v", req.AuthorID)
+		return nil, ErrTeamNotFound
+	}
+
+	seed := s.seeds(req.PullRequestID)
+	if req.Seed != nil {
+		seed = *req.Seed
+	}
+
+	owners, err := s.findCodeOwners(ctx, author, req.ChangedFiles, s.ownershipRepo, s.userRepo, s.teamRepo)
+	if err != nil {
+		log.Printf("Failed to resolve code owners: %v", err)
+		return nil, err
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	sel, err := s.selectReviewers(ctx, rng, author, req.PullRequestID, owners, labels, s.userRepo, s.teamRepo, s.tagRepo, s.historyRepo)
+	if err != nil {
+		log.Printf("Failed to select reviewers: %v", err)
+		return nil, err
+	}
+
+	members, err := s.teamRepo.ListUsersByTeam(ctx, author.TeamID)
+	if err != nil {
+		log.Printf("Failed to list team members: %v", err)
+		return nil, err
+	}
+
+	log.Printf("PreviewReviewers for author=%s picked %v with seed %d", author.UserID, sel.reviewers, seed)
+	return &dto.PreviewReviewersResponse{
+		AuthorID:   author.UserID,
+		TeamName:   team.TeamName,
+		Seed:       seed,
+		Reviewers:  sel.reviewers,
+		Candidates: explainSelection(author, members, sel),
+	}, nil
+}
+
+func (s *PRServiceImpl) MergePR(ctx context.Context, req *dto.MergePRRequest) (*dto.MergePRResponse, error) {
+	pr, err := s.prRepo.GetByID(ctx, req.PullRequestID)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		log.Printf("PR not found: %v", req.PullRequestID)
+		return nil, ErrPRNotFound
+	}
+
+	if pr.Status == models.PRMerged {
+		reviewers, _ := s.prRepo.ListReviewers(ctx, req.PullRequestID)
+		log.Printf("PR already merged: %v", req.PullRequestID)
+		return &dto.MergePRResponse{
+			PR: mapPullRequestToDTO(pr, reviewers),
+		}, nil
+	}
+
+	mergeTime := time.Now()
+	newPr := models.PullRequest{
+		PullRequestID:   pr.PullRequestID,
+		PullRequestName: pr.PullRequestName,
+		AuthorID:        pr.AuthorID,
+		Status:          models.PRMerged,
+		CreatedAt:       pr.CreatedAt,
+		MergedAt:        &mergeTime,
+		SelectionSeed:   pr.SelectionSeed,
+	}
+
+	if err := s.prRepo.Update(ctx, newPr); err != nil {
+		log.Printf("Failed to merge PR: %v", err)
+		return nil, err
+	}
+
+	reviewers, _ := s.prRepo.ListReviewers(ctx, req.PullRequestID)
+	return &dto.MergePRResponse{
+		PR: mapPullRequestToDTO(&newPr, reviewers),
+	}, nil
+}
+
+// DeletePR soft-deletes the pull request. It drops out of review lists and
+// open review counts, while its reviewers and assignment history stay for
+// stats and a possible restore.
+func (s *PRServiceImpl) DeletePR(ctx context.Context, req *dto.DeletePRRequest) error {
+	err := s.txManager.Do(ctx, func(txCtx context.Context, tx *gorm.DB) error {
+		txPrRepo := s.prRepo.WithTx(tx)
+
+		pr, err := txPrRepo.GetByID(txCtx, req.PullRequestID)
+		if err != nil {
+			return err
+		}
+		if pr == nil {
+			return ErrPRNotFound
+		}
+
+		return txPrRepo.Delete(txCtx, pr.PullRequestID)
+	})
+	if err != nil {
+		log.Printf("Failed to delete PR %s: %v", req.PullRequestID, err)
+		return err
+	}
+
+	log.Printf("PR deleted: %s", req.PullRequestID)
+	return nil
+}
+
+func (s *PRServiceImpl) ReassignReviewer(ctx context.Context, req *dto.ReassignReviewerRequest) (*dto.ReassignReviewerResponse, error) {
+	var (
+		resp   *dto.ReassignReviewerResponse
+		notify func(context.Context)
+	)
+
+	err := s.txManager.Do(ctx, func(txCtx context.Context, tx *gorm.DB) error {
+		var err error
+		resp, notify, err = s.ReassignReviewerTx(txCtx, tx, req)
+		return err
+	})
+
+	if err != nil {
+		log.Printf("Transaction failed for ReassignReviewer: %v", err)
+		return nil, err
+	}
+
+	notify(ctx)
+
+	return resp, nil
+}
+
+// ReassignReviewerTx is ReassignReviewer inside the caller's transaction.
+// The returned notify sends the chat notification and is meant to be called
+// once tx has committed.
+func (s *PRServiceImpl) ReassignReviewerTx(ctx context.Context, tx *gorm.DB, req *dto.ReassignReviewerRequest) (*dto.ReassignReviewerResponse, func(context.Context), error) {
+	txPrRepo := s.prRepo.WithTx(tx)
+	txUserRepo := s.userRepo.WithTx(tx)
+	txTeamRepo := s.teamRepo.WithTx(tx)
+	txHistoryRepo := s.historyRepo.WithTx(tx)
+
+	pr, err := s.getPRForReassign(ctx, req.PullRequestID, txPrRepo)
+	if err != nil {
+		log.Printf("Failed to get PR for reassign: %v", err)
+		return nil, nil, err
+	}
+
+	reviewers, oldReviewer, err := s.getOldReviewer(ctx, pr.PullRequestID, req.OldUserID, txPrRepo)
+	if err != nil {
+		log.Printf("Failed to get old reviewer: %v", err)
+		return nil, nil, err
+	}
+
+	pinned, err := txPrRepo.ListPinnedReviewers(ctx, pr.PullRequestID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if slices.Contains(pinned, req.OldUserID) {
+		log.Printf("Reviewer %s is pinned to PR %s", req.OldUserID, pr.PullRequestID)
+		return nil, nil, ErrReviewerPinned
+	}
+
+	author, err := s.getAuthorWithTeamLock(ctx, pr.AuthorID, txUserRepo)
+	if err != nil {
+		log.Printf("Failed to get PR author: %v", err)
+		return nil, nil, err
+	}
+
+	seed := s.seeds(pr.PullRequestID + "/" + req.OldUserID)
+	rng := rand.New(rand.NewSource(seed))
+	newReviewerID, err := s.pickNewReviewer(ctx, reviewers, author, rng, txUserRepo, txTeamRepo)
+	if err != nil {
+		log.Printf("Failed to pick new reviewer: %v", err)
+		return nil, nil, err
+	}
+
+	if err := s.updateReviewers(ctx, pr.PullRequestID, req.OldUserID, newReviewerID, txPrRepo); err != nil {
+		log.Printf("Failed to update reviewers: %v", err)
+		return nil, nil, err
+	}
+
+	if err := s.logReviewerAssignments(ctx, txHistoryRepo, pr.PullRequestID, []string{newReviewerID}, seed); err != nil {
+		log.Printf("Failed to log reassignment: %v", err)
+		return nil, nil, err
+	}
+
+	if req.SLAEscalation {
+		if err := s.recordReviewerEvent(ctx, txHistoryRepo, pr.PullRequestID, req.OldUserID, models.EventSLAReassigned); err != nil {
+			log.Printf("Failed to record SLA reassignment: %v", err)
+			return nil, nil, err
+		}
+	}
+
+	updatedReviewers, _ := txPrRepo.ListReviewers(ctx, pr.PullRequestID)
+
+	prDTO := mapPullRequestToDTO(pr, updatedReviewers)
+	prDTO.PinnedReviewers = pinned
+	resp := &dto.ReassignReviewerResponse{
+		PR:         prDTO,
+		ReplacedBy: newReviewerID,
+	}
+
+	notify := func(ctx context.Context) {
+		s.notifyAssignment(ctx, oldReviewer.TeamID, pr, oldReviewer, []string{newReviewerID})
+	}
+	return resp, notify, nil
+}
+
+// AddReviewer assigns a reviewer chosen by hand. Any active user except the
+// author can be added, also from another team, as long as the PR is open and
+// has a free reviewer slot.
+func (s *PRServiceImpl) AddReviewer(ctx context.Context, req *dto.AddReviewerRequest) (*dto.ReviewerChangeResponse, error) {
+	var (
+		resp  *dto.ReviewerChangeResponse
+		pr    *models.PullRequest
+		added *models.User
+	)
+
+	err := s.txManager.Do(ctx, func(txCtx context.Context, tx *gorm.DB) error {
+		txPrRepo := s.prRepo.WithTx(tx)
+		txUserRepo := s.userRepo.WithTx(tx)
+		txHistoryRepo := s.historyRepo.WithTx(tx)
+
+		var err error
+		pr, err = s.getPRForReassign(txCtx, req.PullRequestID, txPrRepo)
+		if err != nil {
+			return err
+		}
+
+		added, err = txUserRepo.GetByID(txCtx, req.UserID)
+		if err != nil {
+			return err
+		}
+		if added == nil {
+			return ErrUserNotFound
+		}
+		if added.UserID == pr.AuthorID {
+			return ErrAuthorAsReviewer
+		}
+		if !added.IsActive {
+			return ErrReviewerInactive
+		}
+
+		reviewers, err := txPrRepo.ListReviewers(txCtx, pr.PullRequestID)
+		if err != nil {
+			return err
+		}
+		for _, r := range reviewers {
+			if r.UserID == added.UserID {
+				return ErrAlreadyAssigned
+			}
+		}
+		if len(reviewers) >= maxReviewers {
+			return ErrTooManyReviewers
+		}
+
+		if err := txPrRepo.AddReviewer(txCtx, pr.PullRequestID, added.UserID); err != nil {
+			return err
+		}
+		if err := s.recordReviewerEvent(txCtx, txHistoryRepo, pr.PullRequestID, added.UserID, models.EventManuallyAssigned); err != nil {
+			return err
+		}
+
+		if req.Pin {
+			if _, err := txPrRepo.SetReviewerPinned(txCtx, pr.PullRequestID, added.UserID, true); err != nil {
+				return err
+			}
+			if err := s.recordReviewerEvent(txCtx, txHistoryRepo, pr.PullRequestID, added.UserID, models.EventPinned); err != nil {
+				return err
+			}
+		}
+
+		prDTO, err := s.loadPullRequestDTO(txCtx, pr, txPrRepo)
+		if err != nil {
+			return err
+		}
+		resp = &dto.ReviewerChangeResponse{PR: prDTO}
+		return nil
+	})
+
+	if err != nil {
+		log.Printf("Transaction failed for AddReviewer: %v", err)
+		return nil, err
+	}
+
+	s.notifyAssignment(ctx, added.TeamID, pr, nil, []string{added.UserID})
+
+	log.Printf("Reviewer %s added to PR %s manually (pinned=%v)", added.UserID, pr.PullRequestID, req.Pin)
+	return resp, nil
+}
+
+// RemoveReviewer unassigns a reviewer, pinned ones included since the removal
+// is explicit. With Backfill a replacement is picked like on reassignment; no
+// available candidate leaves the slot empty.
+func (s *PRServiceImpl) RemoveReviewer(ctx context.Context, req *dto.RemoveReviewerRequest) (*dto.ReviewerChangeResponse, error) {
+	var (
+		resp    *dto.ReviewerChangeResponse
+		pr      *models.PullRequest
+		removed *models.User
+	)
+
+	err := s.txManager.Do(ctx, func(txCtx context.Context, tx *gorm.DB) error {
+		txPrRepo := s.prRepo.WithTx(tx)
+		txUserRepo := s.userRepo.WithTx(tx)
+		txTeamRepo := s.teamRepo.WithTx(tx)
+		txHistoryRepo := s.historyRepo.WithTx(tx)
+
+		var err error
+		pr, err = s.getPRForReassign(txCtx, req.PullRequestID, txPrRepo)
+		if err != nil {
+			return err
+		}
+
+		reviewers, oldReviewer, err := s.getOldReviewer(txCtx, pr.PullRequestID, req.UserID, txPrRepo)
+		if err != nil {
+			return err
+		}
+		removed = oldReviewer
+
+		if err := txPrRepo.RemoveReviewer(txCtx, pr.PullRequestID, removed.UserID); err != nil {
+			return err
+		}
+		if err := s.recordReviewerEvent(txCtx, txHistoryRepo, pr.PullRequestID, removed.UserID, models.EventRemoved); err != nil {
+			return err
+		}
+
+		resp = &dto.ReviewerChangeResponse{}
+
+		if req.Backfill {
+			author, err := s.getAuthorWithTeamLock(txCtx, pr.AuthorID, txUserRepo)
+			if err != nil {
+				return err
+			}
+
+			seed := s.seeds(pr.PullRequestID + "/" + removed.UserID)
+			rng := rand.New(rand.NewSource(seed))
+			newReviewerID, err := s.pickNewReviewer(txCtx, reviewers, author, rng, txUserRepo, txTeamRepo)
+			switch {
+			case errors.Is(err, ErrNoCandidate), errors.Is(err, ErrReviewersAtCapacity):
+				log.Printf("No backfill candidate for PR %s", pr.PullRequestID)
+			case err != nil:
+				return err
+			default:
+				if err := txPrRepo.AddReviewer(txCtx, pr.PullRequestID, newReviewerID); err != nil {
+					return err
+				}
+				if err := s.logReviewerAssignments(txCtx, txHistoryRepo, pr.PullRequestID, []string{newReviewerID}, seed); err != nil {
+					return err
+				}
+				resp.ReplacedBy = newReviewerID
+			}
+		}
+
+		resp.PR, err = s.loadPullRequestDTO(txCtx, pr, txPrRepo)
+		return err
+	})
+
+	if err != nil {
+		log.Printf("Transaction failed for RemoveReviewer: %v", err)
+		return nil, err
+	}
+
+	if resp.ReplacedBy != "" {
+		s.notifyAssignment(ctx, removed.TeamID, pr, removed, []string{resp.ReplacedBy})
+	}
+
+	log.Printf("Reviewer %s removed from PR %s (replaced by %q)", removed.UserID, pr.PullRequestID, resp.ReplacedBy)
+	return resp, nil
+}
+
+// SetReviewerPinned pins or unpins an assigned reviewer. Pinned reviewers are
+// never swapped out by reassignment, SLA escalation or user deactivation.
+func (s *PRServiceImpl) SetReviewerPinned(ctx context.Context, req *dto.PinReviewerRequest, pinned bool) (*dto.ReviewerChangeResponse, error) {
+	var resp *dto.ReviewerChangeResponse
+
+	err := s.txManager.Do(ctx, func(txCtx context.Context, tx *gorm.DB) error {
+		txPrRepo := s.prRepo.WithTx(tx)
+		txHistoryRepo := s.historyRepo.WithTx(tx)
+
+		pr, err := s.getPRForReassign(txCtx, req.PullRequestID, txPrRepo)
+		if err != nil {
+			return err
+		}
+
+		before, err := txPrRepo.ListPinnedReviewers(txCtx, pr.PullRequestID)
+		if err != nil {
+			return err
+		}
+
+		updated, err := txPrRepo.SetReviewerPinned(txCtx, pr.PullRequestID, req.UserID, pinned)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return ErrReviewerNotAssigned
+		}
+
+		if slices.Contains(before, req.UserID) != pinned {
+			eventType := models.EventUnpinned
+			if pinned {
+				eventType = models.EventPinned
+			}
+			if err := s.recordReviewerEvent(txCtx, txHistoryRepo, pr.PullRequestID, req.UserID, eventType); err != nil {
+				return err
+			}
+		}
+
+		prDTO, err := s.loadPullRequestDTO(txCtx, pr, txPrRepo)
+		if err != nil {
+			return err
+		}
+		resp = &dto.ReviewerChangeResponse{PR: prDTO}
+		return nil
+	})
+
+	if err != nil {
+		log.Printf("Transaction failed for SetReviewerPinned: %v", err)
+		return nil, err
+	}
+
+	log.Printf("Reviewer %s on PR %s pinned=%v", req.UserID, req.PullRequestID, pinned)
+	return resp, nil
+}
+
+func (s *PRServiceImpl) GetLabels(ctx context.Context, prID string) (*dto.PRLabels, error) {
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		log.Printf("PR not found: %v", prID)
+		return nil, ErrPRNotFound
+	}
+
+	labels, err := s.tagRepo.ListPRLabels(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if labels == nil {
+		labels = []string{}
+	}
+
+	return &dto.PRLabels{PullRequestID: prID, Labels: labels}, nil
+}
+
+func (s *PRServiceImpl) AddLabels(ctx context.Context, req *dto.PRLabels) (*dto.PRLabels, error) {
+	labels, err := normalizeTags(req.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, err := s.prRepo.GetByID(ctx, req.PullRequestID)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		log.Printf("PR not found: %v", req.PullRequestID)
+		return nil, ErrPRNotFound
+	}
+
+	if err := s.tagRepo.AddPRLabels(ctx, pr.PullRequestID, labels); err != nil {
+		return nil, err
+	}
+
+	return s.GetLabels(ctx, pr.PullRequestID)
+}
+
+func (s *PRServiceImpl) RemoveLabels(ctx context.Context, req *dto.PRLabels) (*dto.PRLabels, error) {
+	labels, err := normalizeTags(req.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, err := s.prRepo.GetByID(ctx, req.PullRequestID)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		log.Printf("PR not found: %v", req.PullRequestID)
+		return nil, ErrPRNotFound
+	}
+
+	if err := s.tagRepo.RemovePRLabels(ctx, pr.PullRequestID, labels); err != nil {
+		return nil, err
+	}
+
+	return s.GetLabels(ctx, pr.PullRequestID)
+}
+
+func mapPullRequestToDTO(pr *models.PullRequest, reviewers []models.User) dto.PullRequestDTO {
+	reviewersStr := make([]string, len(reviewers))
+	for i, r := range reviewers {
+		reviewersStr[i] = r.UserID
+	}
+
+	return dto.PullRequestDTO{
+		PullRequestID:     pr.PullRequestID,
+		PullRequestName:   pr.PullRequestName,
+		AuthorID:          pr.AuthorID,
+		Status:            dto.PRStatus(pr.Status),
+		AssignedReviewers: reviewersStr,
+		SelectionSeed:     pr.SelectionSeed,
+		CreatedAt:         &pr.CreatedAt,
+		MergedAt:          pr.MergedAt,
+	}
+}
+
+func (s *PRServiceImpl) loadPullRequestDTO(ctx context.Context, pr *models.PullRequest, prRepo repository.PullRequestRepository) (dto.PullRequestDTO, error) {
+	reviewers, err := prRepo.ListReviewers(ctx, pr.PullRequestID)
+	if err != nil {
+		return dto.PullRequestDTO{}, err
+	}
+
+	pinned, err := prRepo.ListPinnedReviewers(ctx, pr.PullRequestID)
+	if err != nil {
+		return dto.PullRequestDTO{}, err
+	}
+
+	prDTO := mapPullRequestToDTO(pr, reviewers)
+	prDTO.PinnedReviewers = pinned
+	return prDTO, nil
+}
+
+func (s *PRServiceImpl) createPullRequest(ctx context.Context, req *dto.CreatePRRequest, seed int64, prRepo repository.PullRequestRepository) (*models.PullRequest, error) {
+	existing, err := prRepo.GetByID(ctx, req.PullRequestID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrPRExists
+	}
+
+	author, err := s.userRepo.GetByID(ctx, req.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	if author == nil {
+		return nil, ErrUserNotFound
+	}
+
+	pr := models.PullRequest{
+		PullRequestID:   req.PullRequestID,
+		PullRequestName: req.PullRequestName,
+		AuthorID:        req.AuthorID,
+		Status:          models.PROpen,
+		CreatedAt:       time.Now(),
+		SelectionSeed:   &seed,
+	}
+
+	// A concurrent request may have created the PR since the lookup.
+	if err := prRepo.Create(ctx, pr); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, ErrPRExists.Wrap(err)
+		}
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
+func (s *PRServiceImpl) getAuthorWithTeamLock(ctx context.Context, authorID string, userRepo repository.UserRepository) (*models.User, error) {
+	author, err := userRepo.GetByID(ctx, authorID)
+	if err != nil {
+		return nil, err
+	}
+	if author == nil {
+		return nil, ErrUserNotFound
+	}
+
+	_, err = userRepo.ListActiveByTeam(ctx, author.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	return author, nil
+}
+
+// findCodeOwners resolves the ownership rules of the author's team against the
+// changed files. For every file only the last matching rule counts, as in
+// CODEOWNERS; owners are expanded to active users other than the author.
+func (s *PRServiceImpl) findCodeOwners(
+	ctx context.Context,
+	author *models.User,
+	changedFiles []string,
+	ownershipRepo repository.OwnershipRuleRepository,
+	userRepo repository.UserRepository,
+	teamRepo repository.TeamRepository,
+) ([]codeOwner, error) {
+
+	if len(changedFiles) == 0 {
+		return nil, nil
+	}
+
+	rules, err := ownershipRepo.ListByTeam(ctx, author.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	patterns := make([]*codeowners.Pattern, len(rules))
+	for i, rule := range rules {
+		p, err := codeowners.NewPattern(rule.Pattern)
+		if err != nil {
+			log.Printf("Skipping invalid ownership pattern %q: %v", rule.Pattern, err)
+			continue
+		}
+		patterns[i] = p
+	}
+
+	var matched []models.OwnershipRule
+	seenRules := map[uuid.UUID]struct{}{}
+	for _, file := range changedFiles {
+		for i := len(rules) - 1; i >= 0; i-- {
+			if patterns[i] == nil || !patterns[i].Match(file) {
+				continue
+			}
+			if _, ok := seenRules[rules[i].RuleID]; !ok {
+				seenRules[rules[i].RuleID] = struct{}{}
+				matched = append(matched, rules[i])
+			}
+			break
+		}
+	}
+
+	var owners []codeOwner
+	seenUsers := map[string]struct{}{author.UserID: {}}
+	for _, rule := range matched {
+		for _, owner := range strings.Fields(rule.Owners) {
+			users, err := resolveOwner(ctx, codeowners.OwnerName(owner), userRepo, teamRepo)
+			if err != nil {
+				return nil, err
+			}
+			for _, u := range users {
+				if _, ok := seenUsers[u.UserID]; ok {
+					continue
+				}
+				seenUsers[u.UserID] = struct{}{}
+				owners = append(owners, codeOwner{user: u, rule: rule})
+			}
 		}
+	}
 
-		return nil
-	})
+	return owners, nil
+}
 
+// resolveOwner expands an owner name to active users: a user id resolves to
+// that user, a team name to the active members of the team.
+func resolveOwner(ctx context.Context, name string, userRepo repository.UserRepository, teamRepo repository.TeamRepository) ([]models.User, error) {
+	user, err := userRepo.GetByID(ctx, name)
 	if err != nil {
-		log.Printf("Transaction failed for CreatePR: %v", err)
 		return nil, err
 	}
+	if user != nil {
+		if !user.IsActive {
+			return nil, nil
+		}
+		return []models.User{*user}, nil
+	}
 
-	log.Printf("CreatePR completed successfully for PRID=%s", req.PullRequestID)
-	return resp, nil
+	team, err := teamRepo.GetByName(ctx, name)
+	if err != nil || team == nil {
+		return nil, err
+	}
+	return userRepo.ListActiveByTeam(ctx, team.TeamID)
 }
 
-func (s *PRServiceImpl) MergePR(ctx context.Context, req *dto.MergePRRequest) (*dto.MergePRResponse, error) {
-	pr, err := s.prRepo.GetByID(ctx, req.PullRequestID)
-	if err != nil || pr == nil {
-		log.Printf("PR not found: %v", req.PullRequestID)
-		return nil, ErrPRNotFound
+// selectReviewers picks up to two reviewers: a code owner of the changed files
+// first, then teammates whose skills match the PR labels (the more matching
+// skills, the likelier the pick), then random teammates. With a rotation
+// window configured for the team, reviewers of the author's recent PRs are
+// down-weighted at every step.
+func (s *PRServiceImpl) selectReviewers(
+	ctx context.Context,
+	rng *rand.Rand,
+	author *models.User,
+	prID string,
+	owners []codeOwner,
+	labels []string,
+	userRepo repository.UserRepository,
+	teamRepo repository.TeamRepository,
+	tagRepo repository.TagRepository,
+	historyRepo repository.ReviewerHistoryRepository,
+) (*selection, error) {
+
+	maxAssign := maxReviewers
+	sel := &selection{reviewers: make([]string, 0, maxAssign), owners: owners}
+
+	recent, err := s.recentReviewers(ctx, author, prID, historyRepo)
+	if err != nil {
+		return nil, err
 	}
+	sel.recentReviews = recent
 
-	if pr.Status == models.PRMerged {
-		reviewers, _ := s.prRepo.ListReviewers(ctx, req.PullRequestID)
-		log.Printf("PR already merged: %v", req.PullRequestID)
-		return &dto.MergePRResponse{
-			PR: mapPullRequestToDTO(pr, reviewers),
-		}, nil
+	rotated := func(userID string, weight float64) weightedCandidate {
+		return weightedCandidate{userID: userID, weight: rotationWeight(weight, recent[userID])}
 	}
 
-	mergeTime := time.Now()
-	newPr := models.PullRequest{
-		PullRequestID:   pr.PullRequestID,
-		PullRequestName: pr.PullRequestName,
-		AuthorID:        pr.AuthorID,
-		Status:          models.PRMerged,
-		CreatedAt:       pr.CreatedAt,
-		MergedAt:        &mergeTime,
+	users, err := userRepo.ListActiveByTeam(ctx, author.TeamID)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := s.prRepo.Update(ctx, newPr); err != nil {
-		log.Printf("Failed to merge PR: %v", err)
+	ownerUsers := make([]models.User, len(owners))
+	for i, owner := range owners {
+		ownerUsers[i] = owner.user
+	}
+	sel.atCapacity, err = atCapacity(ctx, append(ownerUsers, users...), userRepo)
+	if err != nil {
 		return nil, err
 	}
 
-	reviewers, _ := s.prRepo.ListReviewers(ctx, req.PullRequestID)
-	return &dto.MergePRResponse{
-		PR: mapPullRequestToDTO(&newPr, reviewers),
-	}, nil
+	var pool []weightedCandidate
+	for _, owner := range owners {
+		if _, full := sel.atCapacity[owner.user.UserID]; !full {
+			pool = append(pool, rotated(owner.user.UserID, 1))
+		}
+	}
+	sel.reviewers = append(sel.reviewers, pickWeighted(rng, pool, 1)...)
+
+	candidates := make([]string, 0, len(users))
+	for _, u := range users {
+		if _, full := sel.atCapacity[u.UserID]; full {
+			continue
+		}
+		if u.UserID != author.UserID && !slices.Contains(sel.reviewers, u.UserID) {
+			candidates = append(candidates, u.UserID)
+		}
+	}
+
+	var skilled []weightedCandidate
+	others := candidates
+	if len(labels) > 0 {
+		matched, err := matchSkills(ctx, append(slices.Clone(sel.reviewers), candidates...), labels, tagRepo)
+		if err != nil {
+			return nil, err
+		}
+		sel.matchedSkills = matched
+
+		others = nil
+		for _, id := range candidates {
+			if n := len(matched[id]); n > 0 {
+				skilled = append(skilled, rotated(id, float64(n)))
+			} else {
+				others = append(others, id)
+			}
+		}
+	}
+
+	sel.reviewers = append(sel.reviewers, pickWeighted(rng, skilled, maxAssign-len(sel.reviewers))...)
+
+	if len(recent) == 0 {
+		sel.reviewers = append(sel.reviewers, pickRandom(rng, others, maxAssign-len(sel.reviewers))...)
+	} else {
+		pool := make([]weightedCandidate, len(others))
+		for i, id := range others {
+			pool[i] = rotated(id, 1)
+		}
+		sel.reviewers = append(sel.reviewers, pickWeighted(rng, pool, maxAssign-len(sel.reviewers))...)
+	}
+
+	if len(sel.reviewers) < maxAssign {
+		if err := s.fillFromAncestors(ctx, rng, author, sel, maxAssign, userRepo, teamRepo); err != nil {
+			return nil, err
+		}
+	}
+
+	return sel, nil
 }
 
-func (s *PRServiceImpl) ReassignReviewer(ctx context.Context, req *dto.ReassignReviewerRequest) (*dto.ReassignReviewerResponse, error) {
-	var resp *dto.ReassignReviewerResponse
+// fillFromAncestors takes the missing reviewers from the parent team, then
+// its parent and so on, when the author's own team is too small or busy.
+func (s *PRServiceImpl) fillFromAncestors(
+	ctx context.Context,
+	rng *rand.Rand,
+	author *models.User,
+	sel *selection,
+	maxAssign int,
+	userRepo repository.UserRepository,
+	teamRepo repository.TeamRepository,
+) error {
 
-	err := s.txManager.Do(ctx, func(txCtx context.Context, tx *gorm.DB) error {
-		txPrRepo := s.prRepo.WithTx(tx)
-		txUserRepo := s.userRepo.WithTx(tx)
-		txHistoryRepo := s.historyRepo.WithTx(tx)
+	ancestors, err := teamRepo.ListAncestors(ctx, author.TeamID)
+	if err != nil {
+		return err
+	}
 
-		pr, err := s.getPRForReassign(txCtx, req.PullRequestID, txPrRepo)
-		if err != nil {
-			log.Printf("Failed to get PR for reassign: %v", err)
-			return err
+	for _, team := range ancestors {
+		if len(sel.reviewers) >= maxAssign {
+			break
 		}
 
-		reviewers, oldReviewer, err := s.getOldReviewer(txCtx, pr.PullRequestID, req.OldUserID, txPrRepo)
+		users, err := userRepo.ListActiveByTeam(ctx, team.TeamID)
 		if err != nil {
-			log.Printf("Failed to get old reviewer: %v", err)
 			return err
 		}
-
-		newReviewerID, err := s.pickNewReviewer(txCtx, reviewers, oldReviewer.TeamID, pr.AuthorID, txUserRepo)
+		full, err := atCapacity(ctx, users, userRepo)
 		if err != nil {
-			log.Printf("Failed to pick new reviewer: %v", err)
 			return err
 		}
 
-		if err := s.updateReviewers(txCtx, pr.PullRequestID, req.OldUserID, newReviewerID, txPrRepo); err != nil {
-			log.Printf("Failed to update reviewers: %v", err)
-			return err
+		byID := make(map[string]models.User, len(users))
+		var candidates []string
+		for _, u := range users {
+			if _, ok := full[u.UserID]; ok {
+				continue
+			}
+			if u.UserID != author.UserID && !slices.Contains(sel.reviewers, u.UserID) {
+				candidates = append(candidates, u.UserID)
+				byID[u.UserID] = u
+			}
 		}
 
-		if err := s.logReviewerAssignments(txCtx, txHistoryRepo, pr.PullRequestID, []string{newReviewerID}); err != nil {
-			log.Printf("Failed to log reassignment: %v", err)
-			return err
+		for _, id := range pickRandom(rng, candidates, maxAssign-len(sel.reviewers)) {
+			if sel.fallback == nil {
+				sel.fallback = map[string]fallbackPick{}
+			}
+			sel.fallback[id] = fallbackPick{user: byID[id], teamName: team.TeamName}
+			sel.reviewers = append(sel.reviewers, id)
 		}
+	}
 
-		updatedReviewers, _ := txPrRepo.ListReviewers(txCtx, pr.PullRequestID)
+	return nil
+}
 
-		resp = &dto.ReassignReviewerResponse{
-			PR:         mapPullRequestToDTO(pr, updatedReviewers),
-			ReplacedBy: newReviewerID,
+// shortfallAtCapacity returns, sorted, the candidates left out only because
+// they are at their review limit.
+func shortfallAtCapacity(sel *selection, authorID string) []string {
+	var ids []string
+	for id := range sel.atCapacity {
+		if id != authorID {
+			ids = append(ids, id)
 		}
+	}
+	slices.Sort(ids)
+	return ids
+}
 
-		return nil
-	})
+// atCapacity returns the users who already review as many OPEN pull requests
+// as their MaxOpenReviews allows.
+func atCapacity(ctx context.Context, users []models.User, userRepo repository.UserRepository) (map[string]struct{}, error) {
+	var limited []string
+	for _, u := range users {
+		if u.MaxOpenReviews != nil {
+			limited = append(limited, u.UserID)
+		}
+	}
+	if len(limited) == 0 {
+		return nil, nil
+	}
 
+	open, err := userRepo.CountOpenReviews(ctx, limited)
 	if err != nil {
-		log.Printf("Transaction failed for ReassignReviewer: %v", err)
 		return nil, err
 	}
 
-	return resp, nil
+	full := map[string]struct{}{}
+	for _, u := range users {
+		if u.MaxOpenReviews != nil && open[u.UserID] >= *u.MaxOpenReviews {
+			full[u.UserID] = struct{}{}
+		}
+	}
+	return full, nil
 }
 
-func mapPullRequestToDTO(pr *models.PullRequest, reviewers []models.User) dto.PullRequestDTO {
-	reviewersStr := make([]string, len(reviewers))
-	for i, r := range reviewers {
-		reviewersStr[i] = r.UserID
-	}
+// recentReviewers counts, per reviewer, how many of the author's last N pull
+// requests they were assigned to, N being the team's rotation window. Nil
+// means rotation is not configured.
+func (s *PRServiceImpl) recentReviewers(
+	ctx context.Context,
+	author *models.User,
+	prID string,
+	historyRepo repository.ReviewerHistoryRepository,
+) (map[string]int, error) {
 
-	return dto.PullRequestDTO{
-		PullRequestID:     pr.PullRequestID,
-		PullRequestName:   pr.PullRequestName,
-		AuthorID:          pr.AuthorID,
-		Status:            dto.PRStatus(pr.Status),
-		AssignedReviewers: reviewersStr,
-		CreatedAt:         &pr.CreatedAt,
-		MergedAt:          pr.MergedAt,
+	settings, err := s.teamSettingsRepo.Get(ctx, author.TeamID)
+	if err != nil {
+		return nil, err
 	}
+	if settings == nil || settings.RotationWindow == nil {
+		return nil, nil
+	}
+
+	return historyRepo.CountRecentReviewsOfAuthor(ctx, author.UserID, prID, *settings.RotationWindow)
 }
 
-func (s *PRServiceImpl) createPullRequest(ctx context.Context, req *dto.CreatePRRequest, prRepo repository.PullRequestRepository) (*models.PullRequest, error) {
-	existing, err := prRepo.GetByID(ctx, req.PullRequestID)
+// rotationWeight lowers the chance of a reviewer who already reviewed recent
+// PRs of the same author.
+func rotationWeight(weight float64, recentReviews int) float64 {
+	return weight / float64(1+recentReviews)
+}
+
+// matchSkills returns, per user, the skills that appear among the labels.
+func matchSkills(ctx context.Context, userIDs []string, labels []string, tagRepo repository.TagRepository) (map[string][]string, error) {
+	skills, err := tagRepo.ListSkillsByUsers(ctx, userIDs)
 	if err != nil {
 		return nil, err
 	}
-	if existing != nil {
-		return nil, ErrPRExists
+
+	matched := make(map[string][]string, len(skills))
+	for userID, tags := range skills {
+		for _, tag := range tags {
+			if slices.Contains(labels, tag) {
+				matched[userID] = append(matched[userID], tag)
+			}
+		}
 	}
+	return matched, nil
+}
 
-	author, err := s.userRepo.GetByID(ctx, req.AuthorID)
-	if err != nil || author == nil {
-		return nil, ErrUserNotFound
+type weightedCandidate struct {
+	userID string
+	weight float64
+}
+
+// pickWeighted draws up to k distinct candidates, each draw proportional to
+// the weights of the candidates that are still left.
+func pickWeighted(rng *rand.Rand, candidates []weightedCandidate, k int) []string {
+	pool := slices.Clone(candidates)
+	picked := make([]string, 0, max(k, 0))
+
+	for len(picked) < k && len(pool) > 0 {
+		var total float64
+		for _, c := range pool {
+			total += c.weight
+		}
+
+		r := rng.Float64() * total
+		idx := len(pool) - 1
+		for i, c := range pool {
+			if r < c.weight {
+				idx = i
+				break
+			}
+			r -= c.weight
+		}
+
+		picked = append(picked, pool[idx].userID)
+		pool = slices.Delete(pool, idx, idx+1)
 	}
 
-	pr := models.PullRequest{
-		PullRequestID:   req.PullRequestID,
-		PullRequestName: req.PullRequestName,
-		AuthorID:        req.AuthorID,
-		Status:          models.PROpen,
-		CreatedAt:       time.Now(),
+	return picked
+}
+
+// pickRandom draws up to k distinct candidates uniformly with a partial
+// Fisher-Yates shuffle, so it never re-rolls already used picks.
+func pickRandom(rng *rand.Rand, candidates []string, k int) []string {
+	pool := slices.Clone(candidates)
+	k = min(k, len(pool))
+	if k <= 0 {
+		return nil
 	}
 
-	if err := prRepo.Create(ctx, pr); err != nil {
-		return nil, err
+	for i := 0; i < k; i++ {
+		j := i + rng.Intn(len(pool)-i)
+		pool[i], pool[j] = pool[j], pool[i]
 	}
 
-	return &pr, nil
+	return pool[:k]
 }
 
-func (s *PRServiceImpl) getAuthorWithTeamLock(ctx context.Context, authorID string, userRepo repository.UserRepository) (*models.User, error) {
-	author, err := userRepo.GetByID(ctx, authorID)
-	if err != nil || author == nil {
-		return nil, ErrUserNotFound
+func mapReviewerMatches(sel *selection) []dto.ReviewerMatch {
+	matches := make([]dto.ReviewerMatch, len(sel.reviewers))
+	for i, reviewerID := range sel.reviewers {
+		matches[i] = dto.ReviewerMatch{
+			ReviewerID:    reviewerID,
+			MatchedSkills: sel.matchedSkills[reviewerID],
+			RecentReviews: sel.recentReviews[reviewerID],
+			FallbackTeam:  sel.fallback[reviewerID].teamName,
+		}
+		for _, owner := range sel.owners {
+			if owner.user.UserID == reviewerID {
+				rule := mapOwnershipRuleToDTO(owner.rule)
+				matches[i].MatchedRule = &rule
+				break
+			}
+		}
 	}
+	return matches
+}
 
-	_, err = userRepo.ListActiveByTeam(ctx, author.TeamID)
-	if err != nil {
-		return nil, err
+// explainSelection lists the author's team members and outside code owners
+// with the reasons the selection considered or skipped them. Eligible
+// candidates are ranked the way selectReviewers prefers them: the picked
+// reviewers in pick order, then owners, skill matches and the rest of the
+// team, each by weight.
+func explainSelection(author *models.User, members []models.User, sel *selection) []dto.ReviewerCandidate {
+	owned := make(map[string]models.OwnershipRule, len(sel.owners))
+	for _, owner := range sel.owners {
+		owned[owner.user.UserID] = owner.rule
 	}
 
-	return author, nil
-}
+	candidates := make([]dto.ReviewerCandidate, 0, len(members)+len(sel.owners))
+	seen := map[string]struct{}{}
+	add := func(userID, username string, active bool) {
+		seen[userID] = struct{}{}
+		c := dto.ReviewerCandidate{UserID: userID, Username: username}
+
+		switch {
+		case userID == author.UserID:
+			c.Reasons = []dto.CandidateReason{dto.CandidateAuthor}
+			candidates = append(candidates, c)
+			return
+		case !active:
+			c.Reasons = []dto.CandidateReason{dto.CandidateInactive}
+			candidates = append(candidates, c)
+			return
+		}
+		if _, full := sel.atCapacity[userID]; full {
+			c.Reasons = []dto.CandidateReason{dto.CandidateAtCapacity}
+			candidates = append(candidates, c)
+			return
+		}
 
-func (s *PRServiceImpl) selectReviewers(ctx context.Context, authorID string, teamID uuid.UUID, userRepo repository.UserRepository) []string {
-	users, _ := userRepo.ListActiveByTeam(ctx, teamID)
+		c.Eligible = true
+		c.Selected = slices.Contains(sel.reviewers, userID)
+		c.MatchedSkills = sel.matchedSkills[userID]
+		c.RecentReviews = sel.recentReviews[userID]
+		c.Weight = 1
 
-	candidates := make([]string, 0, len(users))
-	for _, u := range users {
-		if u.UserID != authorID {
-			candidates = append(candidates, u.UserID)
+		if rule, ok := owned[userID]; ok {
+			dtoRule := mapOwnershipRuleToDTO(rule)
+			c.MatchedRule = &dtoRule
+			c.Reasons = append(c.Reasons, dto.CandidateCodeOwner)
+		}
+		if n := len(c.MatchedSkills); n > 0 {
+			c.Reasons = append(c.Reasons, dto.CandidateSkillMatch)
+			if c.MatchedRule == nil {
+				c.Weight = float64(n)
+			}
+		}
+		if _, ok := sel.fallback[userID]; ok {
+			c.Reasons = append(c.Reasons, dto.CandidateParentTeam)
+		}
+		if len(c.Reasons) == 0 {
+			c.Reasons = append(c.Reasons, dto.CandidateTeamMember)
 		}
+		if c.RecentReviews > 0 {
+			c.Reasons = append(c.Reasons, dto.CandidateRecentReviewer)
+		}
+		c.Weight = rotationWeight(c.Weight, c.RecentReviews)
+
+		candidates = append(candidates, c)
 	}
 
-	n := len(candidates)
-	if n == 0 {
-		return nil
+	for _, m := range members {
+		add(m.UserID, m.Username, m.IsActive)
+	}
+	for _, owner := range sel.owners {
+		if _, ok := seen[owner.user.UserID]; !ok {
+			add(owner.user.UserID, owner.user.Username, true)
+		}
+	}
+	for _, id := range sel.reviewers {
+		if pick, ok := sel.fallback[id]; ok {
+			add(id, pick.user.Username, true)
+		}
 	}
 
-	maxAssign := 2
-	if n < maxAssign {
-		maxAssign = n
+	tier := func(c dto.ReviewerCandidate) int {
+		switch {
+		case !c.Eligible:
+			return 4
+		case c.Selected:
+			return 0
+		case c.MatchedRule != nil:
+			return 1
+		case len(c.MatchedSkills) > 0:
+			return 2
+		default:
+			return 3
+		}
 	}
 
-	selected := make([]string, 0, maxAssign)
-	used := make(map[int]struct{})
+	slices.SortStableFunc(candidates, func(a, b dto.ReviewerCandidate) int {
+		if ta, tb := tier(a), tier(b); ta != tb {
+			return ta - tb
+		}
+		if a.Selected {
+			return slices.Index(sel.reviewers, a.UserID) - slices.Index(sel.reviewers, b.UserID)
+		}
+		if a.Weight != b.Weight {
+			if a.Weight > b.Weight {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.UserID, b.UserID)
+	})
 
-	for len(selected) < maxAssign {
-		idx := rand.Intn(n)
-		if _, ok := used[idx]; ok {
-			continue
+	for i := range candidates {
+		if candidates[i].Eligible {
+			candidates[i].Rank = i + 1
 		}
-		used[idx] = struct{}{}
-		selected = append(selected, candidates[idx])
 	}
 
-	return selected
+	return candidates
 }
 
 func (s *PRServiceImpl) assignReviewers(ctx context.Context, prID string, reviewers []string, prRepo repository.PullRequestRepository) error {
@@ -289,7 +1320,10 @@ func (s *PRServiceImpl) assignReviewers(ctx context.Context, prID string, review
 
 func (s *PRServiceImpl) getPRForReassign(ctx context.Context, prID string, prRepo repository.PullRequestRepository) (*models.PullRequest, error) {
 	pr, err := prRepo.GetByID(ctx, prID)
-	if err != nil || pr == nil {
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
 		return nil, ErrPRNotFound
 	}
 
@@ -327,43 +1361,76 @@ func (s *PRServiceImpl) getOldReviewer(
 	return reviewers, oldReviewer, nil
 }
 
+// pickNewReviewer picks a replacement from the author's team, as CreatePR
+// does, and, when nobody there is free, from its parent teams nearest first.
 func (s *PRServiceImpl) pickNewReviewer(
 	ctx context.Context,
 	reviewers []models.User,
-	teamID uuid.UUID,
-	authorID string,
+	author *models.User,
+	rng *rand.Rand,
 	userRepo repository.UserRepository,
+	teamRepo repository.TeamRepository,
 ) (string, error) {
 
-	users, err := userRepo.ListActiveByTeam(ctx, teamID)
-	if err != nil {
-		return "", err
-	}
-
 	assigned := map[string]struct{}{}
 	for _, r := range reviewers {
 		assigned[r.UserID] = struct{}{}
 	}
 
-	var candidates []string
-	for _, u := range users {
+	teamIDs := []uuid.UUID{author.TeamID}
+	ancestors, err := teamRepo.ListAncestors(ctx, author.TeamID)
+	if err != nil {
+		return "", err
+	}
+	for _, t := range ancestors {
+		teamIDs = append(teamIDs, t.TeamID)
+	}
 
-		if u.UserID == authorID {
-			continue
+	anyFull := false
+	for _, id := range teamIDs {
+		users, err := userRepo.ListActiveByTeam(ctx, id)
+		if err != nil {
+			return "", err
 		}
 
-		if _, exists := assigned[u.UserID]; exists {
-			continue
+		var available []models.User
+		for _, u := range users {
+
+			if u.UserID == author.UserID {
+				continue
+			}
+
+			if _, exists := assigned[u.UserID]; exists {
+				continue
+			}
+
+			available = append(available, u)
 		}
 
-		candidates = append(candidates, u.UserID)
-	}
+		full, err := atCapacity(ctx, available, userRepo)
+		if err != nil {
+			return "", err
+		}
+		if len(full) > 0 {
+			anyFull = true
+		}
+
+		var candidates []string
+		for _, u := range available {
+			if _, ok := full[u.UserID]; !ok {
+				candidates = append(candidates, u.UserID)
+			}
+		}
 
-	if len(candidates) == 0 {
-		return "", ErrNoCandidate
+		if len(candidates) > 0 {
+			return candidates[rng.Intn(len(candidates))], nil
+		}
 	}
 
-	return candidates[rand.Intn(len(candidates))], nil
+	if anyFull {
+		return "", ErrReviewersAtCapacity
+	}
+	return "", ErrNoCandidate
 }
 
 func (s *PRServiceImpl) updateReviewers(
@@ -385,11 +1452,14 @@ func (s *PRServiceImpl) updateReviewers(
 	return nil
 }
 
+// logReviewerAssignments records the picks together with the selection seed,
+// so every assignment can be replayed, not only the one at PR creation.
 func (s *PRServiceImpl) logReviewerAssignments(
 	ctx context.Context,
 	txRepo repository.ReviewerHistoryRepository,
 	prID string,
 	reviewers []string,
+	seed int64,
 ) error {
 
 	for _, reviewerID := range reviewers {
@@ -397,9 +1467,10 @@ func (s *PRServiceImpl) logReviewerAssignments(
 			AssigmentHistoryID: uuid.New(),
 			PrID:               prID,
 			UserID:             reviewerID,
+			EventType:          models.EventAssigned,
+			SelectionSeed:      &seed,
 			CreatedAt:          time.Now(),
 		}
-
 		if err := txRepo.AddEvent(ctx, event); err != nil {
 			return err
 		}
@@ -407,3 +1478,68 @@ func (s *PRServiceImpl) logReviewerAssignments(
 
 	return nil
 }
+
+func (s *PRServiceImpl) recordReviewerEvent(
+	ctx context.Context,
+	txRepo repository.ReviewerHistoryRepository,
+	prID string,
+	userID string,
+	eventType models.HistoryEventType,
+) error {
+
+	event := models.ReviewerAssignmentHistory{
+		AssigmentHistoryID: uuid.New(),
+		PrID:               prID,
+		UserID:             userID,
+		EventType:          eventType,
+		CreatedAt:          time.Now(),
+	}
+
+	return txRepo.AddEvent(ctx, event)
+}
+
+func (s *PRServiceImpl) notifyAssignment(
+	ctx context.Context,
+	teamID uuid.UUID,
+	pr *models.PullRequest,
+	replaced *models.User,
+	reviewerIDs []string,
+) {
+
+	if len(reviewerIDs) == 0 {
+		return
+	}
+
+	team, err := s.teamRepo.GetByID(ctx, teamID)
+	if err != nil || team == nil || team.WebhookURL == nil {
+		return
+	}
+
+	assignment := notifier.Assignment{
+		TeamName:        team.TeamName,
+		WebhookURL:      *team.WebhookURL,
+		PullRequestID:   pr.PullRequestID,
+		PullRequestName: pr.PullRequestName,
+		Author:          lookupPerson(ctx, s.userRepo, pr.AuthorID),
+	}
+
+	for _, id := range reviewerIDs {
+		assignment.Reviewers = append(assignment.Reviewers, lookupPerson(ctx, s.userRepo, id))
+	}
+
+	if replaced != nil {
+		assignment.Replaced = &notifier.Person{ID: replaced.UserID, Name: replaced.Username}
+	}
+
+	if err := s.notifier.NotifyAssignment(ctx, assignment); err != nil {
+		log.Printf("Failed to notify reviewers of PR %s: %v", pr.PullRequestID, err)
+	}
+}
+
+func lookupPerson(ctx context.Context, userRepo repository.UserRepository, userID string) notifier.Person {
+	user, err := userRepo.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		return notifier.Person{ID: userID, Name: userID}
+	}
+	return notifier.Person{ID: user.UserID, Name: user.Username}
+}
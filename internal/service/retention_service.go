@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/models"
+	"github.com/mink0ff/pr_service/internal/repository"
+	"github.com/mink0ff/pr_service/internal/repository/transaction"
+	"gorm.io/gorm"
+)
+
+const (
+	RetentionArchive = "archive"
+	RetentionDelete  = "delete"
+)
+
+type RetentionServiceImpl struct {
+	retentionRepo repository.RetentionRepository
+	teamRepo      repository.TeamRepository
+	userRepo      repository.UserRepository
+	auditRepo     repository.TeamAuditRepository
+	txManager     *transaction.Manager
+	period        time.Duration
+	mode          string
+	now           func() time.Time
+}
+
+// NewRetentionService purges records deleted longer than period ago; mode is
+// RetentionArchive or RetentionDelete.
+func NewRetentionService(
+	retentionRepo repository.RetentionRepository,
+	teamRepo repository.TeamRepository,
+	userRepo repository.UserRepository,
+	auditRepo repository.TeamAuditRepository,
+	txManager *transaction.Manager,
+	period time.Duration,
+	mode string,
+) RetentionService {
+	return &RetentionServiceImpl{
+		retentionRepo: retentionRepo,
+		teamRepo:      teamRepo,
+		userRepo:      userRepo,
+		auditRepo:     auditRepo,
+		txManager:     txManager,
+		period:        period,
+		mode:          mode,
+		now:           time.Now,
+	}
+}
+
+func parseDeletedEntity(entity string) (models.DeletedEntity, error) {
+	e := models.DeletedEntity(entity)
+	if !slices.Contains(models.DeletedEntities, e) {
+		return "", ErrUnknownDeleted
+	}
+	return e, nil
+}
+
+func (s *RetentionServiceImpl) ListDeleted(ctx context.Context, entity string, limit int) (*dto.DeletedRecordsResponse, error) {
+	e, err := parseDeletedEntity(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := s.retentionRepo.ListDeleted(ctx, e, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.DeletedRecordsResponse{Entity: entity, Records: make([]dto.DeletedRecord, 0, len(records))}
+	for _, r := range records {
+		resp.Records = append(resp.Records, mapDeletedRecordToDTO(r))
+	}
+	return resp, nil
+}
+
+// Restore brings a deleted record back as it was. A team or user whose parent
+// team is deleted, or a pull request whose author is, must wait until that is
+// restored. Restored users stay inactive, and a team whose name was taken in
+// the meantime has to be renamed first.
+func (s *RetentionServiceImpl) Restore(ctx context.Context, req *dto.RestoreDeletedRequest) (*dto.DeletedRecord, error) {
+	entity, err := parseDeletedEntity(req.Entity)
+	if err != nil {
+		return nil, err
+	}
+
+	var restored *models.DeletedRecord
+	err = s.txManager.Do(ctx, func(txCtx context.Context, tx *gorm.DB) error {
+		txRetentionRepo := s.retentionRepo.WithTx(tx)
+
+		record, err := txRetentionRepo.GetDeleted(txCtx, entity, req.ID)
+		if err != nil {
+			return err
+		}
+		if record == nil {
+			return ErrNotDeleted
+		}
+
+		team, err := s.restoredTeam(txCtx, tx, record)
+		if err != nil {
+			return err
+		}
+
+		if _, err := txRetentionRepo.Restore(txCtx, entity, req.ID); err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				return ErrTeamExists.Wrap(err)
+			}
+			return err
+		}
+
+		restored = record
+		switch entity {
+		case models.DeletedTeams:
+			return s.auditRepo.WithTx(tx).Add(txCtx, auditEvent(team, models.AuditTeamRestored, "", "", ""))
+		case models.DeletedUsers:
+			return s.auditRepo.WithTx(tx).Add(txCtx, auditEvent(team, models.AuditMemberRestored, record.ID, "", ""))
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Failed to restore %s %s: %v", req.Entity, req.ID, err)
+		return nil, err
+	}
+
+	log.Printf("Restored %s %s", req.Entity, req.ID)
+	resp := mapDeletedRecordToDTO(*restored)
+	return &resp, nil
+}
+
+// restoredTeam checks that the record's parent is live. It returns the team
+// the restore is logged under: the team itself or the user's primary team.
+func (s *RetentionServiceImpl) restoredTeam(ctx context.Context, tx *gorm.DB, record *models.DeletedRecord) (*models.Team, error) {
+	txTeamRepo := s.teamRepo.WithTx(tx)
+
+	switch record.Entity {
+	case models.DeletedTeams:
+		teamID, err := uuid.Parse(record.ID)
+		if err != nil {
+			return nil, err
+		}
+		if record.ParentID != nil {
+			parent, err := s.liveTeam(ctx, txTeamRepo, *record.ParentID)
+			if err != nil {
+				return nil, err
+			}
+			if parent == nil {
+				return nil, ErrParentDeleted.WithDetails("parent_team_id", *record.ParentID)
+			}
+		}
+		return &models.Team{TeamID: teamID, TeamName: record.Name}, nil
+
+	case models.DeletedUsers:
+		team, err := s.liveTeam(ctx, txTeamRepo, *record.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if team == nil {
+			return nil, ErrParentDeleted.WithDetails("team_id", *record.ParentID)
+		}
+		return team, nil
+
+	default:
+		author, err := s.userRepo.WithTx(tx).GetByID(ctx, *record.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if author == nil {
+			return nil, ErrParentDeleted.WithDetails("author_id", *record.ParentID)
+		}
+		return nil, nil
+	}
+}
+
+func (s *RetentionServiceImpl) liveTeam(ctx context.Context, teamRepo repository.TeamRepository, id string) (*models.Team, error) {
+	teamID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	return teamRepo.GetByID(ctx, teamID)
+}
+
+// PurgeExpired removes records deleted longer ago than the retention period.
+// Pull requests go first, so their authors and reviewers can go in the same
+// run, then users and then teams. Records still referenced by live rows,
+// such as a user with review history on a kept pull request, stay deleted
+// until the next run.
+func (s *RetentionServiceImpl) PurgeExpired(ctx context.Context) (*dto.RetentionReport, error) {
+	cutoff := s.now().Add(-s.period)
+	archive := s.mode == RetentionArchive
+	report := &dto.RetentionReport{Mode: s.mode}
+
+	err := s.txManager.Do(ctx, func(txCtx context.Context, tx *gorm.DB) error {
+		txRetentionRepo := s.retentionRepo.WithTx(tx)
+
+		steps := []struct {
+			entity models.DeletedEntity
+			count  *int64
+		}{
+			{models.DeletedPullRequests, &report.PullRequests},
+			{models.DeletedUsers, &report.Users},
+			{models.DeletedTeams, &report.Teams},
+		}
+		for _, step := range steps {
+			n, err := txRetentionRepo.Purge(txCtx, step.entity, cutoff, archive)
+			if err != nil {
+				return err
+			}
+			*step.count = n
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Retention run failed: %v", err)
+		return nil, err
+	}
+
+	log.Printf("Retention run purged %d pull requests, %d users and %d teams deleted before %s (mode=%s)",
+		report.PullRequests, report.Users, report.Teams, cutoff.Format(time.RFC3339), s.mode)
+	return report, nil
+}
+
+func mapDeletedRecordToDTO(r models.DeletedRecord) dto.DeletedRecord {
+	return dto.DeletedRecord{
+		Entity:    string(r.Entity),
+		ID:        r.ID,
+		Name:      r.Name,
+		DeletedAt: r.DeletedAt,
+	}
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+const (
+	SelectionModeRandom = "random"
+	SelectionModePRHash = "pr_hash"
+)
+
+// seedMask keeps seeds within 53 bits so they survive a round trip through
+// JSON numbers in any client.
+const seedMask = 1<<53 - 1
+
+// SeedFunc returns the seed reviewer selection starts from for the given key,
+// normally a pull request id. The seed is stored with the pull request, so an
+// assignment can be reproduced later by passing the same seed back.
+type SeedFunc func(key string) int64
+
+// RandomSeeds draws a fresh seed for every selection from r.
+func RandomSeeds(r *rand.Rand) SeedFunc {
+	var mu sync.Mutex
+	return func(string) int64 {
+		mu.Lock()
+		defer mu.Unlock()
+		return r.Int63() & seedMask
+	}
+}
+
+// HashSeeds derives the seed from the key itself, so the same pull request
+// against the same team state always gets the same reviewers.
+func HashSeeds() SeedFunc {
+	return func(key string) int64 {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(key))
+		return int64(h.Sum64() & seedMask)
+	}
+}
+
+// NewSeedFunc builds the seed source for a selection mode. A zero seed for
+// the random mode means seeding from the clock.
+func NewSeedFunc(mode string, seed int64) (SeedFunc, error) {
+	switch mode {
+	case "", SelectionModeRandom:
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+		}
+		return RandomSeeds(rand.New(rand.NewSource(seed))), nil
+	case SelectionModePRHash:
+		return HashSeeds(), nil
+	default:
+		return nil, fmt.Errorf("unknown reviewer selection mode %q", mode)
+	}
+}
@@ -2,29 +2,102 @@ package service
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/mink0ff/pr_service/internal/dto"
 	"github.com/mink0ff/pr_service/internal/models"
+	"gorm.io/gorm"
 )
 
 type UserService interface {
 	CreateUser(ctx context.Context, req *dto.CreateUserRequest) (*dto.User, error)
+	GetUser(ctx context.Context, userID string) (*dto.UserDetails, error)
+	ListUsers(ctx context.Context, req *dto.ListUsersRequest) (*dto.ListUsersResponse, error)
 	SetActive(ctx context.Context, req dto.SetUserActiveRequest) (*dto.User, error)
 	GetReviewPRs(ctx context.Context, userID string) ([]models.PullRequest, error)
+	GetNotificationSettings(ctx context.Context, userID string) (*dto.NotificationSettings, error)
+	SetNotificationSettings(ctx context.Context, req *dto.NotificationSettings) (*dto.NotificationSettings, error)
+	GetSkills(ctx context.Context, userID string) (*dto.UserSkills, error)
+	AddSkills(ctx context.Context, req *dto.UserSkills) (*dto.UserSkills, error)
+	RemoveSkills(ctx context.Context, req *dto.UserSkills) (*dto.UserSkills, error)
+	GetReviewLimit(ctx context.Context, userID string) (*dto.UserReviewLimit, error)
+	SetReviewLimit(ctx context.Context, req *dto.UserReviewLimit) (*dto.UserReviewLimit, error)
 }
 
 type TeamService interface {
 	CreateTeam(ctx context.Context, req *dto.CreateTeamRequest) (*dto.CreateTeamResponse, error)
 	GetTeam(ctx context.Context, teamName string) (*dto.Team, error)
 	DeactivateTeamUsers(ctx context.Context, req *dto.DeactivateTeamUsersRequest) (*dto.DeactivateTeamUsersResponse, error)
+	SetWebhook(ctx context.Context, req *dto.SetTeamWebhookRequest) (*dto.SetTeamWebhookResponse, error)
+	GetSLA(ctx context.Context, teamName string) (*dto.TeamSLA, error)
+	SetSLA(ctx context.Context, req *dto.TeamSLA) (*dto.TeamSLA, error)
+	GetRotation(ctx context.Context, teamName string) (*dto.TeamRotation, error)
+	SetRotation(ctx context.Context, req *dto.TeamRotation) (*dto.TeamRotation, error)
+	GetOwnershipRules(ctx context.Context, teamName string) (*dto.OwnershipRulesResponse, error)
+	ImportOwnershipRules(ctx context.Context, req *dto.ImportOwnershipRulesRequest) (*dto.OwnershipRulesResponse, error)
+	AddOwnershipRule(ctx context.Context, req *dto.AddOwnershipRuleRequest) (*dto.OwnershipRule, error)
+	DeleteOwnershipRule(ctx context.Context, req *dto.DeleteOwnershipRuleRequest) (*dto.OwnershipRulesResponse, error)
+	AddMember(ctx context.Context, req *dto.AddTeamMemberRequest) (*dto.User, error)
+	MoveMember(ctx context.Context, req *dto.MoveTeamMemberRequest) (*dto.MoveTeamMemberResponse, error)
+	RemoveMember(ctx context.Context, req *dto.RemoveTeamMemberRequest) error
+	AddMembership(ctx context.Context, req *dto.TeamMembershipRequest) (*dto.UserMembershipsResponse, error)
+	RemoveMembership(ctx context.Context, req *dto.RemoveMembershipRequest) (*dto.UserMembershipsResponse, error)
+	RenameTeam(ctx context.Context, req *dto.RenameTeamRequest) (*dto.Team, error)
+	DeleteTeam(ctx context.Context, req *dto.DeleteTeamRequest) error
+	GetAuditLog(ctx context.Context, teamName string, limit int) (*dto.TeamAuditLogResponse, error)
+	SyncTeam(ctx context.Context, req *dto.TeamSyncRequest) (*dto.TeamSyncDiff, error)
+	SetParent(ctx context.Context, req *dto.SetTeamParentRequest) (*dto.TeamTreeNode, error)
+	GetTree(ctx context.Context, teamName string) (*dto.TeamTreeResponse, error)
 }
 
 type PRService interface {
 	CreatePR(ctx context.Context, req *dto.CreatePRRequest) (*dto.CreatePRResponse, error)
+	PreviewReviewers(ctx context.Context, req *dto.PreviewReviewersRequest) (*dto.PreviewReviewersResponse, error)
 	ReassignReviewer(ctx context.Context, req *dto.ReassignReviewerRequest) (*dto.ReassignReviewerResponse, error)
+	ReassignReviewerTx(ctx context.Context, tx *gorm.DB, req *dto.ReassignReviewerRequest) (*dto.ReassignReviewerResponse, func(context.Context), error)
 	MergePR(ctx context.Context, req *dto.MergePRRequest) (*dto.MergePRResponse, error)
+	DeletePR(ctx context.Context, req *dto.DeletePRRequest) error
+	AddReviewer(ctx context.Context, req *dto.AddReviewerRequest) (*dto.ReviewerChangeResponse, error)
+	RemoveReviewer(ctx context.Context, req *dto.RemoveReviewerRequest) (*dto.ReviewerChangeResponse, error)
+	SetReviewerPinned(ctx context.Context, req *dto.PinReviewerRequest, pinned bool) (*dto.ReviewerChangeResponse, error)
+	GetLabels(ctx context.Context, prID string) (*dto.PRLabels, error)
+	AddLabels(ctx context.Context, req *dto.PRLabels) (*dto.PRLabels, error)
+	RemoveLabels(ctx context.Context, req *dto.PRLabels) (*dto.PRLabels, error)
 }
 
 type StatsService interface {
 	GetReviewerStats(ctx context.Context) (*dto.ReviewerStatsResponse, error)
+	GetTeamStats(ctx context.Context, teamName string) (*dto.TeamStatsResponse, error)
+}
+
+type NotificationService interface {
+	SendReviewDigests(ctx context.Context) error
+	SendEmailDigests(ctx context.Context) error
+}
+
+type EscalationService interface {
+	EscalateStaleReviews(ctx context.Context) (*dto.EscalationReport, error)
+}
+
+type ImportService interface {
+	ImportPullRequests(ctx context.Context, format string, data io.Reader, dryRun bool) (*dto.ImportReport, error)
+}
+
+type ExportService interface {
+	Export(ctx context.Context, entity, format string, w io.Writer) error
+	Snapshot(ctx context.Context, w io.Writer) (*dto.SnapshotManifest, error)
+	Restore(ctx context.Context, r io.Reader) (*dto.RestoreReport, error)
+}
+
+type RetentionService interface {
+	ListDeleted(ctx context.Context, entity string, limit int) (*dto.DeletedRecordsResponse, error)
+	Restore(ctx context.Context, req *dto.RestoreDeletedRequest) (*dto.DeletedRecord, error)
+	PurgeExpired(ctx context.Context) (*dto.RetentionReport, error)
+}
+
+type TokenService interface {
+	CreateToken(ctx context.Context, name string, ttl time.Duration) (*dto.CreatedToken, error)
+	RevokeToken(ctx context.Context, name string) error
+	Authenticate(ctx context.Context, token string) error
 }
@@ -4,16 +4,20 @@ import (
 	"context"
 	"log"
 
+	"github.com/google/uuid"
+
 	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/models"
 	"github.com/mink0ff/pr_service/internal/repository"
 )
 
 type StatsServiceImpl struct {
 	historyRepo repository.ReviewerHistoryRepository
+	teamRepo    repository.TeamRepository
 }
 
-func NewStatsService(historyRepo repository.ReviewerHistoryRepository) StatsService {
-	return &StatsServiceImpl{historyRepo: historyRepo}
+func NewStatsService(historyRepo repository.ReviewerHistoryRepository, teamRepo repository.TeamRepository) StatsService {
+	return &StatsServiceImpl{historyRepo: historyRepo, teamRepo: teamRepo}
 }
 
 func (s *StatsServiceImpl) GetReviewerStats(ctx context.Context) (*dto.ReviewerStatsResponse, error) {
@@ -27,3 +31,74 @@ func (s *StatsServiceImpl) GetReviewerStats(ctx context.Context) (*dto.ReviewerS
 		Items: items,
 	}, nil
 }
+
+// GetTeamStats returns per-team activity rolled up the team hierarchy: the
+// subtree of teamName, or every top-level team when teamName is empty.
+func (s *StatsServiceImpl) GetTeamStats(ctx context.Context, teamName string) (*dto.TeamStatsResponse, error) {
+	var (
+		teams []models.Team
+		err   error
+	)
+	if teamName == "" {
+		teams, err = s.teamRepo.List(ctx)
+	} else {
+		var team *models.Team
+		team, err = s.teamRepo.GetByName(ctx, teamName)
+		if err != nil {
+			return nil, err
+		}
+		if team == nil {
+			return nil, ErrTeamNotFound
+		}
+		teams, err = s.teamRepo.ListSubtree(ctx, team.TeamID)
+	}
+	if err != nil {
+		log.Printf("Failed to list teams for stats: %v", err)
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, len(teams))
+	for i, t := range teams {
+		ids[i] = t.TeamID
+	}
+	activity, err := s.teamRepo.CountTeamActivity(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	own := make(map[uuid.UUID]dto.TeamStatsCounts, len(activity))
+	for _, a := range activity {
+		own[a.TeamID] = dto.TeamStatsCounts{
+			Members:       a.Members,
+			ActiveMembers: a.ActiveMembers,
+			Assignments:   a.Assignments,
+			OpenReviews:   a.OpenReviews,
+		}
+	}
+
+	roots, children := groupByParent(teams)
+
+	var build func(t models.Team) dto.TeamStatsNode
+	build = func(t models.Team) dto.TeamStatsNode {
+		node := dto.TeamStatsNode{
+			TeamName: t.TeamName,
+			Own:      own[t.TeamID],
+			Total:    own[t.TeamID],
+			Children: []dto.TeamStatsNode{},
+		}
+		for _, c := range children[t.TeamID] {
+			child := build(c)
+			node.Total.Members += child.Total.Members
+			node.Total.ActiveMembers += child.Total.ActiveMembers
+			node.Total.Assignments += child.Total.Assignments
+			node.Total.OpenReviews += child.Total.OpenReviews
+			node.Children = append(node.Children, child)
+		}
+		return node
+	}
+
+	resp := &dto.TeamStatsResponse{Teams: []dto.TeamStatsNode{}}
+	for _, r := range roots {
+		resp.Teams = append(resp.Teams, build(r))
+	}
+	return resp, nil
+}
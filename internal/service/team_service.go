@@ -2,9 +2,16 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/mink0ff/pr_service/internal/codeowners"
 	"github.com/mink0ff/pr_service/internal/dto"
 	"github.com/mink0ff/pr_service/internal/models"
 	"github.com/mink0ff/pr_service/internal/repository"
@@ -13,14 +20,18 @@ import (
 )
 
 type TeamServiceImpl struct {
-	teamRepo  repository.TeamRepository
-	userRepo  repository.UserRepository
-	prRepo    repository.PullRequestRepository
-	txManager *transaction.Manager
+	teamRepo      repository.TeamRepository
+	userRepo      repository.UserRepository
+	prRepo        repository.PullRequestRepository
+	settingsRepo  repository.TeamSettingsRepository
+	ownershipRepo repository.OwnershipRuleRepository
+	auditRepo     repository.TeamAuditRepository
+	prService     PRService
+	txManager     *transaction.Manager
 }
 
-func NewTeamService(teamRepo repository.TeamRepository, userRepo repository.UserRepository, prRepo repository.PullRequestRepository, manager *transaction.Manager) TeamService {
-	return &TeamServiceImpl{teamRepo: teamRepo, userRepo: userRepo, prRepo: prRepo, txManager: manager}
+func NewTeamService(teamRepo repository.TeamRepository, userRepo repository.UserRepository, prRepo repository.PullRequestRepository, settingsRepo repository.TeamSettingsRepository, ownershipRepo repository.OwnershipRuleRepository, auditRepo repository.TeamAuditRepository, prService PRService, manager *transaction.Manager) TeamService {
+	return &TeamServiceImpl{teamRepo: teamRepo, userRepo: userRepo, prRepo: prRepo, settingsRepo: settingsRepo, ownershipRepo: ownershipRepo, auditRepo: auditRepo, prService: prService, txManager: manager}
 }
 
 func (s *TeamServiceImpl) CreateTeam(ctx context.Context, req *dto.CreateTeamRequest) (*dto.CreateTeamResponse, error) {
@@ -36,11 +47,17 @@ func (s *TeamServiceImpl) CreateTeam(ctx context.Context, req *dto.CreateTeamReq
 			return err
 		}
 
-		if err := s.createOrUpdateMembers(txCtx, team.TeamID, req.Members, txUserRepo); err != nil {
+		events, err := s.createOrUpdateMembers(txCtx, team, req.Members, txUserRepo, txTeamRepo)
+		if err != nil {
 			log.Printf("Failed to create/update members for team %s: %v", req.TeamName, err)
 			return err
 		}
 
+		events = append([]models.TeamAuditEvent{auditEvent(team, models.AuditTeamCreated, "", "", "")}, events...)
+		if err := s.auditRepo.WithTx(tx).Add(txCtx, events...); err != nil {
+			return err
+		}
+
 		resp = &dto.CreateTeamResponse{
 			Team: dto.Team{
 				TeamName: team.TeamName,
@@ -63,7 +80,10 @@ func (s *TeamServiceImpl) CreateTeam(ctx context.Context, req *dto.CreateTeamReq
 func (s *TeamServiceImpl) GetTeam(ctx context.Context, teamName string) (*dto.Team, error) {
 
 	team, err := s.teamRepo.GetByName(ctx, teamName)
-	if err != nil || team == nil {
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
 		log.Printf("Team not found: teamName=%s", teamName)
 		return nil, ErrTeamNotFound
 	}
@@ -74,12 +94,23 @@ func (s *TeamServiceImpl) GetTeam(ctx context.Context, teamName string) (*dto.Te
 		return nil, err
 	}
 
+	primaryNames := map[uuid.UUID]string{team.TeamID: ""}
 	members := make([]dto.TeamMember, len(users))
 	for i, u := range users {
+		if _, ok := primaryNames[u.TeamID]; !ok {
+			primary, err := s.teamRepo.GetByID(ctx, u.TeamID)
+			if err != nil {
+				return nil, err
+			}
+			if primary != nil {
+				primaryNames[u.TeamID] = primary.TeamName
+			}
+		}
 		members[i] = dto.TeamMember{
-			UserID:   u.UserID,
-			Username: u.Username,
-			IsActive: u.IsActive,
+			UserID:      u.UserID,
+			Username:    u.Username,
+			IsActive:    u.IsActive,
+			PrimaryTeam: primaryNames[u.TeamID],
 		}
 	}
 
@@ -109,45 +140,71 @@ func (s *TeamServiceImpl) createTeam(
 		TeamName: req.TeamName,
 	}
 
+	// A concurrent request may have created the team since the lookup.
 	if err := teamRepo.Create(ctx, *team); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, ErrTeamExists.Wrap(err)
+		}
 		return nil, err
 	}
 
 	return team, nil
 }
 
+// createOrUpdateMembers returns the audit events for the members it added
+// or took over from other teams.
 func (s *TeamServiceImpl) createOrUpdateMembers(
 	ctx context.Context,
-	teamID uuid.UUID,
+	team *models.Team,
 	members []dto.TeamMember,
 	userRepo repository.UserRepository,
-) error {
+	teamRepo repository.TeamRepository,
+) ([]models.TeamAuditEvent, error) {
 
+	var events []models.TeamAuditEvent
 	for _, m := range members {
 		existingUser, err := userRepo.GetByID(ctx, m.UserID)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		user := models.User{
 			UserID:   m.UserID,
 			Username: m.Username,
-			TeamID:   teamID,
+			TeamID:   team.TeamID,
 			IsActive: m.IsActive,
 		}
 
 		if existingUser == nil {
 			if err := userRepo.Create(ctx, user); err != nil {
-				return err
+				if errors.Is(err, repository.ErrDuplicate) {
+					// A deleted user keeps their ID until restored or purged.
+					return nil, ErrUserExists.Wrap(err).WithDetails("user_id", m.UserID)
+				}
+				return nil, err
 			}
-		} else {
-			if err := userRepo.Update(ctx, user); err != nil {
-				return err
+			events = append(events, auditEvent(team, models.AuditMemberAdded, m.UserID, "", ""))
+			continue
+		}
+
+		user.MaxOpenReviews = existingUser.MaxOpenReviews
+		if err := userRepo.Update(ctx, user); err != nil {
+			return nil, err
+		}
+
+		if existingUser.TeamID != team.TeamID {
+			oldTeam, err := teamRepo.GetByID(ctx, existingUser.TeamID)
+			if err != nil {
+				return nil, err
+			}
+			if oldTeam != nil {
+				events = append(events, auditEvent(oldTeam, models.AuditMemberMovedOut, m.UserID, "", team.TeamName))
+				events = append(events, auditEvent(team, models.AuditMemberMovedIn, m.UserID, oldTeam.TeamName, ""))
 			}
 		}
 	}
 
-	return nil
+	return events, nil
 }
 
 func (s *TeamServiceImpl) DeactivateTeamUsers(ctx context.Context, req *dto.DeactivateTeamUsersRequest) (*dto.DeactivateTeamUsersResponse, error) {
@@ -171,6 +228,17 @@ func (s *TeamServiceImpl) DeactivateTeamUsers(ctx context.Context, req *dto.Deac
 		}
 
 		for _, u := range users {
+			// Members from other teams only stop reviewing for this one.
+			if u.TeamID != team.TeamID {
+				if err := txUserRepo.SetMembershipActive(txCtx, u.UserID, team.TeamID, false); err != nil {
+					return err
+				}
+				if err := txPrRepo.RemoveReviewerFromTeamPRs(txCtx, u.UserID, team.TeamID); err != nil {
+					return err
+				}
+				continue
+			}
+
 			u.IsActive = false
 			if err := txUserRepo.Update(txCtx, u); err != nil {
 				return err
@@ -195,3 +263,1203 @@ func (s *TeamServiceImpl) DeactivateTeamUsers(ctx context.Context, req *dto.Deac
 
 	return resp, nil
 }
+
+func (s *TeamServiceImpl) SetWebhook(ctx context.Context, req *dto.SetTeamWebhookRequest) (*dto.SetTeamWebhookResponse, error) {
+	team, err := s.teamRepo.GetByName(ctx, req.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		log.Printf("Team not found: teamName=%s", req.TeamName)
+		return nil, ErrTeamNotFound
+	}
+
+	team.WebhookURL = nil
+	if req.WebhookURL != "" {
+		u, err := url.Parse(req.WebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, ErrInvalidWebhookURL
+		}
+		team.WebhookURL = &req.WebhookURL
+	}
+
+	if err := s.teamRepo.Update(ctx, *team); err != nil {
+		log.Printf("Failed to update webhook for team %s: %v", req.TeamName, err)
+		return nil, err
+	}
+
+	log.Printf("Team webhook updated: teamName=%s, configured=%v", team.TeamName, team.WebhookURL != nil)
+	return &dto.SetTeamWebhookResponse{
+		TeamName:          team.TeamName,
+		WebhookConfigured: team.WebhookURL != nil,
+	}, nil
+}
+
+func (s *TeamServiceImpl) GetSLA(ctx context.Context, teamName string) (*dto.TeamSLA, error) {
+	team, err := s.teamRepo.GetByName(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		log.Printf("Team not found: teamName=%s", teamName)
+		return nil, ErrTeamNotFound
+	}
+
+	settings, err := s.settingsRepo.Get(ctx, team.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.TeamSLA{TeamName: team.TeamName}
+	if settings != nil {
+		resp.RemindAfterHours = settings.SLARemindAfterHours
+		resp.ReassignAfterHours = settings.SLAReassignAfterHours
+	}
+	return resp, nil
+}
+
+func (s *TeamServiceImpl) SetSLA(ctx context.Context, req *dto.TeamSLA) (*dto.TeamSLA, error) {
+	if !validSLA(req.RemindAfterHours, req.ReassignAfterHours) {
+		return nil, ErrInvalidSLA
+	}
+
+	team, err := s.teamRepo.GetByName(ctx, req.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		log.Printf("Team not found: teamName=%s", req.TeamName)
+		return nil, ErrTeamNotFound
+	}
+
+	settings, err := s.settingsRepo.Get(ctx, team.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		settings = &models.TeamSettings{TeamID: team.TeamID}
+	}
+	settings.SLARemindAfterHours = req.RemindAfterHours
+	settings.SLAReassignAfterHours = req.ReassignAfterHours
+
+	if err := s.settingsRepo.Upsert(ctx, *settings); err != nil {
+		log.Printf("Failed to save SLA for team %s: %v", req.TeamName, err)
+		return nil, err
+	}
+
+	log.Printf("Team SLA updated: teamName=%s", team.TeamName)
+	return &dto.TeamSLA{
+		TeamName:           team.TeamName,
+		RemindAfterHours:   settings.SLARemindAfterHours,
+		ReassignAfterHours: settings.SLAReassignAfterHours,
+	}, nil
+}
+
+func (s *TeamServiceImpl) GetRotation(ctx context.Context, teamName string) (*dto.TeamRotation, error) {
+	team, err := s.teamRepo.GetByName(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		log.Printf("Team not found: teamName=%s", teamName)
+		return nil, ErrTeamNotFound
+	}
+
+	settings, err := s.settingsRepo.Get(ctx, team.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.TeamRotation{TeamName: team.TeamName}
+	if settings != nil {
+		resp.Window = settings.RotationWindow
+	}
+	return resp, nil
+}
+
+func (s *TeamServiceImpl) SetRotation(ctx context.Context, req *dto.TeamRotation) (*dto.TeamRotation, error) {
+	if req.Window != nil && (*req.Window < 1 || *req.Window > 50) {
+		return nil, ErrInvalidRotation
+	}
+
+	team, err := s.teamRepo.GetByName(ctx, req.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		log.Printf("Team not found: teamName=%s", req.TeamName)
+		return nil, ErrTeamNotFound
+	}
+
+	settings, err := s.settingsRepo.Get(ctx, team.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		settings = &models.TeamSettings{TeamID: team.TeamID}
+	}
+	settings.RotationWindow = req.Window
+
+	if err := s.settingsRepo.Upsert(ctx, *settings); err != nil {
+		log.Printf("Failed to save rotation window for team %s: %v", req.TeamName, err)
+		return nil, err
+	}
+
+	log.Printf("Team rotation updated: teamName=%s", team.TeamName)
+	return &dto.TeamRotation{TeamName: team.TeamName, Window: settings.RotationWindow}, nil
+}
+
+func validSLA(remind, reassign *int) bool {
+	if remind != nil && *remind <= 0 {
+		return false
+	}
+	if reassign != nil && *reassign <= 0 {
+		return false
+	}
+	if remind != nil && reassign != nil && *reassign <= *remind {
+		return false
+	}
+	return true
+}
+
+func (s *TeamServiceImpl) GetOwnershipRules(ctx context.Context, teamName string) (*dto.OwnershipRulesResponse, error) {
+	team, err := s.teamRepo.GetByName(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		log.Printf("Team not found: teamName=%s", teamName)
+		return nil, ErrTeamNotFound
+	}
+
+	rules, err := s.ownershipRepo.ListByTeam(ctx, team.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapOwnershipRulesToDTO(team.TeamName, rules), nil
+}
+
+func (s *TeamServiceImpl) ImportOwnershipRules(ctx context.Context, req *dto.ImportOwnershipRulesRequest) (*dto.OwnershipRulesResponse, error) {
+	parsed, err := codeowners.Parse(strings.NewReader(req.Content))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidOwnership, err)
+	}
+
+	team, err := s.teamRepo.GetByName(ctx, req.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		log.Printf("Team not found: teamName=%s", req.TeamName)
+		return nil, ErrTeamNotFound
+	}
+
+	now := time.Now()
+	rules := make([]models.OwnershipRule, len(parsed))
+	for i, rule := range parsed {
+		if err := s.checkOwnersExist(ctx, rule.Owners); err != nil {
+			return nil, fmt.Errorf("line %d: %w", rule.Line, err)
+		}
+		rules[i] = models.OwnershipRule{
+			RuleID:    uuid.New(),
+			TeamID:    team.TeamID,
+			Position:  i + 1,
+			Pattern:   rule.Pattern,
+			Owners:    strings.Join(rule.Owners, " "),
+			CreatedAt: now,
+		}
+	}
+
+	err = s.txManager.Do(ctx, func(txCtx context.Context, tx *gorm.DB) error {
+		return s.ownershipRepo.WithTx(tx).ReplaceForTeam(txCtx, team.TeamID, rules)
+	})
+	if err != nil {
+		log.Printf("Failed to import ownership rules for team %s: %v", req.TeamName, err)
+		return nil, err
+	}
+
+	log.Printf("Ownership rules imported: teamName=%s, rules=%d", team.TeamName, len(rules))
+	return mapOwnershipRulesToDTO(team.TeamName, rules), nil
+}
+
+func (s *TeamServiceImpl) AddOwnershipRule(ctx context.Context, req *dto.AddOwnershipRuleRequest) (*dto.OwnershipRule, error) {
+	if err := codeowners.ValidatePattern(req.Pattern); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidOwnership, err)
+	}
+	for _, owner := range req.Owners {
+		if err := codeowners.ValidateOwner(owner); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidOwnership, err)
+		}
+	}
+
+	team, err := s.teamRepo.GetByName(ctx, req.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		log.Printf("Team not found: teamName=%s", req.TeamName)
+		return nil, ErrTeamNotFound
+	}
+
+	if err := s.checkOwnersExist(ctx, req.Owners); err != nil {
+		return nil, err
+	}
+
+	rule := models.OwnershipRule{
+		RuleID:    uuid.New(),
+		TeamID:    team.TeamID,
+		Pattern:   req.Pattern,
+		Owners:    strings.Join(req.Owners, " "),
+		CreatedAt: time.Now(),
+	}
+	if err := s.ownershipRepo.Append(ctx, &rule); err != nil {
+		return nil, err
+	}
+
+	resp := mapOwnershipRuleToDTO(rule)
+	return &resp, nil
+}
+
+func (s *TeamServiceImpl) DeleteOwnershipRule(ctx context.Context, req *dto.DeleteOwnershipRuleRequest) (*dto.OwnershipRulesResponse, error) {
+	ruleID, err := uuid.Parse(req.RuleID)
+	if err != nil {
+		return nil, ErrRuleNotFound
+	}
+
+	team, err := s.teamRepo.GetByName(ctx, req.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		log.Printf("Team not found: teamName=%s", req.TeamName)
+		return nil, ErrTeamNotFound
+	}
+
+	deleted, err := s.ownershipRepo.Delete(ctx, team.TeamID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if !deleted {
+		return nil, ErrRuleNotFound
+	}
+
+	return s.GetOwnershipRules(ctx, team.TeamName)
+}
+
+// checkOwnersExist makes sure every @name refers to an existing user id or
+// team name, so typos are reported on import instead of silently never matching.
+func (s *TeamServiceImpl) checkOwnersExist(ctx context.Context, owners []string) error {
+	for _, owner := range owners {
+		name := codeowners.OwnerName(owner)
+
+		user, err := s.userRepo.GetByID(ctx, name)
+		if err != nil {
+			return err
+		}
+		if user != nil {
+			continue
+		}
+
+		team, err := s.teamRepo.GetByName(ctx, name)
+		if err != nil {
+			return err
+		}
+		if team == nil {
+			return ErrUnknownOwner.WithDetails("owner", owner)
+		}
+	}
+	return nil
+}
+
+func mapOwnershipRuleToDTO(rule models.OwnershipRule) dto.OwnershipRule {
+	owners := strings.Fields(rule.Owners)
+	if owners == nil {
+		owners = []string{}
+	}
+	return dto.OwnershipRule{
+		RuleID:   rule.RuleID.String(),
+		Position: rule.Position,
+		Pattern:  rule.Pattern,
+		Owners:   owners,
+	}
+}
+
+func mapOwnershipRulesToDTO(teamName string, rules []models.OwnershipRule) *dto.OwnershipRulesResponse {
+	resp := &dto.OwnershipRulesResponse{TeamName: teamName, Rules: make([]dto.OwnershipRule, len(rules))}
+	for i, rule := range rules {
+		resp.Rules[i] = mapOwnershipRuleToDTO(rule)
+	}
+	return resp
+}
+
+// AddMember creates a user in an existing team. Unlike /team/add it never
+// moves a user who already belongs to another team.
+func (s *TeamServiceImpl) AddMember(ctx context.Context, req *dto.AddTeamMemberRequest) (*dto.User, error) {
+	err := s.txManager.Do(ctx, func(txCtx context.Context, tx *gorm.DB) error {
+		txTeamRepo := s.teamRepo.WithTx(tx)
+		txUserRepo := s.userRepo.WithTx(tx)
+
+		team, err := txTeamRepo.GetByName(txCtx, req.TeamName)
+		if err != nil {
+			return err
+		}
+		if team == nil {
+			return ErrTeamNotFound
+		}
+
+		existing, err := txUserRepo.GetByID(txCtx, req.UserID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			if existing.TeamID == team.TeamID {
+				return ErrAlreadyInTeam
+			}
+			return ErrUserInOtherTeam
+		}
+
+		user := models.User{UserID: req.UserID, Username: req.Username, TeamID: team.TeamID, IsActive: req.IsActive}
+		if err := txUserRepo.Create(txCtx, user); err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				return ErrUserExists.Wrap(err)
+			}
+			return err
+		}
+
+		return s.auditRepo.WithTx(tx).Add(txCtx, auditEvent(team, models.AuditMemberAdded, req.UserID, "", ""))
+	})
+	if err != nil {
+		log.Printf("Failed to add member %s to team %s: %v", req.UserID, req.TeamName, err)
+		return nil, err
+	}
+
+	log.Printf("Member added: teamName=%s, userID=%s", req.TeamName, req.UserID)
+	return &dto.User{UserID: req.UserID, Username: req.Username, TeamName: req.TeamName, IsActive: req.IsActive}, nil
+}
+
+// MoveMember moves a user to another team. In the same transaction their
+// open reviews of the old team's pull requests are handed to that team
+// through the usual reassignment, because reviewers are chosen from the
+// author's team; reviews that cannot be reassigned stay with the user and are
+// reported as kept.
+func (s *TeamServiceImpl) MoveMember(ctx context.Context, req *dto.MoveTeamMemberRequest) (*dto.MoveTeamMemberResponse, error) {
+	var (
+		resp   *dto.MoveTeamMemberResponse
+		source *models.Team
+		notify []func(context.Context)
+	)
+
+	err := s.txManager.Do(ctx, func(txCtx context.Context, tx *gorm.DB) error {
+		txUserRepo := s.userRepo.WithTx(tx)
+		txTeamRepo := s.teamRepo.WithTx(tx)
+
+		// The user row is locked first so concurrent moves and review
+		// changes of the same user wait for this one.
+		user, err := txUserRepo.GetByID(txCtx, req.UserID)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return ErrUserNotFound
+		}
+
+		target, err := txTeamRepo.GetByName(txCtx, req.TeamName)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			return ErrTeamNotFound
+		}
+		if user.TeamID == target.TeamID {
+			return ErrAlreadyInTeam
+		}
+
+		source, err = txTeamRepo.GetByID(txCtx, user.TeamID)
+		if err != nil {
+			return err
+		}
+		if source == nil {
+			return ErrTeamNotFound
+		}
+
+		resp = &dto.MoveTeamMemberResponse{
+			FromTeam:    source.TeamName,
+			Reassigned:  []dto.ReassignedReview{},
+			KeptReviews: []string{},
+		}
+		notify, err = s.reassignOpenReviews(txCtx, tx, user.UserID, source, resp)
+		if err != nil {
+			return err
+		}
+
+		moved := *user
+		moved.TeamID = target.TeamID
+		if err := txUserRepo.Update(txCtx, moved); err != nil {
+			return err
+		}
+
+		events := []models.TeamAuditEvent{
+			auditEvent(source, models.AuditMemberMovedOut, user.UserID, "", target.TeamName),
+			auditEvent(target, models.AuditMemberMovedIn, user.UserID, source.TeamName, ""),
+		}
+		for _, r := range resp.Reassigned {
+			events = append(events, auditEvent(source, models.AuditReviewReassigned, user.UserID, r.PullRequestID, r.ReplacedBy))
+		}
+		if err := s.auditRepo.WithTx(tx).Add(txCtx, events...); err != nil {
+			return err
+		}
+
+		resp.User = dto.User{UserID: moved.UserID, Username: moved.Username, TeamName: target.TeamName, IsActive: moved.IsActive}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Failed to move user %s to team %s: %v", req.UserID, req.TeamName, err)
+		return nil, err
+	}
+
+	for _, n := range notify {
+		n(ctx)
+	}
+
+	log.Printf("Member moved: userID=%s, from=%s, to=%s, reassigned=%d, kept=%d",
+		req.UserID, source.TeamName, req.TeamName, len(resp.Reassigned), len(resp.KeptReviews))
+	return resp, nil
+}
+
+// reassignOpenReviews hands the user's open reviews of pull requests authored
+// in the source team to that team, in the move transaction; reviews of other
+// teams' pull requests stay with the user. It follows the escalation job: a
+// missing replacement or a pin keeps the review with the user and any other
+// error aborts the move; a review closed meanwhile is skipped. Each
+// reassignment runs in a savepoint so a skipped one leaves nothing behind.
+func (s *TeamServiceImpl) reassignOpenReviews(ctx context.Context, tx *gorm.DB, userID string, source *models.Team, resp *dto.MoveTeamMemberResponse) ([]func(context.Context), error) {
+	txUserRepo := s.userRepo.WithTx(tx)
+
+	prs, err := s.prRepo.WithTx(tx).ListByReviewer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var notify []func(context.Context)
+	for _, pr := range prs {
+		if pr.Status != models.PROpen {
+			continue
+		}
+
+		author, err := txUserRepo.GetByID(ctx, pr.AuthorID)
+		if err != nil {
+			return nil, err
+		}
+		if author == nil || author.TeamID != source.TeamID {
+			continue
+		}
+
+		err = tx.Transaction(func(sp *gorm.DB) error {
+			reassigned, n, err := s.prService.ReassignReviewerTx(ctx, sp, &dto.ReassignReviewerRequest{PullRequestID: pr.PullRequestID, OldUserID: userID})
+			if err != nil {
+				return err
+			}
+			resp.Reassigned = append(resp.Reassigned, dto.ReassignedReview{PullRequestID: pr.PullRequestID, ReplacedBy: reassigned.ReplacedBy})
+			notify = append(notify, n)
+			return nil
+		})
+		switch {
+		case err == nil:
+		case errors.Is(err, ErrNoCandidate), errors.Is(err, ErrReviewersAtCapacity), errors.Is(err, ErrReviewerPinned):
+			resp.KeptReviews = append(resp.KeptReviews, pr.PullRequestID)
+		case errors.Is(err, ErrReviewerNotAssigned), errors.Is(err, ErrPRMerged), errors.Is(err, ErrPRNotFound):
+			log.Printf("Review %s/%s closed during move: %v", pr.PullRequestID, userID, err)
+		default:
+			return nil, err
+		}
+	}
+	return notify, nil
+}
+
+// RemoveMember soft-deletes the user. They are deactivated and unassigned
+// like on deactivation; their pull requests and review history stay, and an
+// admin can restore them until the retention job purges them.
+func (s *TeamServiceImpl) RemoveMember(ctx context.Context, req *dto.RemoveTeamMemberRequest) error {
+	err := s.txManager.Do(ctx, func(txCtx context.Context, tx *gorm.DB) error {
+		txUserRepo := s.userRepo.WithTx(tx)
+
+		team, err := s.teamRepo.WithTx(tx).GetByName(txCtx, req.TeamName)
+		if err != nil {
+			return err
+		}
+		if team == nil {
+			return ErrTeamNotFound
+		}
+
+		user, err := txUserRepo.GetByID(txCtx, req.UserID)
+		if err != nil {
+			return err
+		}
+		if user == nil || user.TeamID != team.TeamID {
+			return ErrUserNotInTeam
+		}
+
+		user.IsActive = false
+		if err := txUserRepo.Update(txCtx, *user); err != nil {
+			return err
+		}
+		if err := s.prRepo.WithTx(tx).RemoveReviewerFromAllPRs(txCtx, user.UserID); err != nil {
+			return err
+		}
+
+		if err := txUserRepo.Delete(txCtx, user.UserID); err != nil {
+			return err
+		}
+
+		return s.auditRepo.WithTx(tx).Add(txCtx, auditEvent(team, models.AuditMemberRemoved, user.UserID, "", ""))
+	})
+	if err != nil {
+		log.Printf("Failed to remove member %s from team %s: %v", req.UserID, req.TeamName, err)
+		return err
+	}
+
+	log.Printf("Member removed: teamName=%s, userID=%s", req.TeamName, req.UserID)
+	return nil
+}
+
+// AddMembership makes an existing user a secondary member of the team, so they
+// are picked as a reviewer for it as well. For an existing secondary
+// membership only IsActive is changed; deactivating it unassigns the user from
+// the team's open pull requests.
+func (s *TeamServiceImpl) AddMembership(ctx context.Context, req *dto.TeamMembershipRequest) (*dto.UserMembershipsResponse, error) {
+	err := s.txManager.Do(ctx, func(txCtx context.Context, tx *gorm.DB) error {
+		txUserRepo := s.userRepo.WithTx(tx)
+
+		team, err := s.teamRepo.WithTx(tx).GetByName(txCtx, req.TeamName)
+		if err != nil {
+			return err
+		}
+		if team == nil {
+			return ErrTeamNotFound
+		}
+
+		user, err := txUserRepo.GetByID(txCtx, req.UserID)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return ErrUserNotFound
+		}
+		if user.TeamID == team.TeamID {
+			return ErrAlreadyInTeam
+		}
+
+		membership := models.TeamMembership{
+			UserID:    user.UserID,
+			TeamID:    team.TeamID,
+			IsActive:  req.IsActive,
+			CreatedAt: time.Now(),
+		}
+		if err := txUserRepo.AddMembership(txCtx, membership); err != nil {
+			return err
+		}
+		if !req.IsActive {
+			if err := s.prRepo.WithTx(tx).RemoveReviewerFromTeamPRs(txCtx, user.UserID, team.TeamID); err != nil {
+				return err
+			}
+		}
+
+		return s.auditRepo.WithTx(tx).Add(txCtx, auditEvent(team, models.AuditMembershipAdded, user.UserID, "", fmt.Sprintf("active=%v", req.IsActive)))
+	})
+	if err != nil {
+		log.Printf("Failed to add membership of %s in team %s: %v", req.UserID, req.TeamName, err)
+		return nil, err
+	}
+
+	log.Printf("Membership added: teamName=%s, userID=%s, active=%v", req.TeamName, req.UserID, req.IsActive)
+	return s.membershipsResponse(ctx, req.UserID)
+}
+
+// RemoveMembership takes a secondary member out of the team and off its open
+// pull requests, pinned reviews excepted.
+func (s *TeamServiceImpl) RemoveMembership(ctx context.Context, req *dto.RemoveMembershipRequest) (*dto.UserMembershipsResponse, error) {
+	err := s.txManager.Do(ctx, func(txCtx context.Context, tx *gorm.DB) error {
+		txUserRepo := s.userRepo.WithTx(tx)
+
+		team, err := s.teamRepo.WithTx(tx).GetByName(txCtx, req.TeamName)
+		if err != nil {
+			return err
+		}
+		if team == nil {
+			return ErrTeamNotFound
+		}
+
+		memberships, err := txUserRepo.ListMemberships(txCtx, req.UserID)
+		if err != nil {
+			return err
+		}
+		idx := slices.IndexFunc(memberships, func(m models.TeamMembership) bool { return m.TeamID == team.TeamID })
+		if idx < 0 {
+			return ErrUserNotInTeam
+		}
+		if memberships[idx].IsPrimary {
+			return ErrPrimaryMembership
+		}
+
+		if err := txUserRepo.RemoveMembership(txCtx, req.UserID, team.TeamID); err != nil {
+			return err
+		}
+		if err := s.prRepo.WithTx(tx).RemoveReviewerFromTeamPRs(txCtx, req.UserID, team.TeamID); err != nil {
+			return err
+		}
+
+		return s.auditRepo.WithTx(tx).Add(txCtx, auditEvent(team, models.AuditMembershipRemoved, req.UserID, "", ""))
+	})
+	if err != nil {
+		log.Printf("Failed to remove membership of %s in team %s: %v", req.UserID, req.TeamName, err)
+		return nil, err
+	}
+
+	log.Printf("Membership removed: teamName=%s, userID=%s", req.TeamName, req.UserID)
+	return s.membershipsResponse(ctx, req.UserID)
+}
+
+func (s *TeamServiceImpl) membershipsResponse(ctx context.Context, userID string) (*dto.UserMembershipsResponse, error) {
+	teams, err := listUserMemberships(ctx, userID, s.userRepo, s.teamRepo)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.UserMembershipsResponse{UserID: userID, Teams: teams}, nil
+}
+
+// listUserMemberships returns the user's teams, the primary one first.
+func listUserMemberships(ctx context.Context, userID string, userRepo repository.UserRepository, teamRepo repository.TeamRepository) ([]dto.UserMembership, error) {
+	memberships, err := userRepo.ListMemberships(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	teams := make([]dto.UserMembership, 0, len(memberships))
+	for _, m := range memberships {
+		team, err := teamRepo.GetByID(ctx, m.TeamID)
+		if err != nil {
+			return nil, err
+		}
+		if team == nil {
+			continue
+		}
+		teams = append(teams, dto.UserMembership{TeamName: team.TeamName, IsPrimary: m.IsPrimary, IsActive: m.IsActive})
+	}
+	return teams, nil
+}
+
+func (s *TeamServiceImpl) RenameTeam(ctx context.Context, req *dto.RenameTeamRequest) (*dto.Team, error) {
+	err := s.txManager.Do(ctx, func(txCtx context.Context, tx *gorm.DB) error {
+		txTeamRepo := s.teamRepo.WithTx(tx)
+
+		team, err := txTeamRepo.GetByName(txCtx, req.TeamName)
+		if err != nil {
+			return err
+		}
+		if team == nil {
+			return ErrTeamNotFound
+		}
+		if req.NewTeamName == team.TeamName {
+			return nil
+		}
+
+		renamed := *team
+		renamed.TeamName = req.NewTeamName
+		if err := txTeamRepo.Update(txCtx, renamed); err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				return ErrTeamExists.Wrap(err)
+			}
+			return err
+		}
+
+		return s.auditRepo.WithTx(tx).Add(txCtx, auditEvent(&renamed, models.AuditTeamRenamed, "", team.TeamName, renamed.TeamName))
+	})
+	if err != nil {
+		log.Printf("Failed to rename team %s: %v", req.TeamName, err)
+		return nil, err
+	}
+
+	log.Printf("Team renamed: from=%s, to=%s", req.TeamName, req.NewTeamName)
+	return s.GetTeam(ctx, req.NewTeamName)
+}
+
+// DeleteTeam soft-deletes a team without members or sub-teams. Its settings,
+// ownership rules and audit log are kept, so a restore brings the team back
+// as it was, and its name is free for a new team.
+func (s *TeamServiceImpl) DeleteTeam(ctx context.Context, req *dto.DeleteTeamRequest) error {
+	err := s.txManager.Do(ctx, func(txCtx context.Context, tx *gorm.DB) error {
+		txTeamRepo := s.teamRepo.WithTx(tx)
+
+		team, err := txTeamRepo.GetByName(txCtx, req.TeamName)
+		if err != nil {
+			return err
+		}
+		if team == nil {
+			return ErrTeamNotFound
+		}
+
+		members, err := txTeamRepo.ListUsersByTeam(txCtx, team.TeamID)
+		if err != nil {
+			return err
+		}
+		if len(members) > 0 {
+			return ErrTeamNotEmpty.WithDetails("members", len(members))
+		}
+
+		subtree, err := txTeamRepo.ListSubtree(txCtx, team.TeamID)
+		if err != nil {
+			return err
+		}
+		if len(subtree) > 1 {
+			return ErrTeamHasChildren
+		}
+
+		if err := txTeamRepo.Delete(txCtx, team.TeamID); err != nil {
+			return err
+		}
+
+		return s.auditRepo.WithTx(tx).Add(txCtx, auditEvent(team, models.AuditTeamDeleted, "", "", ""))
+	})
+	if err != nil {
+		log.Printf("Failed to delete team %s: %v", req.TeamName, err)
+		return err
+	}
+
+	log.Printf("Team deleted: teamName=%s", req.TeamName)
+	return nil
+}
+
+// SetParent moves a team under another one, or to the top level when the
+// parent name is empty. Moves that would put a team under itself or one of
+// its sub-teams are rejected.
+func (s *TeamServiceImpl) SetParent(ctx context.Context, req *dto.SetTeamParentRequest) (*dto.TeamTreeNode, error) {
+	err := s.txManager.Do(ctx, func(txCtx context.Context, tx *gorm.DB) error {
+		txTeamRepo := s.teamRepo.WithTx(tx)
+
+		if err := txTeamRepo.LockHierarchy(txCtx); err != nil {
+			return err
+		}
+
+		team, err := txTeamRepo.GetByName(txCtx, req.TeamName)
+		if err != nil {
+			return err
+		}
+		if team == nil {
+			return ErrTeamNotFound
+		}
+
+		var parent *models.Team
+		if req.ParentTeamName != "" {
+			parent, err = txTeamRepo.GetByName(txCtx, req.ParentTeamName)
+			if err != nil {
+				return err
+			}
+			if parent == nil {
+				return ErrTeamNotFound.WithDetails("team_name", req.ParentTeamName)
+			}
+			if parent.TeamID == team.TeamID {
+				return ErrTeamCycle
+			}
+
+			ancestors, err := txTeamRepo.ListAncestors(txCtx, parent.TeamID)
+			if err != nil {
+				return err
+			}
+			for _, a := range ancestors {
+				if a.TeamID == team.TeamID {
+					return ErrTeamCycle.WithDetails("parent_team_name", parent.TeamName)
+				}
+			}
+		}
+
+		oldParentName := ""
+		if team.ParentTeamID != nil {
+			oldParent, err := txTeamRepo.GetByID(txCtx, *team.ParentTeamID)
+			if err != nil {
+				return err
+			}
+			if oldParent != nil {
+				oldParentName = oldParent.TeamName
+			}
+		}
+		if oldParentName == req.ParentTeamName {
+			return nil
+		}
+
+		moved := *team
+		moved.ParentTeamID = nil
+		if parent != nil {
+			moved.ParentTeamID = &parent.TeamID
+		}
+		if err := txTeamRepo.Update(txCtx, moved); err != nil {
+			return err
+		}
+
+		return s.auditRepo.WithTx(tx).Add(txCtx, auditEvent(team, models.AuditTeamParentChanged, "", oldParentName, req.ParentTeamName))
+	})
+	if err != nil {
+		log.Printf("Failed to set parent of team %s: %v", req.TeamName, err)
+		return nil, err
+	}
+
+	log.Printf("Team parent set: team=%s, parent=%q", req.TeamName, req.ParentTeamName)
+
+	tree, err := s.GetTree(ctx, req.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	return &tree.Teams[0], nil
+}
+
+// GetTree returns the hierarchy below teamName, or the whole forest of teams
+// when teamName is empty.
+func (s *TeamServiceImpl) GetTree(ctx context.Context, teamName string) (*dto.TeamTreeResponse, error) {
+	var (
+		teams []models.Team
+		err   error
+	)
+	if teamName == "" {
+		teams, err = s.teamRepo.List(ctx)
+	} else {
+		var team *models.Team
+		team, err = s.teamRepo.GetByName(ctx, teamName)
+		if err != nil {
+			return nil, err
+		}
+		if team == nil {
+			return nil, ErrTeamNotFound
+		}
+		teams, err = s.teamRepo.ListSubtree(ctx, team.TeamID)
+	}
+	if err != nil {
+		log.Printf("Failed to list teams for tree: %v", err)
+		return nil, err
+	}
+
+	names := make(map[uuid.UUID]string, len(teams))
+	for _, t := range teams {
+		names[t.TeamID] = t.TeamName
+	}
+
+	roots, children := groupByParent(teams)
+
+	var build func(t models.Team, parentName string) (dto.TeamTreeNode, error)
+	build = func(t models.Team, parentName string) (dto.TeamTreeNode, error) {
+		members, err := s.teamRepo.ListUsersByTeam(ctx, t.TeamID)
+		if err != nil {
+			return dto.TeamTreeNode{}, err
+		}
+		node := dto.TeamTreeNode{
+			TeamName:       t.TeamName,
+			ParentTeamName: parentName,
+			Members:        len(members),
+			Children:       []dto.TeamTreeNode{},
+		}
+		for _, c := range children[t.TeamID] {
+			child, err := build(c, t.TeamName)
+			if err != nil {
+				return dto.TeamTreeNode{}, err
+			}
+			node.Children = append(node.Children, child)
+		}
+		return node, nil
+	}
+
+	resp := &dto.TeamTreeResponse{Teams: []dto.TeamTreeNode{}}
+	for _, r := range roots {
+		parentName := ""
+		if r.ParentTeamID != nil {
+			parentName = names[*r.ParentTeamID]
+			if parentName == "" {
+				parent, err := s.teamRepo.GetByID(ctx, *r.ParentTeamID)
+				if err != nil {
+					return nil, err
+				}
+				if parent != nil {
+					parentName = parent.TeamName
+				}
+			}
+		}
+		node, err := build(r, parentName)
+		if err != nil {
+			return nil, err
+		}
+		resp.Teams = append(resp.Teams, node)
+	}
+	return resp, nil
+}
+
+// groupByParent splits teams into roots, whose parent is not among teams,
+// and the children of every team, keeping the order of teams.
+func groupByParent(teams []models.Team) ([]models.Team, map[uuid.UUID][]models.Team) {
+	present := make(map[uuid.UUID]struct{}, len(teams))
+	for _, t := range teams {
+		present[t.TeamID] = struct{}{}
+	}
+
+	var roots []models.Team
+	children := map[uuid.UUID][]models.Team{}
+	for _, t := range teams {
+		if t.ParentTeamID != nil {
+			if _, ok := present[*t.ParentTeamID]; ok {
+				children[*t.ParentTeamID] = append(children[*t.ParentTeamID], t)
+				continue
+			}
+		}
+		roots = append(roots, t)
+	}
+	return roots, children
+}
+
+// SyncTeam makes the team match req: it creates the team if needed, adds
+// and updates the listed members, takes over members of other teams and
+// deactivates active members missing from the list. The diff is computed and
+// applied in one transaction. Deactivated members leave their reviews like in
+// DeactivateTeamUsers; moved members keep theirs, use MoveMember to hand them
+// over instead.
+func (s *TeamServiceImpl) SyncTeam(ctx context.Context, req *dto.TeamSyncRequest) (*dto.TeamSyncDiff, error) {
+	diff := &dto.TeamSyncDiff{
+		TeamName:    req.TeamName,
+		DryRun:      req.DryRun,
+		Added:       []dto.TeamMember{},
+		Updated:     []dto.TeamMemberUpdate{},
+		Moved:       []dto.TeamMemberMove{},
+		Deactivated: []string{},
+	}
+
+	err := s.txManager.Do(ctx, func(txCtx context.Context, tx *gorm.DB) error {
+		txTeamRepo := s.teamRepo.WithTx(tx)
+		txUserRepo := s.userRepo.WithTx(tx)
+
+		team, err := txTeamRepo.GetByName(txCtx, req.TeamName)
+		if err != nil {
+			return err
+		}
+
+		var current []models.User
+		if team == nil {
+			diff.TeamCreated = true
+			team = &models.Team{TeamID: uuid.New(), TeamName: req.TeamName}
+		} else {
+			members, err := txTeamRepo.ListUsersByTeam(txCtx, team.TeamID)
+			if err != nil {
+				return err
+			}
+			// The synced list is the team's own roster; secondary members
+			// are managed through memberships and left alone.
+			for _, u := range members {
+				if u.TeamID == team.TeamID {
+					current = append(current, u)
+				}
+			}
+		}
+
+		plan, err := s.planSync(txCtx, team, current, req.Members, diff, txUserRepo, txTeamRepo)
+		if err != nil {
+			return err
+		}
+		if req.DryRun {
+			return nil
+		}
+
+		return s.applySync(txCtx, tx, team, plan, diff)
+	})
+	if err != nil {
+		log.Printf("Failed to sync team %s: %v", req.TeamName, err)
+		return nil, err
+	}
+
+	log.Printf("Team synced: teamName=%s, dryRun=%v, added=%d, updated=%d, moved=%d, deactivated=%d",
+		diff.TeamName, diff.DryRun, len(diff.Added), len(diff.Updated), len(diff.Moved), len(diff.Deactivated))
+	return diff, nil
+}
+
+// syncPlan holds the rows to write for a diff.
+type syncPlan struct {
+	create     []models.User
+	update     []models.User
+	deactivate []models.User
+	events     []models.TeamAuditEvent
+}
+
+func (s *TeamServiceImpl) planSync(
+	ctx context.Context,
+	team *models.Team,
+	current []models.User,
+	members []dto.TeamMember,
+	diff *dto.TeamSyncDiff,
+	userRepo repository.UserRepository,
+	teamRepo repository.TeamRepository,
+) (*syncPlan, error) {
+
+	plan := &syncPlan{}
+	if diff.TeamCreated {
+		plan.events = append(plan.events, auditEvent(team, models.AuditTeamCreated, "", "", ""))
+	}
+
+	byID := make(map[string]models.User, len(current))
+	for _, u := range current {
+		byID[u.UserID] = u
+	}
+
+	desired := make(map[string]bool, len(members))
+	for _, m := range members {
+		desired[m.UserID] = true
+
+		if u, ok := byID[m.UserID]; ok {
+			if u.Username == m.Username && u.IsActive == m.IsActive {
+				diff.Unchanged++
+				continue
+			}
+			diff.Updated = append(diff.Updated, dto.TeamMemberUpdate{
+				UserID: u.UserID,
+				Before: dto.TeamMember{UserID: u.UserID, Username: u.Username, IsActive: u.IsActive},
+				After:  m,
+			})
+			u.Username, u.IsActive = m.Username, m.IsActive
+			plan.update = append(plan.update, u)
+			plan.events = append(plan.events, auditEvent(team, models.AuditMemberUpdated, u.UserID, "", ""))
+			continue
+		}
+
+		existing, err := userRepo.GetByID(ctx, m.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			diff.Added = append(diff.Added, m)
+			plan.create = append(plan.create, models.User{UserID: m.UserID, Username: m.Username, TeamID: team.TeamID, IsActive: m.IsActive})
+			plan.events = append(plan.events, auditEvent(team, models.AuditMemberAdded, m.UserID, "", ""))
+			continue
+		}
+
+		from, err := teamRepo.GetByID(ctx, existing.TeamID)
+		if err != nil {
+			return nil, err
+		}
+		if from == nil {
+			return nil, ErrTeamNotFound
+		}
+		diff.Moved = append(diff.Moved, dto.TeamMemberMove{TeamMember: m, FromTeam: from.TeamName})
+		moved := *existing
+		moved.Username, moved.IsActive, moved.TeamID = m.Username, m.IsActive, team.TeamID
+		plan.update = append(plan.update, moved)
+		plan.events = append(plan.events,
+			auditEvent(from, models.AuditMemberMovedOut, m.UserID, "", team.TeamName),
+			auditEvent(team, models.AuditMemberMovedIn, m.UserID, from.TeamName, ""))
+	}
+
+	for _, u := range current {
+		if desired[u.UserID] {
+			continue
+		}
+		if !u.IsActive {
+			diff.Unchanged++
+			continue
+		}
+		diff.Deactivated = append(diff.Deactivated, u.UserID)
+		u.IsActive = false
+		plan.deactivate = append(plan.deactivate, u)
+		plan.events = append(plan.events, auditEvent(team, models.AuditMemberDeactivated, u.UserID, "", ""))
+	}
+
+	slices.Sort(diff.Deactivated)
+	return plan, nil
+}
+
+func (s *TeamServiceImpl) applySync(ctx context.Context, tx *gorm.DB, team *models.Team, plan *syncPlan, diff *dto.TeamSyncDiff) error {
+	txTeamRepo := s.teamRepo.WithTx(tx)
+	txUserRepo := s.userRepo.WithTx(tx)
+	txPrRepo := s.prRepo.WithTx(tx)
+
+	if diff.TeamCreated {
+		if err := txTeamRepo.Create(ctx, *team); err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				return ErrTeamExists.Wrap(err)
+			}
+			return err
+		}
+	}
+
+	for _, u := range plan.create {
+		if err := txUserRepo.Create(ctx, u); err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				return ErrUserExists.Wrap(err)
+			}
+			return err
+		}
+	}
+
+	for _, u := range plan.update {
+		if err := txUserRepo.Update(ctx, u); err != nil {
+			return err
+		}
+	}
+
+	for _, u := range plan.deactivate {
+		if err := txUserRepo.Update(ctx, u); err != nil {
+			return err
+		}
+		if err := txPrRepo.RemoveReviewerFromAllPRs(ctx, u.UserID); err != nil {
+			return err
+		}
+	}
+
+	return s.auditRepo.WithTx(tx).Add(ctx, plan.events...)
+}
+
+func (s *TeamServiceImpl) GetAuditLog(ctx context.Context, teamName string, limit int) (*dto.TeamAuditLogResponse, error) {
+	team, err := s.teamRepo.GetByName(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		return nil, ErrTeamNotFound
+	}
+
+	events, err := s.auditRepo.ListByTeam(ctx, team.TeamID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.TeamAuditLogResponse{TeamName: team.TeamName, Events: make([]dto.TeamAuditEvent, len(events))}
+	for i, e := range events {
+		resp.Events[i] = dto.TeamAuditEvent{
+			Action:    string(e.Action),
+			TeamName:  e.TeamName,
+			UserID:    deref(e.UserID),
+			OldValue:  deref(e.OldValue),
+			NewValue:  deref(e.NewValue),
+			CreatedAt: e.CreatedAt,
+		}
+	}
+	return resp, nil
+}
+
+// auditEvent builds an event for team; empty strings are stored as NULL.
+func auditEvent(team *models.Team, action models.TeamAuditAction, userID, oldValue, newValue string) models.TeamAuditEvent {
+	optional := func(v string) *string {
+		if v == "" {
+			return nil
+		}
+		return &v
+	}
+	return models.TeamAuditEvent{
+		EventID:   uuid.New(),
+		TeamID:    team.TeamID,
+		TeamName:  team.TeamName,
+		Action:    action,
+		UserID:    optional(userID),
+		OldValue:  optional(oldValue),
+		NewValue:  optional(newValue),
+		CreatedAt: time.Now(),
+	}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
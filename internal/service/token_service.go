@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/models"
+	"github.com/mink0ff/pr_service/internal/repository"
+)
+
+const tokenPrefix = "prs_"
+
+type TokenServiceImpl struct {
+	tokenRepo repository.TokenRepository
+}
+
+func NewTokenService(tokenRepo repository.TokenRepository) TokenService {
+	return &TokenServiceImpl{tokenRepo: tokenRepo}
+}
+
+// CreateToken returns the plain token once; only its hash is stored. A zero
+// ttl creates a token that never expires.
+func (s *TokenServiceImpl) CreateToken(ctx context.Context, name string, ttl time.Duration) (*dto.CreatedToken, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || ttl < 0 {
+		return nil, ErrInvalidToken
+	}
+
+	existing, err := s.tokenRepo.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrTokenExists
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	plain := tokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	token := models.APIToken{
+		TokenID:   uuid.New(),
+		Name:      name,
+		TokenHash: hashToken(plain),
+		CreatedAt: time.Now().UTC(),
+	}
+	if ttl > 0 {
+		expiresAt := token.CreatedAt.Add(ttl)
+		token.ExpiresAt = &expiresAt
+	}
+
+	if err := s.tokenRepo.Create(ctx, token); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, ErrTokenExists.Wrap(err)
+		}
+		return nil, err
+	}
+
+	log.Printf("API token %s created", name)
+	return &dto.CreatedToken{Name: token.Name, Token: plain, CreatedAt: token.CreatedAt, ExpiresAt: token.ExpiresAt}, nil
+}
+
+func (s *TokenServiceImpl) RevokeToken(ctx context.Context, name string) error {
+	deleted, err := s.tokenRepo.Delete(ctx, name)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrTokenNotFound
+	}
+
+	log.Printf("API token %s revoked", name)
+	return nil
+}
+
+func (s *TokenServiceImpl) Authenticate(ctx context.Context, plain string) error {
+	if !strings.HasPrefix(plain, tokenPrefix) {
+		return ErrUnauthorized
+	}
+
+	token, err := s.tokenRepo.GetByHash(ctx, hashToken(plain))
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if token == nil || (token.ExpiresAt != nil && now.After(*token.ExpiresAt)) {
+		return ErrUnauthorized
+	}
+
+	if err := s.tokenRepo.MarkUsed(ctx, token.TokenID.String(), now); err != nil {
+		log.Printf("Failed to record use of API token %s: %v", token.Name, err)
+	}
+	return nil
+}
+
+func hashToken(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
@@ -2,40 +2,58 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"net/mail"
+	"regexp"
+	"slices"
+	"strings"
 
+	"github.com/google/uuid"
 	"github.com/mink0ff/pr_service/internal/dto"
 	"github.com/mink0ff/pr_service/internal/models"
 	"github.com/mink0ff/pr_service/internal/repository"
 )
 
 type UserServiceImpl struct {
-	userRepo repository.UserRepository
-	teamRepo repository.TeamRepository
+	userRepo     repository.UserRepository
+	teamRepo     repository.TeamRepository
+	settingsRepo repository.NotificationSettingsRepository
+	tagRepo      repository.TagRepository
 }
 
-func NewUserService(userRepo repository.UserRepository, teamRepo repository.TeamRepository) UserService {
-	return &UserServiceImpl{userRepo: userRepo, teamRepo: teamRepo}
+func NewUserService(userRepo repository.UserRepository, teamRepo repository.TeamRepository, settingsRepo repository.NotificationSettingsRepository, tagRepo repository.TagRepository) UserService {
+	return &UserServiceImpl{userRepo: userRepo, teamRepo: teamRepo, settingsRepo: settingsRepo, tagRepo: tagRepo}
 }
 
 func (s *UserServiceImpl) CreateUser(ctx context.Context, req *dto.CreateUserRequest) (*dto.User, error) {
+	team, err := s.teamRepo.GetByName(ctx, req.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		log.Printf("Team not found: teamName=%s", req.TeamName)
+		return nil, ErrTeamNotFound
+	}
+
 	user := models.User{
 		UserID:   req.UserID,
 		Username: req.Name,
-		TeamID:   req.TeamID,
+		TeamID:   team.TeamID,
 		IsActive: req.IsActive,
 	}
 
-	err := s.userRepo.Create(ctx, user)
-	if err != nil {
+	err = s.userRepo.Create(ctx, user)
+	switch {
+	case errors.Is(err, repository.ErrDuplicate):
+		return nil, ErrUserExists.Wrap(err)
+	case errors.Is(err, repository.ErrForeignKey):
+		// The team was deleted after the lookup.
+		return nil, ErrTeamNotFound.Wrap(err)
+	case err != nil:
 		log.Printf("Failed to create user %s: %v", req.UserID, err)
-		return nil, ErrUserExists
-	}
-
-	team, err := s.teamRepo.GetByID(ctx, user.TeamID)
-	if err != nil {
-		log.Printf("Failed to get team %s for user %s: %v", user.TeamID, user.UserID, err)
-		return nil, ErrTeamNotFound
+		return nil, fmt.Errorf("create user %s: %w", req.UserID, err)
 	}
 
 	dtoUser := dto.User{
@@ -49,20 +67,123 @@ func (s *UserServiceImpl) CreateUser(ctx context.Context, req *dto.CreateUserReq
 	return &dtoUser, nil
 }
 
+func (s *UserServiceImpl) GetUser(ctx context.Context, userID string) (*dto.UserDetails, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	team, err := s.teamRepo.GetByID(ctx, user.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		return nil, ErrTeamNotFound
+	}
+
+	teams, err := listUserMemberships(ctx, userID, s.userRepo, s.teamRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	openReviews, err := s.userRepo.CountOpenReviews(ctx, []string{userID})
+	if err != nil {
+		return nil, err
+	}
+
+	prs, err := s.userRepo.ListAuthoredPRs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	authored := make([]dto.PullRequestShortDTO, len(prs))
+	for i, pr := range prs {
+		authored[i] = dto.PullRequestShortDTO{
+			PullRequestID:   pr.PullRequestID,
+			PullRequestName: pr.PullRequestName,
+			AuthorID:        pr.AuthorID,
+			Status:          dto.PRStatus(pr.Status),
+		}
+	}
+
+	return &dto.UserDetails{
+		User: dto.User{
+			UserID:   user.UserID,
+			Username: user.Username,
+			TeamName: team.TeamName,
+			IsActive: user.IsActive,
+		},
+		Teams:       teams,
+		OpenReviews: openReviews[userID],
+		AuthoredPRs: authored,
+	}, nil
+}
+
+func (s *UserServiceImpl) ListUsers(ctx context.Context, req *dto.ListUsersRequest) (*dto.ListUsersResponse, error) {
+	filter := repository.UserFilter{IsActive: req.IsActive, Limit: req.Limit, Offset: req.Offset}
+
+	// Team names are unique, so one lookup turns the name filter into an ID
+	// filter and also gives the name for every user on the page.
+	teamNames := make(map[uuid.UUID]string)
+	if req.TeamName != "" {
+		team, err := s.teamRepo.GetByName(ctx, req.TeamName)
+		if err != nil {
+			return nil, err
+		}
+		if team == nil {
+			return nil, ErrTeamNotFound
+		}
+		filter.TeamID = &team.TeamID
+		teamNames[team.TeamID] = team.TeamName
+	} else {
+		teams, err := s.teamRepo.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, team := range teams {
+			teamNames[team.TeamID] = team.TeamName
+		}
+	}
+
+	users, total, err := s.userRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.ListUsersResponse{
+		Users:  make([]dto.User, len(users)),
+		Total:  total,
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	}
+	for i, user := range users {
+		resp.Users[i] = dto.User{
+			UserID:   user.UserID,
+			Username: user.Username,
+			TeamName: teamNames[user.TeamID],
+			IsActive: user.IsActive,
+		}
+	}
+
+	return resp, nil
+}
+
 func (s *UserServiceImpl) SetActive(ctx context.Context, req dto.SetUserActiveRequest) (*dto.User, error) {
 
 	user, err := s.userRepo.GetByID(ctx, req.UserID)
-	if err != nil || user == nil {
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
 		log.Printf("User not found: userID=%s", req.UserID)
 		return nil, ErrUserNotFound
 	}
 
-	userUpdate := models.User{
-		UserID:   user.UserID,
-		Username: user.Username,
-		TeamID:   user.TeamID,
-		IsActive: req.IsActive,
-	}
+	userUpdate := *user
+	userUpdate.IsActive = req.IsActive
 
 	if err := s.userRepo.Update(ctx, userUpdate); err != nil {
 		log.Printf("Failed to update user %s active status: %v", req.UserID, err)
@@ -72,6 +193,9 @@ func (s *UserServiceImpl) SetActive(ctx context.Context, req dto.SetUserActiveRe
 	team, err := s.teamRepo.GetByID(ctx, user.TeamID)
 	if err != nil {
 		log.Printf("Failed to get team %s for user %s: %v", user.TeamID, user.UserID, err)
+		return nil, err
+	}
+	if team == nil {
 		return nil, ErrTeamNotFound
 	}
 
@@ -89,3 +213,214 @@ func (s *UserServiceImpl) SetActive(ctx context.Context, req dto.SetUserActiveRe
 func (s *UserServiceImpl) GetReviewPRs(ctx context.Context, userID string) ([]models.PullRequest, error) {
 	return s.userRepo.ListReviewPRs(ctx, userID)
 }
+
+func (s *UserServiceImpl) GetNotificationSettings(ctx context.Context, userID string) (*dto.NotificationSettings, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		log.Printf("User not found: userID=%s", userID)
+		return nil, ErrUserNotFound
+	}
+
+	settings, err := s.settingsRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		return &dto.NotificationSettings{
+			UserID:          userID,
+			DigestFrequency: string(models.DigestDaily),
+		}, nil
+	}
+
+	return mapNotificationSettingsToDTO(settings), nil
+}
+
+func (s *UserServiceImpl) SetNotificationSettings(ctx context.Context, req *dto.NotificationSettings) (*dto.NotificationSettings, error) {
+	user, err := s.userRepo.GetByID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		log.Printf("User not found: userID=%s", req.UserID)
+		return nil, ErrUserNotFound
+	}
+
+	frequency := models.DigestFrequency(req.DigestFrequency)
+	switch frequency {
+	case "":
+		frequency = models.DigestDaily
+	case models.DigestDaily, models.DigestWeekly:
+	default:
+		return nil, ErrInvalidFrequency
+	}
+
+	settings := models.UserNotificationSettings{
+		UserID:          req.UserID,
+		EmailOptOut:     req.EmailOptOut,
+		DigestFrequency: frequency,
+	}
+	if req.Email != "" {
+		addr, err := mail.ParseAddress(req.Email)
+		if err != nil {
+			return nil, ErrInvalidEmail
+		}
+		settings.Email = &addr.Address
+	}
+
+	if err := s.settingsRepo.Upsert(ctx, settings); err != nil {
+		log.Printf("Failed to save notification settings for user %s: %v", req.UserID, err)
+		return nil, err
+	}
+
+	log.Printf("Notification settings updated: userID=%s, optOut=%v, frequency=%s", req.UserID, req.EmailOptOut, frequency)
+	return mapNotificationSettingsToDTO(&settings), nil
+}
+
+func mapNotificationSettingsToDTO(settings *models.UserNotificationSettings) *dto.NotificationSettings {
+	resp := &dto.NotificationSettings{
+		UserID:          settings.UserID,
+		EmailOptOut:     settings.EmailOptOut,
+		DigestFrequency: string(settings.DigestFrequency),
+	}
+	if settings.Email != nil {
+		resp.Email = *settings.Email
+	}
+	return resp
+}
+
+func (s *UserServiceImpl) GetSkills(ctx context.Context, userID string) (*dto.UserSkills, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		log.Printf("User not found: userID=%s", userID)
+		return nil, ErrUserNotFound
+	}
+
+	skills, err := s.tagRepo.ListUserSkills(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if skills == nil {
+		skills = []string{}
+	}
+
+	return &dto.UserSkills{UserID: userID, Skills: skills}, nil
+}
+
+func (s *UserServiceImpl) AddSkills(ctx context.Context, req *dto.UserSkills) (*dto.UserSkills, error) {
+	tags, err := normalizeTags(req.Skills)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		log.Printf("User not found: userID=%s", req.UserID)
+		return nil, ErrUserNotFound
+	}
+
+	if err := s.tagRepo.AddUserSkills(ctx, req.UserID, tags); err != nil {
+		return nil, err
+	}
+
+	return s.GetSkills(ctx, req.UserID)
+}
+
+func (s *UserServiceImpl) RemoveSkills(ctx context.Context, req *dto.UserSkills) (*dto.UserSkills, error) {
+	tags, err := normalizeTags(req.Skills)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		log.Printf("User not found: userID=%s", req.UserID)
+		return nil, ErrUserNotFound
+	}
+
+	if err := s.tagRepo.RemoveUserSkills(ctx, req.UserID, tags); err != nil {
+		return nil, err
+	}
+
+	return s.GetSkills(ctx, req.UserID)
+}
+
+func (s *UserServiceImpl) GetReviewLimit(ctx context.Context, userID string) (*dto.UserReviewLimit, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		log.Printf("User not found: userID=%s", userID)
+		return nil, ErrUserNotFound
+	}
+
+	return s.mapReviewLimit(ctx, user)
+}
+
+func (s *UserServiceImpl) SetReviewLimit(ctx context.Context, req *dto.UserReviewLimit) (*dto.UserReviewLimit, error) {
+	if req.MaxOpenReviews != nil && (*req.MaxOpenReviews < 0 || *req.MaxOpenReviews > 100) {
+		return nil, ErrInvalidReviewLimit
+	}
+
+	user, err := s.userRepo.GetByID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		log.Printf("User not found: userID=%s", req.UserID)
+		return nil, ErrUserNotFound
+	}
+
+	user.MaxOpenReviews = req.MaxOpenReviews
+	if err := s.userRepo.Update(ctx, *user); err != nil {
+		log.Printf("Failed to update review limit for user %s: %v", req.UserID, err)
+		return nil, err
+	}
+
+	log.Printf("Review limit updated: userID=%s, limited=%v", user.UserID, user.MaxOpenReviews != nil)
+	return s.mapReviewLimit(ctx, user)
+}
+
+func (s *UserServiceImpl) mapReviewLimit(ctx context.Context, user *models.User) (*dto.UserReviewLimit, error) {
+	open, err := s.userRepo.CountOpenReviews(ctx, []string{user.UserID})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.UserReviewLimit{
+		UserID:         user.UserID,
+		MaxOpenReviews: user.MaxOpenReviews,
+		OpenReviews:    open[user.UserID],
+	}, nil
+}
+
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9+#._-]{0,49}$`)
+
+// normalizeTags lowercases and deduplicates skill tags and PR labels so that
+// "Go" on a user matches "go" on a pull request.
+func normalizeTags(tags []string) ([]string, error) {
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !tagPattern.MatchString(tag) {
+			return nil, ErrInvalidTag
+		}
+		if !slices.Contains(result, tag) {
+			result = append(result, tag)
+		}
+	}
+	slices.Sort(result)
+	return result, nil
+}
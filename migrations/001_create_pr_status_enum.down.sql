@@ -0,0 +1 @@
+DROP TYPE IF EXISTS pr_status;
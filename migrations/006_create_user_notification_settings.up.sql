@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS user_notification_settings (
+    user_id             TEXT PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    email               TEXT,
+    email_opt_out       BOOLEAN NOT NULL DEFAULT FALSE,
+    digest_frequency    TEXT NOT NULL DEFAULT 'DAILY' CHECK (digest_frequency IN ('DAILY', 'WEEKLY')),
+    last_digest_sent_at TIMESTAMPTZ
+);
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS team_settings;
+
+ALTER TABLE reviewer_assignment_histories
+    DROP COLUMN IF EXISTS event_type;
+
+DROP INDEX IF EXISTS idx_pr_reviewers_assigned_at;
+
+ALTER TABLE pr_reviewers
+    DROP COLUMN IF EXISTS reminded_at,
+    DROP COLUMN IF EXISTS assigned_at;
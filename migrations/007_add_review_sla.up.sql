@@ -0,0 +1,15 @@
+ALTER TABLE pr_reviewers
+    ADD COLUMN IF NOT EXISTS assigned_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN IF NOT EXISTS reminded_at TIMESTAMPTZ;
+
+ALTER TABLE reviewer_assignment_histories
+    ADD COLUMN IF NOT EXISTS event_type TEXT NOT NULL DEFAULT 'ASSIGNED';
+
+CREATE TABLE IF NOT EXISTS team_settings (
+    team_id                  TEXT PRIMARY KEY REFERENCES teams(team_id) ON DELETE CASCADE,
+    sla_remind_after_hours   INTEGER CHECK (sla_remind_after_hours > 0),
+    sla_reassign_after_hours INTEGER CHECK (sla_reassign_after_hours > 0)
+);
+
+CREATE INDEX idx_pr_reviewers_assigned_at
+ON pr_reviewers (assigned_at);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS jobs (
+    name       TEXT PRIMARY KEY,
+    schedule   TEXT NOT NULL,
+    paused     BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS job_runs (
+    job_run_id  UUID PRIMARY KEY,
+    job_name    TEXT NOT NULL REFERENCES jobs(name) ON DELETE CASCADE,
+    trigger     TEXT NOT NULL CHECK (trigger IN ('SCHEDULE', 'MANUAL')),
+    status      TEXT NOT NULL CHECK (status IN ('RUNNING', 'SUCCEEDED', 'FAILED')),
+    error       TEXT,
+    instance    TEXT NOT NULL,
+    started_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_job_runs_job_name_started_at
+ON job_runs (job_name, started_at DESC);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS ownership_rules (
+    rule_id    UUID PRIMARY KEY,
+    team_id    TEXT NOT NULL REFERENCES teams(team_id) ON DELETE CASCADE,
+    position   INTEGER NOT NULL,
+    pattern    TEXT NOT NULL,
+    owners     TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    UNIQUE (team_id, position)
+);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS user_skills (
+    user_id TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    tag     TEXT NOT NULL,
+
+    PRIMARY KEY (user_id, tag)
+);
+
+CREATE INDEX idx_user_skills_tag
+ON user_skills (tag);
+
+CREATE TABLE IF NOT EXISTS pr_labels (
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    label           TEXT NOT NULL,
+
+    PRIMARY KEY (pull_request_id, label)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE team_settings
+    ADD COLUMN IF NOT EXISTS rotation_window INTEGER CHECK (rotation_window > 0);
@@ -0,0 +1,2 @@
+ALTER TABLE pr_reviewers
+    DROP COLUMN IF EXISTS pinned;
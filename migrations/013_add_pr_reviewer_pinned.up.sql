@@ -0,0 +1,2 @@
+ALTER TABLE pr_reviewers
+    ADD COLUMN IF NOT EXISTS pinned BOOLEAN NOT NULL DEFAULT FALSE;
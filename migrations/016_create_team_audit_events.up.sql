@@ -0,0 +1,13 @@
+-- No foreign key on team_id: the log must outlive deleted teams and users.
+CREATE TABLE IF NOT EXISTS team_audit_events (
+    event_id   UUID PRIMARY KEY,
+    team_id    TEXT NOT NULL,
+    team_name  TEXT NOT NULL,
+    action     TEXT NOT NULL,
+    user_id    TEXT,
+    old_value  TEXT,
+    new_value  TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_audit_events_team_created ON team_audit_events (team_id, created_at);
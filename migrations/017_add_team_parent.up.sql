@@ -0,0 +1,8 @@
+-- Teams form a forest. The service rejects cycles; the check below only
+-- covers the trivial one. The foreign key is deferred so a snapshot restore
+-- can insert teams in any order.
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS parent_team_id TEXT REFERENCES teams(team_id) DEFERRABLE INITIALLY DEFERRED,
+    ADD CONSTRAINT teams_parent_not_self CHECK (parent_team_id <> team_id);
+
+CREATE INDEX IF NOT EXISTS idx_teams_parent_team_id ON teams (parent_team_id);
@@ -0,0 +1,19 @@
+-- A user reviews for every team they are a member of. users.team_id stays
+-- the primary team and always has a membership with is_primary set; the
+-- primary membership's is_active mirrors users.is_active.
+CREATE TABLE IF NOT EXISTS team_memberships (
+    user_id    TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    team_id    TEXT NOT NULL REFERENCES teams(team_id) ON DELETE CASCADE,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    is_active  BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (user_id, team_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_team_memberships_primary ON team_memberships (user_id) WHERE is_primary;
+CREATE INDEX IF NOT EXISTS idx_team_memberships_team_id ON team_memberships (team_id);
+
+INSERT INTO team_memberships (user_id, team_id, is_primary, is_active)
+SELECT user_id, team_id, TRUE, is_active FROM users
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,19 @@
+DROP TABLE IF EXISTS archive.records;
+DROP SCHEMA IF EXISTS archive;
+
+DROP INDEX IF EXISTS idx_pull_requests_deleted_at;
+DROP INDEX IF EXISTS idx_users_deleted_at;
+DROP INDEX IF EXISTS idx_teams_deleted_at;
+
+-- Deleted rows become visible again; deleted teams are renamed first when
+-- a live team has taken their name.
+UPDATE teams t SET team_name = t.team_name || ' (deleted ' || t.team_id || ')'
+WHERE t.deleted_at IS NOT NULL
+  AND EXISTS (SELECT 1 FROM teams o WHERE o.team_name = t.team_name AND o.team_id <> t.team_id);
+
+DROP INDEX IF EXISTS idx_teams_team_name_live;
+ALTER TABLE teams ADD CONSTRAINT teams_team_name_key UNIQUE (team_name);
+
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE teams DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,27 @@
+-- Teams, users and pull requests are deleted softly so assignment history and
+-- stats survive. The retention job hard-deletes them later and can keep a
+-- copy in archive.records first.
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- A deleted team's name can be taken by a new team.
+ALTER TABLE teams DROP CONSTRAINT IF EXISTS teams_team_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_teams_team_name_live ON teams (team_name) WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_teams_deleted_at ON teams (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_pull_requests_deleted_at ON pull_requests (deleted_at) WHERE deleted_at IS NOT NULL;
+
+CREATE SCHEMA IF NOT EXISTS archive;
+
+CREATE TABLE IF NOT EXISTS archive.records (
+    archive_id  BIGSERIAL PRIMARY KEY,
+    entity      TEXT NOT NULL,
+    record_id   TEXT NOT NULL,
+    data        JSONB NOT NULL,
+    deleted_at  TIMESTAMPTZ NOT NULL,
+    archived_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_archive_records_entity_record ON archive.records (entity, record_id);
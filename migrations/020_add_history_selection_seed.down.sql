@@ -0,0 +1,2 @@
+ALTER TABLE reviewer_assignment_histories
+    DROP COLUMN IF EXISTS selection_seed;
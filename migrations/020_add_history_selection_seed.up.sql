@@ -0,0 +1,2 @@
+ALTER TABLE reviewer_assignment_histories
+    ADD COLUMN IF NOT EXISTS selection_seed BIGINT;
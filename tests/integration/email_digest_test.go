@@ -0,0 +1,88 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mink0ff/pr_service/internal/config"
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/mailer"
+	"github.com/mink0ff/pr_service/internal/notifier"
+	"github.com/mink0ff/pr_service/internal/repository"
+	"github.com/mink0ff/pr_service/internal/service"
+	"github.com/mink0ff/pr_service/tests/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// initEmailDigestTest создаёт команду backend из u1–u3 и сервис рассылки
+// через локальный SMTP-сервер.
+func initEmailDigestTest(t *testing.T) (context.Context, service.NotificationService, *utils.SMTPStub) {
+	ctx := utils.SeedBackendTeam(t, ts, utils.BackendMembers(3)...)
+
+	stub, err := utils.NewSMTPStub()
+	require.NoError(t, err)
+	t.Cleanup(stub.Close)
+
+	smtpMailer := mailer.NewSMTPMailer(&config.SMTPConfig{
+		Host:    "127.0.0.1",
+		Port:    stub.Addr().Port,
+		From:    "pr-service@example.com",
+		Timeout: time.Second,
+	})
+
+	notificationSvc := service.NewNotificationService(
+		repository.NewTeamRepo(ts.DB),
+		repository.NewUserRepo(ts.DB),
+		repository.NewNotificationSettingsRepo(ts.DB),
+		notifier.NewNoopNotifier(),
+		smtpMailer,
+	)
+
+	return ctx, notificationSvc, stub
+}
+
+func TestEmailDigest_SendsPendingReviews(t *testing.T) {
+	ctx, notificationSvc, stub := initEmailDigestTest(t)
+
+	_, err := ts.UserService.SetNotificationSettings(ctx, &dto.NotificationSettings{
+		UserID: "u2", Email: "bob@example.com", DigestFrequency: "DAILY",
+	})
+	require.NoError(t, err)
+
+	_, err = ts.UserService.SetNotificationSettings(ctx, &dto.NotificationSettings{
+		UserID: "u3", Email: "charlie@example.com", EmailOptOut: true,
+	})
+	require.NoError(t, err)
+
+	_, err = ts.PRService.CreatePR(ctx, &dto.CreatePRRequest{
+		PullRequestID:   "pr-1",
+		PullRequestName: "Add payments module",
+		AuthorID:        "u1",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, notificationSvc.SendEmailDigests(ctx))
+
+	messages := stub.Messages()
+	require.Len(t, messages, 1)
+	require.Equal(t, []string{"bob@example.com"}, messages[0].To)
+	require.Contains(t, messages[0].Data, "Add payments module")
+	require.Contains(t, messages[0].Data, "multipart/alternative")
+
+	require.NoError(t, notificationSvc.SendEmailDigests(ctx))
+	require.Len(t, stub.Messages(), 1)
+}
+
+func TestEmailDigest_InvalidSettings(t *testing.T) {
+	ctx, _, _ := initEmailDigestTest(t)
+
+	_, err := ts.UserService.SetNotificationSettings(ctx, &dto.NotificationSettings{UserID: "u1", Email: "nope"})
+	require.ErrorIs(t, err, service.ErrInvalidEmail)
+
+	_, err = ts.UserService.SetNotificationSettings(ctx, &dto.NotificationSettings{UserID: "u1", DigestFrequency: "HOURLY"})
+	require.ErrorIs(t, err, service.ErrInvalidFrequency)
+
+	_, err = ts.UserService.SetNotificationSettings(ctx, &dto.NotificationSettings{UserID: "u999"})
+	require.ErrorIs(t, err, service.ErrUserNotFound)
+}
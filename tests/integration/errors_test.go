@@ -0,0 +1,61 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/handler"
+	"github.com/mink0ff/pr_service/internal/repository"
+	"github.com/mink0ff/pr_service/internal/service"
+	"github.com/mink0ff/pr_service/tests/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrors_ConstraintViolations(t *testing.T) {
+	utils.TruncateTables(ts.DB)
+	ctx := context.Background()
+
+	_, err := ts.TeamService.CreateTeam(ctx, &dto.Team{
+		TeamName: "core",
+		Members:  []dto.TeamMember{{UserID: "e1", Username: "Alice", IsActive: true}},
+	})
+	require.NoError(t, err)
+
+	// Повторный user_id: ошибка уникальности переводится в USER_EXISTS,
+	// исходная ошибка базы остаётся в цепочке.
+	_, err = ts.UserService.CreateUser(ctx, &dto.CreateUserRequest{UserID: "e1", Name: "Alice", TeamName: "core", IsActive: true})
+	require.ErrorIs(t, err, service.ErrUserExists)
+	require.ErrorIs(t, err, repository.ErrDuplicate)
+
+	var constraintErr *repository.ConstraintError
+	require.True(t, errors.As(err, &constraintErr))
+	require.Equal(t, "users", constraintErr.Table)
+
+	status, resp := handler.MapError(err)
+	require.Equal(t, http.StatusConflict, status)
+	require.Equal(t, "USER_EXISTS", resp.Code)
+	require.Equal(t, "user already exists", resp.Message)
+
+	// Ссылка на несуществующую команду: нарушение внешнего ключа.
+	err = ts.DB.Exec("INSERT INTO users (user_id, username, team_id, is_active) VALUES (?, ?, ?, TRUE)", "e2", "Bob", uuid.New()).Error
+	require.ErrorIs(t, err, repository.ErrForeignKey)
+}
+
+func TestErrors_MapError(t *testing.T) {
+	// Детали доменной ошибки попадают в ответ.
+	status, resp := handler.MapError(service.ErrUnknownOwner.WithDetails("owner", "ghost"))
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Equal(t, "UNKNOWN_OWNER", resp.Code)
+	require.Equal(t, map[string]any{"owner": "ghost"}, resp.Details)
+
+	// Неизвестная ошибка — 500 без подробностей, но с correlation ID.
+	status, resp = handler.MapError(errors.New("connection reset by peer"))
+	require.Equal(t, http.StatusInternalServerError, status)
+	require.Equal(t, "INTERNAL_ERROR", resp.Code)
+	require.NotContains(t, resp.Message, "connection reset")
+	require.NotEmpty(t, resp.CorrelationID)
+}
@@ -0,0 +1,140 @@
+package integration
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/models"
+	"github.com/mink0ff/pr_service/internal/service"
+	"github.com/mink0ff/pr_service/tests/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func initEscalationTest(t *testing.T) {
+	ctx := utils.SeedBackendTeam(t, ts, utils.BackendMembers(4)...)
+
+	remind, reassign := 24, 72
+	_, err := ts.TeamService.SetSLA(ctx, &dto.TeamSLA{
+		TeamName:           "backend",
+		RemindAfterHours:   &remind,
+		ReassignAfterHours: &reassign,
+	})
+	require.NoError(t, err)
+
+	_, err = ts.PRService.CreatePR(ctx, &dto.CreatePRRequest{
+		PullRequestID:   "pr-1",
+		PullRequestName: "Slow review",
+		AuthorID:        "u1",
+	})
+	require.NoError(t, err)
+}
+
+func backdateAssignments(t *testing.T, prID string, age time.Duration) {
+	err := ts.DB.Exec("UPDATE pr_reviewers SET assigned_at = ? WHERE pull_request_id = ?", time.Now().Add(-age), prID).Error
+	require.NoError(t, err)
+}
+
+func countHistoryEvents(t *testing.T, eventType models.HistoryEventType) int64 {
+	var count int64
+	err := ts.DB.Model(&models.ReviewerAssignmentHistory{}).Where("event_type = ?", eventType).Count(&count).Error
+	require.NoError(t, err)
+	return count
+}
+
+func TestEscalation_RemindsOnceAcrossReplicas(t *testing.T) {
+	initEscalationTest(t)
+	ctx := context.Background()
+
+	report, err := ts.Escalation.EscalateStaleReviews(ctx)
+	require.NoError(t, err)
+	require.Zero(t, report.Reminded)
+
+	backdateAssignments(t, "pr-1", 30*time.Hour)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		reminded int
+	)
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r, err := ts.Escalation.EscalateStaleReviews(ctx)
+			require.NoError(t, err)
+			mu.Lock()
+			reminded += r.Reminded
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, 2, reminded)
+	require.Equal(t, int64(2), countHistoryEvents(t, models.EventSLAReminder))
+
+	stats, err := ts.StatsService.GetReviewerStats(ctx)
+	require.NoError(t, err)
+	require.Len(t, stats.Items, 2)
+}
+
+func TestEscalation_ReassignsOverdueReviewers(t *testing.T) {
+	initEscalationTest(t)
+	ctx := context.Background()
+
+	backdateAssignments(t, "pr-1", 80*time.Hour)
+
+	report, err := ts.Escalation.EscalateStaleReviews(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, report.Reassigned)
+	require.Equal(t, int64(2), countHistoryEvents(t, models.EventSLAReassigned))
+
+	report, err = ts.Escalation.EscalateStaleReviews(ctx)
+	require.NoError(t, err)
+	require.Zero(t, report.Reassigned)
+	require.Zero(t, report.Reminded)
+}
+
+func TestEscalation_ReassignsOnceAcrossReplicas(t *testing.T) {
+	initEscalationTest(t)
+	ctx := context.Background()
+
+	backdateAssignments(t, "pr-1", 80*time.Hour)
+
+	// Слоты, уже переназначенные другой репликой, считаются пропущенными.
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		reassigned int
+	)
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r, err := ts.Escalation.EscalateStaleReviews(ctx)
+			require.NoError(t, err)
+			mu.Lock()
+			reassigned += r.Reassigned
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, 2, reassigned)
+	require.Equal(t, int64(2), countHistoryEvents(t, models.EventSLAReassigned))
+}
+
+func TestEscalation_InvalidSLA(t *testing.T) {
+	initEscalationTest(t)
+	ctx := context.Background()
+
+	remind, reassign := 48, 24
+	_, err := ts.TeamService.SetSLA(ctx, &dto.TeamSLA{
+		TeamName:           "backend",
+		RemindAfterHours:   &remind,
+		ReassignAfterHours: &reassign,
+	})
+	require.ErrorIs(t, err, service.ErrInvalidSLA)
+}
@@ -0,0 +1,181 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/export"
+	"github.com/mink0ff/pr_service/internal/service"
+	"github.com/mink0ff/pr_service/tests/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func initExportTest(t *testing.T) context.Context {
+	members := utils.BackendMembers(4)
+	members[3].IsActive = false
+	ctx := utils.SeedBackendTeam(t, ts, members...)
+
+	limit := 5
+	_, err := ts.UserService.SetReviewLimit(ctx, &dto.UserReviewLimit{UserID: "u2", MaxOpenReviews: &limit})
+	require.NoError(t, err)
+
+	for _, id := range []string{"pr-1", "pr-2"} {
+		_, err := ts.PRService.CreatePR(ctx, &dto.CreatePRRequest{PullRequestID: id, PullRequestName: "Export " + id, AuthorID: "u1"})
+		require.NoError(t, err)
+	}
+	_, err = ts.PRService.MergePR(ctx, &dto.MergePRRequest{PullRequestID: "pr-2"})
+	require.NoError(t, err)
+	_, err = ts.PRService.SetReviewerPinned(ctx, &dto.PinReviewerRequest{PullRequestID: "pr-1", UserID: "u2"}, true)
+	require.NoError(t, err)
+
+	return ctx
+}
+
+func TestExport_SnapshotRestoresIntoEmptyDatabase(t *testing.T) {
+	ctx := initExportTest(t)
+
+	teamBefore, err := ts.TeamService.GetTeam(ctx, "backend")
+	require.NoError(t, err)
+	statsBefore, err := ts.StatsService.GetReviewerStats(ctx)
+	require.NoError(t, err)
+	reviewsBefore, err := ts.UserService.GetReviewPRs(ctx, "u2")
+	require.NoError(t, err)
+
+	var archive bytes.Buffer
+	manifest, err := ts.Export.Snapshot(ctx, &archive)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), manifest.Counts[export.EntityTeams])
+	require.Equal(t, int64(4), manifest.Counts[export.EntityUsers])
+	require.Equal(t, int64(2), manifest.Counts[export.EntityPullRequests])
+	require.Equal(t, int64(4), manifest.Counts[export.EntityReviewers])
+
+	// В непустую базу восстанавливать нельзя.
+	_, err = ts.Export.Restore(ctx, bytes.NewReader(archive.Bytes()))
+	require.ErrorIs(t, err, service.ErrDatabaseNotEmpty)
+
+	utils.TruncateTables(ts.DB)
+
+	report, err := ts.Export.Restore(ctx, bytes.NewReader(archive.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, manifest.Counts, report.Restored)
+
+	teamAfter, err := ts.TeamService.GetTeam(ctx, "backend")
+	require.NoError(t, err)
+	require.ElementsMatch(t, teamBefore.Members, teamAfter.Members)
+
+	statsAfter, err := ts.StatsService.GetReviewerStats(ctx)
+	require.NoError(t, err)
+	require.ElementsMatch(t, statsBefore.Items, statsAfter.Items)
+
+	reviewsAfter, err := ts.UserService.GetReviewPRs(ctx, "u2")
+	require.NoError(t, err)
+	require.Len(t, reviewsAfter, len(reviewsBefore))
+
+	// Закрепление и лимит ревью переживают восстановление.
+	_, err = ts.PRService.ReassignReviewer(ctx, &dto.ReassignReviewerRequest{PullRequestID: "pr-1", OldUserID: "u2"})
+	require.ErrorIs(t, err, service.ErrReviewerPinned)
+	limit, err := ts.UserService.GetReviewLimit(ctx, "u2")
+	require.NoError(t, err)
+	require.Equal(t, 5, *limit.MaxOpenReviews)
+}
+
+// snapshotTables — все таблицы, которые входят в снимок.
+var snapshotTables = []string{
+	"teams", "users", "team_memberships", "team_settings", "ownership_rules", "user_skills",
+	"user_notification_settings", "pull_requests", "pr_reviewers", "pr_labels",
+	"reviewer_assignment_histories", "team_audit_events", "api_tokens",
+}
+
+// dumpTables возвращает строки каждой таблицы в виде JSON в стабильном порядке.
+func dumpTables(t *testing.T) map[string][]string {
+	t.Helper()
+	dump := map[string][]string{}
+	for _, table := range snapshotTables {
+		var rows []string
+		require.NoError(t, ts.DB.Raw("SELECT to_jsonb(t)::text FROM "+table+" t ORDER BY 1").Scan(&rows).Error)
+		dump[table] = rows
+	}
+	return dump
+}
+
+func TestExport_SnapshotRoundTripsEveryTable(t *testing.T) {
+	ctx := initExportTest(t)
+
+	remind, rotation := 4, 3
+	_, err := ts.TeamService.SetSLA(ctx, &dto.TeamSLA{TeamName: "backend", RemindAfterHours: &remind})
+	require.NoError(t, err)
+	_, err = ts.TeamService.SetRotation(ctx, &dto.TeamRotation{TeamName: "backend", Window: &rotation})
+	require.NoError(t, err)
+	_, err = ts.TeamService.ImportOwnershipRules(ctx, &dto.ImportOwnershipRulesRequest{TeamName: "backend", Content: "*.go @u2\n/docs/ @u3\n"})
+	require.NoError(t, err)
+	_, err = ts.UserService.AddSkills(ctx, &dto.UserSkills{UserID: "u3", Skills: []string{"go", "sql"}})
+	require.NoError(t, err)
+	_, err = ts.UserService.SetNotificationSettings(ctx, &dto.NotificationSettings{UserID: "u2", Email: "bob@example.com", DigestFrequency: "WEEKLY"})
+	require.NoError(t, err)
+	_, err = ts.PRService.AddLabels(ctx, &dto.PRLabels{PullRequestID: "pr-1", Labels: []string{"backend"}})
+	require.NoError(t, err)
+	_, err = ts.Tokens.CreateToken(ctx, "backup", 0)
+	require.NoError(t, err)
+
+	before := dumpTables(t)
+	for _, table := range snapshotTables {
+		require.NotEmpty(t, before[table], table)
+	}
+
+	var archive bytes.Buffer
+	manifest, err := ts.Export.Snapshot(ctx, &archive)
+	require.NoError(t, err)
+	require.Len(t, manifest.Counts, len(export.Entities))
+
+	utils.TruncateTables(ts.DB)
+	_, err = ts.Export.Restore(ctx, bytes.NewReader(archive.Bytes()))
+	require.NoError(t, err)
+	after := dumpTables(t)
+	for _, table := range snapshotTables {
+		require.Equal(t, before[table], after[table], table)
+	}
+
+	// Восстановление через API делается с токеном, поэтому имеющиеся
+	// токены не мешают, а токены из снимка добавляются к ним.
+	utils.TruncateTables(ts.DB)
+	operator, err := ts.Tokens.CreateToken(ctx, "operator", 0)
+	require.NoError(t, err)
+	_, err = ts.Export.Restore(ctx, bytes.NewReader(archive.Bytes()))
+	require.NoError(t, err)
+	require.NoError(t, ts.Tokens.Authenticate(ctx, operator.Token))
+	require.Len(t, dumpTables(t)["api_tokens"], len(before["api_tokens"])+1)
+
+	// Журнал аудита без самих команд тоже делает базу непустой.
+	utils.TruncateTables(ts.DB)
+	_, err = ts.TeamService.CreateTeam(ctx, &dto.Team{TeamName: "ops", Members: []dto.TeamMember{}})
+	require.NoError(t, err)
+	require.NoError(t, ts.DB.Exec("DELETE FROM teams").Error)
+	_, err = ts.Export.Restore(ctx, bytes.NewReader(archive.Bytes()))
+	require.ErrorIs(t, err, service.ErrDatabaseNotEmpty)
+}
+
+func TestExport_EntityFormats(t *testing.T) {
+	ctx := initExportTest(t)
+
+	var csvOut bytes.Buffer
+	require.NoError(t, ts.Export.Export(ctx, export.EntityUsers, export.FormatCSV, &csvOut))
+	lines := strings.Split(strings.TrimSpace(csvOut.String()), "\n")
+	require.Len(t, lines, 5)
+	require.Equal(t, "user_id,username,team_id,is_active,max_open_reviews,deleted_at", lines[0])
+	require.True(t, strings.HasPrefix(lines[2], "u2,Bob,"))
+	require.True(t, strings.HasSuffix(lines[2], ",true,5,"))
+
+	var ndjsonOut bytes.Buffer
+	require.NoError(t, ts.Export.Export(ctx, export.EntityPullRequests, export.FormatNDJSON, &ndjsonOut))
+	require.Equal(t, 2, strings.Count(ndjsonOut.String(), "\n"))
+	require.Contains(t, ndjsonOut.String(), `"status":"MERGED"`)
+
+	require.ErrorIs(t, ts.Export.Export(ctx, "secrets", export.FormatCSV, &bytes.Buffer{}), service.ErrUnknownExportEntity)
+	require.ErrorIs(t, ts.Export.Export(ctx, export.EntityUsers, "xml", &bytes.Buffer{}), service.ErrInvalidExportFormat)
+
+	_, err := ts.Export.Restore(ctx, strings.NewReader("not an archive"))
+	require.ErrorIs(t, err, service.ErrInvalidSnapshot)
+}
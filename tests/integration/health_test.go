@@ -0,0 +1,61 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/health"
+	"github.com/mink0ff/pr_service/internal/repository/migrate"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealth_ReadinessChecks(t *testing.T) {
+	ctx := context.Background()
+
+	latest, err := migrate.LatestVersion("../../migrations")
+	require.NoError(t, err)
+
+	checker := health.NewChecker(time.Second, time.Minute)
+	checker.Add("database", health.DatabaseCheck(ts.DB))
+	checker.Add("migrations", health.MigrationsCheck(ts.DB, latest))
+
+	report := checker.Check(ctx)
+	require.Equal(t, dto.HealthOK, report.Status)
+	require.Len(t, report.Checks, 2)
+	require.False(t, report.Cached)
+
+	// Повторный запрос в пределах TTL отдаётся из кэша.
+	report = checker.Check(ctx)
+	require.True(t, report.Cached)
+
+	// Схема отстаёт от ожидаемой версии — сервис не готов.
+	behind := health.NewChecker(time.Second, 0)
+	behind.Add("migrations", health.MigrationsCheck(ts.DB, latest+1))
+	report = behind.Check(ctx)
+	require.Equal(t, dto.HealthFail, report.Status)
+	require.NotEmpty(t, report.Checks[0].Error)
+}
+
+func TestHealth_TimeoutAndShutdown(t *testing.T) {
+	ctx := context.Background()
+
+	checker := health.NewChecker(20*time.Millisecond, 0)
+	checker.Add("slow", func(context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+	checker.Add("broken", func(context.Context) error { return errors.New("boom") })
+
+	start := time.Now()
+	report := checker.Check(ctx)
+	require.Less(t, time.Since(start), 500*time.Millisecond)
+	require.Equal(t, dto.HealthFail, report.Status)
+	require.Equal(t, dto.HealthFail, report.Checks[0].Status)
+	require.Equal(t, "boom", report.Checks[1].Error)
+
+	checker.SetShuttingDown()
+	require.Equal(t, dto.HealthShuttingDown, checker.Check(ctx).Status)
+}
@@ -0,0 +1,118 @@
+package integration
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mink0ff/pr_service/internal/models"
+	"github.com/mink0ff/pr_service/internal/primport"
+	"github.com/mink0ff/pr_service/internal/service"
+	"github.com/mink0ff/pr_service/tests/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// initImportTest создаёт команду backend из u1–u3, где u3 неактивен.
+func initImportTest(t *testing.T) context.Context {
+	members := utils.BackendMembers(3)
+	members[2].IsActive = false
+	return utils.SeedBackendTeam(t, ts, members...)
+}
+
+func countPullRequests(t *testing.T) int64 {
+	var count int64
+	require.NoError(t, ts.DB.Model(&models.PullRequest{}).Count(&count).Error)
+	return count
+}
+
+func TestImport_NDJSON(t *testing.T) {
+	ctx := initImportTest(t)
+
+	data := `{"pull_request_id":"old-1","pull_request_name":"Legacy 1","author_id":"u1","status":"MERGED","reviewers":["u2","u3"],"created_at":"2024-01-10T10:00:00Z","merged_at":"2024-01-11T10:00:00Z"}
+
+{"pull_request_id":"old-2","pull_request_name":"Legacy 2","author_id":"u2","status":"OPEN","reviewers":["u1"],"created_at":"2024-02-01"}
+`
+	report, err := ts.Import.ImportPullRequests(ctx, primport.FormatNDJSON, strings.NewReader(data), false)
+	require.NoError(t, err)
+	require.Equal(t, 2, report.Total)
+	require.Equal(t, 2, report.Imported)
+	require.Empty(t, report.Errors)
+
+	// Ревьюеры берутся из файла как есть, даже неактивные.
+	prs, err := ts.UserService.GetReviewPRs(ctx, "u3")
+	require.NoError(t, err)
+	require.Len(t, prs, 1)
+	require.Equal(t, models.PRMerged, prs[0].Status)
+	require.NotNil(t, prs[0].MergedAt)
+
+	require.Equal(t, int64(3), countHistoryEvents(t, models.EventAssigned))
+
+	// Повторный импорт того же файла отклоняется целиком.
+	report, err = ts.Import.ImportPullRequests(ctx, primport.FormatNDJSON, strings.NewReader(data), false)
+	require.NoError(t, err)
+	require.Zero(t, report.Imported)
+	require.Len(t, report.Errors, 2)
+	require.Equal(t, 1, report.Errors[0].Row)
+	require.Equal(t, 3, report.Errors[1].Row)
+}
+
+func TestImport_CSVRowErrorsRejectWholeFile(t *testing.T) {
+	ctx := initImportTest(t)
+
+	data := `pull_request_id,pull_request_name,author_id,status,reviewers,created_at,merged_at
+old-1,Legacy 1,u1,merged,u2;u3,2024-01-10,2024-01-11
+old-2,Legacy 2,u1,OPEN,u1,2024-01-10,
+old-3,Legacy 3,ghost,OPEN,,2024-01-10,
+old-1,Legacy 4,u2,OPEN,,not-a-date,
+`
+	report, err := ts.Import.ImportPullRequests(ctx, primport.FormatCSV, strings.NewReader(data), false)
+	require.NoError(t, err)
+	require.Equal(t, 4, report.Total)
+	require.Zero(t, report.Imported)
+
+	rows := make([]int, 0, len(report.Errors))
+	for _, e := range report.Errors {
+		rows = append(rows, e.Row)
+	}
+	require.Equal(t, []int{3, 4, 5}, rows)
+	require.Contains(t, report.Errors[2].Error, "duplicate of row 2")
+	require.Contains(t, report.Errors[2].Error, "created_at")
+
+	require.Zero(t, countPullRequests(t))
+}
+
+func TestImport_RowsFollowCreateValidation(t *testing.T) {
+	ctx := initImportTest(t)
+
+	// ID и имена проверяются так же, как в /pullRequest/create.
+	data := `pull_request_id,pull_request_name,author_id,status,reviewers,created_at
+bad id,Legacy 1,u1,OPEN,,2024-01-10
+` + strings.Repeat("x", 65) + `,Legacy 2,u1,OPEN,,2024-01-10
+old-3,Legacy 3,u1,OPEN,-u2,2024-01-10
+old-4,Legacy 4,u1,OPEN,u2,2024-01-10
+`
+	report, err := ts.Import.ImportPullRequests(ctx, primport.FormatCSV, strings.NewReader(data), false)
+	require.NoError(t, err)
+	require.Zero(t, report.Imported)
+	require.Len(t, report.Errors, 3)
+	require.Contains(t, report.Errors[0].Error, "pull_request_id must start with a letter or digit")
+	require.Contains(t, report.Errors[1].Error, "pull_request_id must be at most 64 characters")
+	require.Contains(t, report.Errors[2].Error, "reviewers[0] must start with a letter or digit")
+	require.Zero(t, countPullRequests(t))
+}
+
+func TestImport_DryRunAndFormat(t *testing.T) {
+	ctx := initImportTest(t)
+
+	data := "pull_request_id,pull_request_name,author_id,status,created_at\nold-1,Legacy 1,u1,OPEN,2024-01-10\n"
+	report, err := ts.Import.ImportPullRequests(ctx, primport.FormatCSV, strings.NewReader(data), true)
+	require.NoError(t, err)
+	require.True(t, report.DryRun)
+	require.Equal(t, 1, report.Total)
+	require.Zero(t, report.Imported)
+	require.Empty(t, report.Errors)
+	require.Zero(t, countPullRequests(t))
+
+	_, err = ts.Import.ImportPullRequests(ctx, "xml", strings.NewReader(data), false)
+	require.ErrorIs(t, err, service.ErrInvalidImportFormat)
+}
@@ -0,0 +1,193 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mink0ff/pr_service/internal/jobs"
+	"github.com/mink0ff/pr_service/internal/models"
+	"github.com/mink0ff/pr_service/internal/repository"
+	"github.com/mink0ff/pr_service/tests/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func initJobsTest(t *testing.T) (context.Context, repository.JobRepository) {
+	utils.TruncateTables(ts.DB)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	return ctx, repository.NewJobRepo(ts.DB)
+}
+
+func waitForRuns(t *testing.T, repo repository.JobRepository, name string, n int) []models.JobRun {
+	t.Helper()
+
+	var runs []models.JobRun
+	require.Eventually(t, func() bool {
+		var err error
+		runs, err = repo.ListRuns(context.Background(), name, 50)
+		require.NoError(t, err)
+		if len(runs) < n {
+			return false
+		}
+		for _, run := range runs {
+			if run.Status == models.JobRunRunning {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 50*time.Millisecond)
+
+	return runs
+}
+
+func TestJobs_ManualTriggerRecordsRun(t *testing.T) {
+	ctx, repo := initJobsTest(t)
+
+	runner := jobs.NewRunner(ts.DB, repo)
+	require.NoError(t, runner.Register("ok-job", "@daily", func(context.Context) error { return nil }))
+	require.NoError(t, runner.Register("failing-job", "@daily", func(context.Context) error { return errors.New("boom") }))
+	require.NoError(t, runner.Start(ctx))
+
+	_, err := runner.TriggerJob(ctx, "ok-job")
+	require.NoError(t, err)
+	_, err = runner.TriggerJob(ctx, "failing-job")
+	require.NoError(t, err)
+
+	runs := waitForRuns(t, repo, "ok-job", 1)
+	require.Equal(t, models.JobRunSucceeded, runs[0].Status)
+	require.Equal(t, models.JobTriggerManual, runs[0].Trigger)
+
+	runs = waitForRuns(t, repo, "failing-job", 1)
+	require.Equal(t, models.JobRunFailed, runs[0].Status)
+	require.NotNil(t, runs[0].Error)
+	require.Equal(t, "boom", *runs[0].Error)
+
+	_, err = runner.TriggerJob(ctx, "missing")
+	require.ErrorIs(t, err, jobs.ErrJobNotFound)
+}
+
+func TestJobs_SingleExecutionAcrossReplicas(t *testing.T) {
+	ctx, repo := initJobsTest(t)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	work := func(context.Context) error {
+		calls.Add(1)
+		<-release
+		return nil
+	}
+
+	first := jobs.NewRunner(ts.DB, repo)
+	second := jobs.NewRunner(ts.DB, repo)
+	for _, r := range []*jobs.Runner{first, second} {
+		require.NoError(t, r.Register("shared-job", "@daily", work))
+		require.NoError(t, r.Start(ctx))
+	}
+
+	_, err := first.TriggerJob(ctx, "shared-job")
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, 5*time.Second, 20*time.Millisecond)
+
+	_, err = second.TriggerJob(ctx, "shared-job")
+	require.NoError(t, err)
+	time.Sleep(200 * time.Millisecond)
+
+	close(release)
+	waitForRuns(t, repo, "shared-job", 1)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestJobs_PauseIsShared(t *testing.T) {
+	ctx, repo := initJobsTest(t)
+
+	var calls atomic.Int32
+	runner := jobs.NewRunner(ts.DB, repo)
+	require.NoError(t, runner.Register("ticker", "@every 1s", func(context.Context) error {
+		calls.Add(1)
+		return nil
+	}))
+
+	other := jobs.NewRunner(ts.DB, repo)
+	require.NoError(t, other.Register("ticker", "@every 1s", func(context.Context) error { return nil }))
+
+	require.NoError(t, runner.Start(ctx))
+	require.NoError(t, other.Start(ctx))
+
+	status, err := other.SetJobPaused(ctx, "ticker", true)
+	require.NoError(t, err)
+	require.True(t, status.Paused)
+
+	time.Sleep(2500 * time.Millisecond)
+	require.Zero(t, calls.Load())
+
+	listed, err := runner.ListJobs(ctx)
+	require.NoError(t, err)
+	require.Len(t, listed.Jobs, 1)
+	require.True(t, listed.Jobs[0].Paused)
+}
+
+func TestJobs_OnlyScheduledRunsTakeTheSlot(t *testing.T) {
+	ctx, repo := initJobsTest(t)
+	require.NoError(t, repo.Register(ctx, models.Job{Name: "slot-job", Schedule: "@daily", UpdatedAt: time.Now()}))
+
+	slot := time.Now().Add(-time.Minute)
+	addRun := func(trigger models.JobTrigger, status models.JobRunStatus) {
+		require.NoError(t, repo.StartRun(ctx, models.JobRun{
+			JobRunID:  uuid.New(),
+			JobName:   "slot-job",
+			Trigger:   trigger,
+			Status:    status,
+			Instance:  "test",
+			StartedAt: time.Now(),
+		}))
+	}
+
+	// Ручной запуск и упавший запуск по расписанию не занимают слот.
+	addRun(models.JobTriggerManual, models.JobRunSucceeded)
+	addRun(models.JobTriggerSchedule, models.JobRunFailed)
+	ran, err := repo.HasScheduledRunSince(ctx, "slot-job", slot)
+	require.NoError(t, err)
+	require.False(t, ran)
+
+	addRun(models.JobTriggerSchedule, models.JobRunSucceeded)
+	ran, err = repo.HasScheduledRunSince(ctx, "slot-job", slot)
+	require.NoError(t, err)
+	require.True(t, ran)
+
+	ran, err = repo.HasScheduledRunSince(ctx, "slot-job", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.False(t, ran)
+}
+
+func TestJobs_ParseSchedule(t *testing.T) {
+	base := time.Date(2025, time.March, 7, 10, 30, 0, 0, time.UTC) // пятница
+
+	cases := []struct {
+		spec string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2025, time.March, 7, 10, 45, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC)},
+		{"0 8 * * *", time.Date(2025, time.March, 8, 8, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2025, time.March, 9, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, time.March, 7, 11, 0, 0, 0, time.UTC)},
+		{"@every 90s", base.Add(90 * time.Second)},
+	}
+
+	for _, c := range cases {
+		schedule, err := jobs.ParseSchedule(c.spec)
+		require.NoError(t, err, c.spec)
+		require.Equal(t, c.want, schedule.Next(base), c.spec)
+	}
+
+	for _, spec := range []string{"", "* * *", "60 * * * *", "0 9 * * 5-1", "@every 10ms"} {
+		_, err := jobs.ParseSchedule(spec)
+		require.Error(t, err, spec)
+	}
+}
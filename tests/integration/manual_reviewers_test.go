@@ -0,0 +1,122 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/models"
+	"github.com/mink0ff/pr_service/internal/service"
+	"github.com/mink0ff/pr_service/tests/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func initManualReviewersTest(t *testing.T) context.Context {
+	ctx := utils.SeedBackendTeam(t, ts, append(utils.BackendMembers(5), dto.TeamMember{UserID: "u6", Username: "Frank"})...)
+
+	seed := int64(42)
+	resp, err := ts.PRService.CreatePR(ctx, &dto.CreatePRRequest{
+		PullRequestID:   "pr-1",
+		PullRequestName: "Manual review",
+		AuthorID:        "u1",
+		Seed:            &seed,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"u3", "u5"}, resp.PR.AssignedReviewers)
+
+	return ctx
+}
+
+func TestManualReviewers_AddRemoveAndPin(t *testing.T) {
+	ctx := initManualReviewersTest(t)
+
+	_, err := ts.PRService.AddReviewer(ctx, &dto.AddReviewerRequest{PullRequestID: "pr-1", UserID: "u2"})
+	require.ErrorIs(t, err, service.ErrTooManyReviewers)
+
+	removed, err := ts.PRService.RemoveReviewer(ctx, &dto.RemoveReviewerRequest{PullRequestID: "pr-1", UserID: "u5"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"u3"}, removed.PR.AssignedReviewers)
+	require.Empty(t, removed.ReplacedBy)
+
+	_, err = ts.PRService.AddReviewer(ctx, &dto.AddReviewerRequest{PullRequestID: "pr-1", UserID: "u1"})
+	require.ErrorIs(t, err, service.ErrAuthorAsReviewer)
+	_, err = ts.PRService.AddReviewer(ctx, &dto.AddReviewerRequest{PullRequestID: "pr-1", UserID: "u3"})
+	require.ErrorIs(t, err, service.ErrAlreadyAssigned)
+	_, err = ts.PRService.AddReviewer(ctx, &dto.AddReviewerRequest{PullRequestID: "pr-1", UserID: "u6"})
+	require.ErrorIs(t, err, service.ErrReviewerInactive)
+	_, err = ts.PRService.AddReviewer(ctx, &dto.AddReviewerRequest{PullRequestID: "pr-1", UserID: "ghost"})
+	require.ErrorIs(t, err, service.ErrUserNotFound)
+
+	added, err := ts.PRService.AddReviewer(ctx, &dto.AddReviewerRequest{PullRequestID: "pr-1", UserID: "u2", Pin: true})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"u2", "u3"}, added.PR.AssignedReviewers)
+	require.Equal(t, []string{"u2"}, added.PR.PinnedReviewers)
+
+	// Закреплённого ревьюера нельзя переназначить, пока его не открепят.
+	_, err = ts.PRService.ReassignReviewer(ctx, &dto.ReassignReviewerRequest{PullRequestID: "pr-1", OldUserID: "u2"})
+	require.ErrorIs(t, err, service.ErrReviewerPinned)
+
+	unpinned, err := ts.PRService.SetReviewerPinned(ctx, &dto.PinReviewerRequest{PullRequestID: "pr-1", UserID: "u2"}, false)
+	require.NoError(t, err)
+	require.Empty(t, unpinned.PR.PinnedReviewers)
+
+	_, err = ts.PRService.ReassignReviewer(ctx, &dto.ReassignReviewerRequest{PullRequestID: "pr-1", OldUserID: "u2"})
+	require.NoError(t, err)
+
+	_, err = ts.PRService.SetReviewerPinned(ctx, &dto.PinReviewerRequest{PullRequestID: "pr-1", UserID: "u5"}, true)
+	require.ErrorIs(t, err, service.ErrReviewerNotAssigned)
+
+	require.Equal(t, int64(1), countHistoryEvents(t, models.EventManuallyAssigned))
+	require.Equal(t, int64(1), countHistoryEvents(t, models.EventRemoved))
+	require.Equal(t, int64(1), countHistoryEvents(t, models.EventPinned))
+	require.Equal(t, int64(1), countHistoryEvents(t, models.EventUnpinned))
+}
+
+func TestManualReviewers_RemoveWithBackfill(t *testing.T) {
+	ctx := initManualReviewersTest(t)
+
+	resp, err := ts.PRService.RemoveReviewer(ctx, &dto.RemoveReviewerRequest{PullRequestID: "pr-1", UserID: "u3", Backfill: true})
+	require.NoError(t, err)
+	require.Contains(t, []string{"u2", "u4"}, resp.ReplacedBy)
+	require.ElementsMatch(t, []string{"u5", resp.ReplacedBy}, resp.PR.AssignedReviewers)
+
+	_, err = ts.PRService.MergePR(ctx, &dto.MergePRRequest{PullRequestID: "pr-1"})
+	require.NoError(t, err)
+
+	_, err = ts.PRService.RemoveReviewer(ctx, &dto.RemoveReviewerRequest{PullRequestID: "pr-1", UserID: "u5"})
+	require.ErrorIs(t, err, service.ErrPRMerged)
+}
+
+func TestManualReviewers_PinnedSurviveDeactivationAndEscalation(t *testing.T) {
+	ctx := initManualReviewersTest(t)
+
+	_, err := ts.PRService.SetReviewerPinned(ctx, &dto.PinReviewerRequest{PullRequestID: "pr-1", UserID: "u3"}, true)
+	require.NoError(t, err)
+
+	remind, reassign := 24, 72
+	_, err = ts.TeamService.SetSLA(ctx, &dto.TeamSLA{
+		TeamName:           "backend",
+		RemindAfterHours:   &remind,
+		ReassignAfterHours: &reassign,
+	})
+	require.NoError(t, err)
+	backdateAssignments(t, "pr-1", 80*time.Hour)
+
+	// Просроченного закреплённого ревьюера только напоминают, но не заменяют.
+	report, err := ts.Escalation.EscalateStaleReviews(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Reassigned)
+	require.Equal(t, 1, report.Reminded)
+
+	prs, err := ts.UserService.GetReviewPRs(ctx, "u3")
+	require.NoError(t, err)
+	require.Len(t, prs, 1)
+
+	_, err = ts.TeamService.DeactivateTeamUsers(ctx, &dto.DeactivateTeamUsersRequest{TeamName: "backend"})
+	require.NoError(t, err)
+
+	prs, err = ts.UserService.GetReviewPRs(ctx, "u3")
+	require.NoError(t, err)
+	require.Len(t, prs, 1)
+}
@@ -0,0 +1,148 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/service"
+	"github.com/mink0ff/pr_service/tests/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// initMultiTeamTest создаёт команды backend и platform; p1 из platform
+// дополнительно ревьюит для backend.
+func initMultiTeamTest(t *testing.T) context.Context {
+	utils.TruncateTables(ts.DB)
+	ctx := context.Background()
+
+	for _, team := range []dto.Team{
+		{TeamName: "backend", Members: []dto.TeamMember{
+			{UserID: "b1", Username: "Alice", IsActive: true},
+			{UserID: "b2", Username: "Bob", IsActive: true},
+		}},
+		{TeamName: "platform", Members: []dto.TeamMember{
+			{UserID: "p1", Username: "Carol", IsActive: true},
+			{UserID: "p2", Username: "Dave", IsActive: true},
+		}},
+	} {
+		_, err := ts.TeamService.CreateTeam(ctx, &team)
+		require.NoError(t, err)
+	}
+
+	resp, err := ts.TeamService.AddMembership(ctx, &dto.TeamMembershipRequest{TeamName: "backend", UserID: "p1", IsActive: true})
+	require.NoError(t, err)
+	require.Equal(t, []dto.UserMembership{
+		{TeamName: "platform", IsPrimary: true, IsActive: true},
+		{TeamName: "backend", IsActive: true},
+	}, resp.Teams)
+	return ctx
+}
+
+func TestMultiTeam_SecondaryMemberReviews(t *testing.T) {
+	ctx := initMultiTeamTest(t)
+
+	backend, err := ts.TeamService.GetTeam(ctx, "backend")
+	require.NoError(t, err)
+	require.Len(t, backend.Members, 3)
+	require.Contains(t, backend.Members, dto.TeamMember{UserID: "p1", Username: "Carol", IsActive: true, PrimaryTeam: "platform"})
+
+	user, err := ts.UserService.GetUser(ctx, "p1")
+	require.NoError(t, err)
+	require.Equal(t, "platform", user.TeamName)
+	require.Len(t, user.Teams, 2)
+
+	list, err := ts.UserService.ListUsers(ctx, &dto.ListUsersRequest{TeamName: "backend", Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), list.Total)
+
+	// Ревьюеры для backend выбираются и из дополнительных участников.
+	pr, err := ts.PRService.CreatePR(ctx, &dto.CreatePRRequest{PullRequestID: "pr-mt1", PullRequestName: "API", AuthorID: "b1"})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"b2", "p1"}, pr.PR.AssignedReviewers)
+
+	// Неактивное членство снимает с ревью команды, но не с остальных.
+	_, err = ts.TeamService.AddMembership(ctx, &dto.TeamMembershipRequest{TeamName: "backend", UserID: "p1", IsActive: false})
+	require.NoError(t, err)
+	reviews, err := ts.UserService.GetReviewPRs(ctx, "p1")
+	require.NoError(t, err)
+	require.Empty(t, reviews)
+
+	user, err = ts.UserService.GetUser(ctx, "p1")
+	require.NoError(t, err)
+	require.True(t, user.IsActive)
+
+	_, err = ts.TeamService.AddMembership(ctx, &dto.TeamMembershipRequest{TeamName: "platform", UserID: "p1", IsActive: true})
+	require.ErrorIs(t, err, service.ErrAlreadyInTeam)
+	_, err = ts.TeamService.RemoveMembership(ctx, &dto.RemoveMembershipRequest{TeamName: "platform", UserID: "p1"})
+	require.ErrorIs(t, err, service.ErrPrimaryMembership)
+	_, err = ts.TeamService.RemoveMembership(ctx, &dto.RemoveMembershipRequest{TeamName: "backend", UserID: "p2"})
+	require.ErrorIs(t, err, service.ErrUserNotInTeam)
+
+	resp, err := ts.TeamService.RemoveMembership(ctx, &dto.RemoveMembershipRequest{TeamName: "backend", UserID: "p1"})
+	require.NoError(t, err)
+	require.Len(t, resp.Teams, 1)
+	require.Subset(t, auditActions(t, "backend"), []string{"MEMBERSHIP_ADDED", "MEMBERSHIP_REMOVED"})
+}
+
+func TestMultiTeam_ReplacementFromAuthorTeam(t *testing.T) {
+	ctx := initMultiTeamTest(t)
+
+	for _, id := range []string{"pr-mt1", "pr-mt2"} {
+		pr, err := ts.PRService.CreatePR(ctx, &dto.CreatePRRequest{PullRequestID: id, PullRequestName: "API", AuthorID: "b1"})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"b2", "p1"}, pr.PR.AssignedReviewers)
+	}
+	_, err := ts.UserService.CreateUser(ctx, &dto.CreateUserRequest{UserID: "b3", Name: "Erin", TeamName: "backend", IsActive: true})
+	require.NoError(t, err)
+
+	// У p1 основная команда platform, но замена берётся из команды автора,
+	// а не из platform.
+	reassigned, err := ts.PRService.ReassignReviewer(ctx, &dto.ReassignReviewerRequest{PullRequestID: "pr-mt1", OldUserID: "p1"})
+	require.NoError(t, err)
+	require.Equal(t, "b3", reassigned.ReplacedBy)
+
+	removed, err := ts.PRService.RemoveReviewer(ctx, &dto.RemoveReviewerRequest{PullRequestID: "pr-mt2", UserID: "p1", Backfill: true})
+	require.NoError(t, err)
+	require.Equal(t, "b3", removed.ReplacedBy)
+	require.ElementsMatch(t, []string{"b2", "b3"}, removed.PR.AssignedReviewers)
+}
+
+func TestMultiTeam_DeactivateSyncAndMove(t *testing.T) {
+	ctx := initMultiTeamTest(t)
+
+	// Синхронизация касается только собственных участников команды.
+	diff, err := ts.TeamService.SyncTeam(ctx, &dto.TeamSyncRequest{Team: dto.Team{TeamName: "backend", Members: []dto.TeamMember{
+		{UserID: "b1", Username: "Alice", IsActive: true},
+		{UserID: "b2", Username: "Bob", IsActive: true},
+	}}})
+	require.NoError(t, err)
+	require.Empty(t, diff.Deactivated)
+	require.Equal(t, 2, diff.Unchanged)
+
+	// Деактивация команды выключает только членство в ней у участников из
+	// других команд.
+	deactivated, err := ts.TeamService.DeactivateTeamUsers(ctx, &dto.DeactivateTeamUsersRequest{TeamName: "backend"})
+	require.NoError(t, err)
+	require.Equal(t, 3, deactivated.DeactivatedCount)
+
+	platform, err := ts.TeamService.GetTeam(ctx, "platform")
+	require.NoError(t, err)
+	for _, m := range platform.Members {
+		require.True(t, m.IsActive)
+	}
+	backend, err := ts.TeamService.GetTeam(ctx, "backend")
+	require.NoError(t, err)
+	for _, m := range backend.Members {
+		require.False(t, m.IsActive)
+	}
+
+	// Перевод в команду дополнительного членства делает его основным,
+	// а из прежней основной команды пользователь уходит.
+	_, err = ts.TeamService.MoveMember(ctx, &dto.MoveTeamMemberRequest{UserID: "p1", TeamName: "backend"})
+	require.NoError(t, err)
+	user, err := ts.UserService.GetUser(ctx, "p1")
+	require.NoError(t, err)
+	require.Equal(t, "backend", user.TeamName)
+	require.Equal(t, []dto.UserMembership{{TeamName: "backend", IsPrimary: true, IsActive: true}}, user.Teams)
+}
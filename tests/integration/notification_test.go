@@ -0,0 +1,97 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mink0ff/pr_service/internal/config"
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/notifier"
+	"github.com/mink0ff/pr_service/internal/repository"
+	"github.com/mink0ff/pr_service/internal/repository/transaction"
+	"github.com/mink0ff/pr_service/internal/service"
+	"github.com/mink0ff/pr_service/tests/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// initNotificationTest создаёт команду backend из u1–u3 и сервисы,
+// отправляющие уведомления в локальный вебхук.
+func initNotificationTest(t *testing.T) (context.Context, service.PRService, service.NotificationService, *utils.WebhookStub) {
+	ctx := utils.SeedBackendTeam(t, ts, utils.BackendMembers(3)...)
+
+	stub := utils.NewWebhookStub()
+	t.Cleanup(stub.Close)
+
+	slack, err := notifier.NewSlackNotifier(&config.NotifyConfig{
+		Enabled:        true,
+		PRLinkTemplate: "https://git.example.com/pr/{pull_request_id}",
+		Timeout:        time.Second,
+	})
+	require.NoError(t, err)
+
+	userRepo := repository.NewUserRepo(ts.DB)
+	teamRepo := repository.NewTeamRepo(ts.DB)
+	prRepo := repository.NewPrRepo(ts.DB)
+	historyRepo := repository.NewReviewerHistoryRepo(ts.DB)
+	settingsRepo := repository.NewNotificationSettingsRepo(ts.DB)
+	txManager := transaction.NewTransactionManager(ts.DB)
+
+	prSvc := service.NewPRService(prRepo, userRepo, teamRepo, historyRepo, repository.NewOwnershipRuleRepo(ts.DB), repository.NewTagRepo(ts.DB), repository.NewTeamSettingsRepo(ts.DB), txManager, slack, service.HashSeeds())
+	notificationSvc := service.NewNotificationService(teamRepo, userRepo, settingsRepo, slack, nil)
+
+	return ctx, prSvc, notificationSvc, stub
+}
+
+func TestNotification_AssignmentAndDigest(t *testing.T) {
+	ctx, prSvc, notificationSvc, stub := initNotificationTest(t)
+
+	_, err := ts.TeamService.SetWebhook(ctx, &dto.SetTeamWebhookRequest{TeamName: "backend", WebhookURL: stub.URL()})
+	require.NoError(t, err)
+
+	_, err = prSvc.CreatePR(ctx, &dto.CreatePRRequest{
+		PullRequestID:   "pr-1",
+		PullRequestName: "Add payments module",
+		AuthorID:        "u1",
+	})
+	require.NoError(t, err)
+
+	messages := stub.Messages()
+	require.Len(t, messages, 1)
+	require.Contains(t, messages[0], "Add payments module")
+	require.Contains(t, messages[0], "Alice")
+	require.Contains(t, messages[0], "https://git.example.com/pr/pr-1")
+
+	require.NoError(t, notificationSvc.SendReviewDigests(ctx))
+
+	messages = stub.Messages()
+	require.Len(t, messages, 2)
+	require.Contains(t, messages[1], "Bob")
+	require.Contains(t, messages[1], "Charlie")
+}
+
+func TestNotification_EscapesPRName(t *testing.T) {
+	ctx, prSvc, _, stub := initNotificationTest(t)
+
+	_, err := ts.TeamService.SetWebhook(ctx, &dto.SetTeamWebhookRequest{TeamName: "backend", WebhookURL: stub.URL()})
+	require.NoError(t, err)
+
+	// Управляющие символы mrkdwn в названии не ломают и не подменяют ссылку.
+	_, err = prSvc.CreatePR(ctx, &dto.CreatePRRequest{
+		PullRequestID:   "pr-1",
+		PullRequestName: "Fix <http://evil.example|docs> & tests",
+		AuthorID:        "u1",
+	})
+	require.NoError(t, err)
+
+	messages := stub.Messages()
+	require.Len(t, messages, 1)
+	require.Contains(t, messages[0], "<https://git.example.com/pr/pr-1|Fix &lt;http://evil.example|docs&gt; &amp; tests>")
+}
+
+func TestNotification_InvalidWebhookURL(t *testing.T) {
+	ctx, _, _, _ := initNotificationTest(t)
+
+	_, err := ts.TeamService.SetWebhook(ctx, &dto.SetTeamWebhookRequest{TeamName: "backend", WebhookURL: "not a url"})
+	require.ErrorIs(t, err, service.ErrInvalidWebhookURL)
+}
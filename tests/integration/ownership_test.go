@@ -0,0 +1,172 @@
+package integration
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mink0ff/pr_service/internal/codeowners"
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/service"
+	"github.com/mink0ff/pr_service/tests/utils"
+	"github.com/stretchr/testify/require"
+)
+
+const testCodeowners = `
+# общие правила
+*            @platform
+/billing/    @u2
+*.sql        @u3
+`
+
+func initOwnershipTest(t *testing.T) context.Context {
+	ctx := utils.SeedBackendTeam(t, ts)
+
+	_, err := ts.TeamService.CreateTeam(ctx, &dto.Team{
+		TeamName: "platform",
+		Members:  []dto.TeamMember{{UserID: "p1", Username: "Pat", IsActive: true}},
+	})
+	require.NoError(t, err)
+
+	return ctx
+}
+
+func TestOwnership_ImportAndList(t *testing.T) {
+	ctx := initOwnershipTest(t)
+
+	imported, err := ts.TeamService.ImportOwnershipRules(ctx, &dto.ImportOwnershipRulesRequest{
+		TeamName: "backend",
+		Content:  testCodeowners,
+	})
+	require.NoError(t, err)
+	require.Len(t, imported.Rules, 3)
+
+	rules, err := ts.TeamService.GetOwnershipRules(ctx, "backend")
+	require.NoError(t, err)
+	require.Equal(t, []string{"*", "/billing/", "*.sql"}, []string{rules.Rules[0].Pattern, rules.Rules[1].Pattern, rules.Rules[2].Pattern})
+	require.Equal(t, []string{"@u2"}, rules.Rules[1].Owners)
+
+	added, err := ts.TeamService.AddOwnershipRule(ctx, &dto.AddOwnershipRuleRequest{
+		TeamName: "backend",
+		Pattern:  "docs/**",
+		Owners:   []string{"@u4"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 4, added.Position)
+
+	rules, err = ts.TeamService.DeleteOwnershipRule(ctx, &dto.DeleteOwnershipRuleRequest{TeamName: "backend", RuleID: added.RuleID})
+	require.NoError(t, err)
+	require.Len(t, rules.Rules, 3)
+}
+
+func TestOwnership_InvalidRules(t *testing.T) {
+	ctx := initOwnershipTest(t)
+
+	_, err := ts.TeamService.ImportOwnershipRules(ctx, &dto.ImportOwnershipRulesRequest{
+		TeamName: "backend",
+		Content:  "/api/ @ghost",
+	})
+	require.ErrorIs(t, err, service.ErrUnknownOwner)
+
+	_, err = ts.TeamService.ImportOwnershipRules(ctx, &dto.ImportOwnershipRulesRequest{
+		TeamName: "backend",
+		Content:  "!/api/ @u2",
+	})
+	require.ErrorIs(t, err, service.ErrInvalidOwnership)
+
+	_, err = ts.TeamService.AddOwnershipRule(ctx, &dto.AddOwnershipRuleRequest{
+		TeamName: "backend",
+		Pattern:  "/api/",
+		Owners:   []string{"u2"},
+	})
+	require.ErrorIs(t, err, service.ErrInvalidOwnership)
+
+	_, err = ts.TeamService.DeleteOwnershipRule(ctx, &dto.DeleteOwnershipRuleRequest{TeamName: "backend", RuleID: "missing"})
+	require.ErrorIs(t, err, service.ErrRuleNotFound)
+}
+
+func TestOwnership_CreatePRPicksOwner(t *testing.T) {
+	ctx := initOwnershipTest(t)
+
+	_, err := ts.TeamService.ImportOwnershipRules(ctx, &dto.ImportOwnershipRulesRequest{
+		TeamName: "backend",
+		Content:  testCodeowners,
+	})
+	require.NoError(t, err)
+
+	// Последнее совпавшее правило побеждает: billing/*.sql принадлежит u3, а не u2.
+	for i := 0; i < 10; i++ {
+		resp, err := ts.PRService.CreatePR(ctx, &dto.CreatePRRequest{
+			PullRequestID:   "pr-sql-" + strings.Repeat("x", i),
+			PullRequestName: "Migrate billing",
+			AuthorID:        "u1",
+			ChangedFiles:    []string{"billing/migrations/001.sql"},
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.PR.AssignedReviewers, 2)
+		require.Contains(t, resp.PR.AssignedReviewers, "u3")
+
+		for _, match := range resp.ReviewerMatches {
+			if match.ReviewerID == "u3" {
+				require.NotNil(t, match.MatchedRule)
+				require.Equal(t, "*.sql", match.MatchedRule.Pattern)
+			} else {
+				require.Nil(t, match.MatchedRule)
+			}
+		}
+	}
+
+	// Владельцем может быть команда целиком, в том числе другая.
+	resp, err := ts.PRService.CreatePR(ctx, &dto.CreatePRRequest{
+		PullRequestID:   "pr-readme",
+		PullRequestName: "Update readme",
+		AuthorID:        "u1",
+		ChangedFiles:    []string{"README.md"},
+	})
+	require.NoError(t, err)
+	require.Contains(t, resp.PR.AssignedReviewers, "p1")
+
+	// Без списка файлов ревьюеры выбираются случайно из команды.
+	resp, err = ts.PRService.CreatePR(ctx, &dto.CreatePRRequest{
+		PullRequestID:   "pr-plain",
+		PullRequestName: "No files",
+		AuthorID:        "u1",
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.PR.AssignedReviewers, 2)
+	require.NotContains(t, resp.PR.AssignedReviewers, "p1")
+}
+
+func TestOwnership_PatternMatching(t *testing.T) {
+	cases := []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"*", "any/file.go", true},
+		{"*.js", "web/app/index.js", true},
+		{"*.js", "web/app/index.ts", false},
+		{"/build/logs/", "build/logs/today.log", true},
+		{"/build/logs/", "src/build/logs/today.log", false},
+		{"docs/*", "docs/getting-started.md", true},
+		{"docs/*", "docs/build-app/troubleshooting.md", false},
+		{"apps/", "services/apps/main.go", true},
+		{"**/logs", "deep/nested/logs/x.log", true},
+		{"/scripts/**", "scripts/ci/run.sh", true},
+		{"internal/service", "./internal/service/pr_service.go", true},
+		{"internal/service", "pkg/internal/service/x.go", false},
+	}
+
+	for _, c := range cases {
+		p, err := codeowners.NewPattern(c.pattern)
+		require.NoError(t, err, c.pattern)
+		require.Equal(t, c.match, p.Match(c.path), "%s ~ %s", c.pattern, c.path)
+	}
+
+	rules, err := codeowners.Parse(strings.NewReader("# comment\n\n/docs/ @u1 @docs # trailing\n\\#notes @u2\n/vendor/\n"))
+	require.NoError(t, err)
+	require.Len(t, rules, 3)
+	require.Equal(t, []string{"@u1", "@docs"}, rules[0].Owners)
+	require.Equal(t, "#notes", rules[1].Pattern)
+	require.Empty(t, rules[2].Owners)
+}
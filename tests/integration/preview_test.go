@@ -0,0 +1,113 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/service"
+	"github.com/mink0ff/pr_service/tests/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// initPreviewTest создаёт команду backend с неактивным u6.
+func initPreviewTest(t *testing.T) context.Context {
+	return utils.SeedBackendTeam(t, ts, append(utils.BackendMembers(5), dto.TeamMember{UserID: "u6", Username: "Frank"})...)
+}
+
+func findCandidate(t *testing.T, candidates []dto.ReviewerCandidate, userID string) dto.ReviewerCandidate {
+	t.Helper()
+	for _, c := range candidates {
+		if c.UserID == userID {
+			return c
+		}
+	}
+	t.Fatalf("candidate %s not found", userID)
+	return dto.ReviewerCandidate{}
+}
+
+func TestPreview_MatchesCreateWithoutWriting(t *testing.T) {
+	ctx := initPreviewTest(t)
+
+	seed := int64(42)
+	preview, err := ts.PRService.PreviewReviewers(ctx, &dto.PreviewReviewersRequest{
+		PullRequestID: "pr-1",
+		AuthorID:      "u1",
+		Seed:          &seed,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "backend", preview.TeamName)
+	require.Equal(t, seed, preview.Seed)
+	require.Equal(t, []string{"u3", "u5"}, preview.Reviewers)
+	require.Len(t, preview.Candidates, 6)
+
+	// Выбранные ревьюеры идут первыми, исключённые — в конце без ранга.
+	require.Equal(t, "u3", preview.Candidates[0].UserID)
+	require.Equal(t, 1, preview.Candidates[0].Rank)
+	require.True(t, preview.Candidates[0].Selected)
+	require.Equal(t, "u5", preview.Candidates[1].UserID)
+
+	author := findCandidate(t, preview.Candidates, "u1")
+	require.False(t, author.Eligible)
+	require.Zero(t, author.Rank)
+	require.Equal(t, []dto.CandidateReason{dto.CandidateAuthor}, author.Reasons)
+
+	inactive := findCandidate(t, preview.Candidates, "u6")
+	require.False(t, inactive.Eligible)
+	require.Equal(t, []dto.CandidateReason{dto.CandidateInactive}, inactive.Reasons)
+
+	other := findCandidate(t, preview.Candidates, "u2")
+	require.True(t, other.Eligible)
+	require.False(t, other.Selected)
+	require.Equal(t, []dto.CandidateReason{dto.CandidateTeamMember}, other.Reasons)
+
+	// Предпросмотр ничего не сохраняет.
+	var count int64
+	require.NoError(t, ts.DB.Table("pull_requests").Count(&count).Error)
+	require.Zero(t, count)
+	require.NoError(t, ts.DB.Table("reviewer_assignment_histories").Count(&count).Error)
+	require.Zero(t, count)
+
+	created, err := ts.PRService.CreatePR(ctx, &dto.CreatePRRequest{
+		PullRequestID:   "pr-1",
+		PullRequestName: "Preview me",
+		AuthorID:        "u1",
+		Seed:            &preview.Seed,
+	})
+	require.NoError(t, err)
+	require.Equal(t, preview.Reviewers, created.PR.AssignedReviewers)
+}
+
+func TestPreview_ExplainsOwnersAndSkills(t *testing.T) {
+	ctx := initPreviewTest(t)
+
+	_, err := ts.TeamService.AddOwnershipRule(ctx, &dto.AddOwnershipRuleRequest{
+		TeamName: "backend",
+		Pattern:  "*.sql",
+		Owners:   []string{"@u2"},
+	})
+	require.NoError(t, err)
+
+	_, err = ts.UserService.AddSkills(ctx, &dto.UserSkills{UserID: "u4", Skills: []string{"go"}})
+	require.NoError(t, err)
+
+	preview, err := ts.PRService.PreviewReviewers(ctx, &dto.PreviewReviewersRequest{
+		AuthorID:     "u1",
+		ChangedFiles: []string{"migrations/001.sql"},
+		Labels:       []string{"go"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"u2", "u4"}, preview.Reviewers)
+
+	owner := findCandidate(t, preview.Candidates, "u2")
+	require.Equal(t, []dto.CandidateReason{dto.CandidateCodeOwner}, owner.Reasons)
+	require.NotNil(t, owner.MatchedRule)
+	require.Equal(t, "*.sql", owner.MatchedRule.Pattern)
+
+	skilled := findCandidate(t, preview.Candidates, "u4")
+	require.Equal(t, []dto.CandidateReason{dto.CandidateSkillMatch}, skilled.Reasons)
+	require.Equal(t, []string{"go"}, skilled.MatchedSkills)
+
+	_, err = ts.PRService.PreviewReviewers(ctx, &dto.PreviewReviewersRequest{AuthorID: "ghost"})
+	require.ErrorIs(t, err, service.ErrUserNotFound)
+}
@@ -0,0 +1,112 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/service"
+	"github.com/mink0ff/pr_service/tests/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func setReviewLimit(t *testing.T, ctx context.Context, userID string, limit int) {
+	t.Helper()
+	resp, err := ts.UserService.SetReviewLimit(ctx, &dto.UserReviewLimit{UserID: userID, MaxOpenReviews: &limit})
+	require.NoError(t, err)
+	require.Equal(t, limit, *resp.MaxOpenReviews)
+}
+
+func createLimitPR(ctx context.Context, prID string) (*dto.CreatePRResponse, error) {
+	return ts.PRService.CreatePR(ctx, &dto.CreatePRRequest{
+		PullRequestID:   prID,
+		PullRequestName: "Capped " + prID,
+		AuthorID:        "u1",
+	})
+}
+
+func TestReviewLimit_CandidatesAtCapacityAreSkipped(t *testing.T) {
+	ctx := utils.SeedBackendTeam(t, ts, utils.BackendMembers(4)...)
+
+	setReviewLimit(t, ctx, "u2", 0)
+	setReviewLimit(t, ctx, "u3", 1)
+
+	resp, err := createLimitPR(ctx, "pr-1")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"u3", "u4"}, resp.PR.AssignedReviewers)
+	require.Empty(t, resp.AtCapacity)
+
+	limit, err := ts.UserService.GetReviewLimit(ctx, "u3")
+	require.NoError(t, err)
+	require.Equal(t, 1, limit.OpenReviews)
+
+	// u3 упёрся в лимит, поэтому назначается только u4.
+	resp, err = createLimitPR(ctx, "pr-2")
+	require.NoError(t, err)
+	require.Equal(t, []string{"u4"}, resp.PR.AssignedReviewers)
+	require.Equal(t, []string{"u2", "u3"}, resp.AtCapacity)
+
+	setReviewLimit(t, ctx, "u4", 2)
+
+	_, err = createLimitPR(ctx, "pr-3")
+	require.ErrorIs(t, err, service.ErrReviewersAtCapacity)
+
+	preview, err := ts.PRService.PreviewReviewers(ctx, &dto.PreviewReviewersRequest{AuthorID: "u1"})
+	require.NoError(t, err)
+	require.Empty(t, preview.Reviewers)
+	for _, id := range []string{"u2", "u3", "u4"} {
+		require.Equal(t, []dto.CandidateReason{dto.CandidateAtCapacity}, findCandidate(t, preview.Candidates, id).Reasons)
+	}
+
+	// Смёрженные PR не учитываются в лимите.
+	_, err = ts.PRService.MergePR(ctx, &dto.MergePRRequest{PullRequestID: "pr-1"})
+	require.NoError(t, err)
+
+	resp, err = createLimitPR(ctx, "pr-3")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"u3", "u4"}, resp.PR.AssignedReviewers)
+
+	_, err = ts.PRService.ReassignReviewer(ctx, &dto.ReassignReviewerRequest{PullRequestID: "pr-3", OldUserID: "u3"})
+	require.ErrorIs(t, err, service.ErrReviewersAtCapacity)
+}
+
+func TestReviewLimit_ShortAssignmentIsReported(t *testing.T) {
+	ctx := utils.SeedBackendTeam(t, ts, utils.BackendMembers(3)...)
+
+	// Из двух кандидатов u2 упёрся в лимит: PR создаётся с одним ревьюером,
+	// а ответ объясняет, почему второго нет.
+	setReviewLimit(t, ctx, "u2", 0)
+
+	resp, err := createLimitPR(ctx, "pr-1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"u3"}, resp.PR.AssignedReviewers)
+	require.Equal(t, []string{"u2"}, resp.AtCapacity)
+}
+
+func TestReviewLimit_SettingsSurviveUserUpdates(t *testing.T) {
+	ctx := utils.SeedBackendTeam(t, ts, utils.BackendMembers(4)...)
+
+	setReviewLimit(t, ctx, "u2", 3)
+
+	_, err := ts.UserService.SetActive(ctx, dto.SetUserActiveRequest{UserID: "u2", IsActive: false})
+	require.NoError(t, err)
+
+	_, err = ts.TeamService.CreateTeam(ctx, &dto.Team{
+		TeamName: "platform",
+		Members:  []dto.TeamMember{{UserID: "u2", Username: "Bob", IsActive: true}},
+	})
+	require.NoError(t, err)
+
+	limit, err := ts.UserService.GetReviewLimit(ctx, "u2")
+	require.NoError(t, err)
+	require.NotNil(t, limit.MaxOpenReviews)
+	require.Equal(t, 3, *limit.MaxOpenReviews)
+
+	tooMany := 1000
+	_, err = ts.UserService.SetReviewLimit(ctx, &dto.UserReviewLimit{UserID: "u2", MaxOpenReviews: &tooMany})
+	require.ErrorIs(t, err, service.ErrInvalidReviewLimit)
+
+	limit, err = ts.UserService.SetReviewLimit(ctx, &dto.UserReviewLimit{UserID: "u2"})
+	require.NoError(t, err)
+	require.Nil(t, limit.MaxOpenReviews)
+}
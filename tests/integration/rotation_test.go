@@ -0,0 +1,81 @@
+package integration
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/repository"
+	"github.com/mink0ff/pr_service/internal/service"
+	"github.com/mink0ff/pr_service/tests/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotation_Settings(t *testing.T) {
+	ctx := utils.SeedBackendTeam(t, ts)
+
+	window := 3
+	rotation, err := ts.TeamService.SetRotation(ctx, &dto.TeamRotation{TeamName: "backend", Window: &window})
+	require.NoError(t, err)
+	require.Equal(t, 3, *rotation.Window)
+
+	remind := 24
+	_, err = ts.TeamService.SetSLA(ctx, &dto.TeamSLA{TeamName: "backend", RemindAfterHours: &remind})
+	require.NoError(t, err)
+
+	rotation, err = ts.TeamService.GetRotation(ctx, "backend")
+	require.NoError(t, err)
+	require.Equal(t, 3, *rotation.Window)
+
+	tooLarge := 500
+	_, err = ts.TeamService.SetRotation(ctx, &dto.TeamRotation{TeamName: "backend", Window: &tooLarge})
+	require.ErrorIs(t, err, service.ErrInvalidRotation)
+
+	rotation, err = ts.TeamService.SetRotation(ctx, &dto.TeamRotation{TeamName: "backend"})
+	require.NoError(t, err)
+	require.Nil(t, rotation.Window)
+}
+
+func TestRotation_RecentReviewersAreReported(t *testing.T) {
+	ctx := utils.SeedBackendTeam(t, ts)
+	historyRepo := repository.NewReviewerHistoryRepo(ts.DB)
+
+	window := 2
+	_, err := ts.TeamService.SetRotation(ctx, &dto.TeamRotation{TeamName: "backend", Window: &window})
+	require.NoError(t, err)
+
+	for i := 0; i < 6; i++ {
+		prID := fmt.Sprintf("pr-%d", i)
+
+		expected, err := historyRepo.CountRecentReviewsOfAuthor(ctx, "u1", prID, window)
+		require.NoError(t, err)
+
+		resp, err := ts.PRService.CreatePR(ctx, &dto.CreatePRRequest{
+			PullRequestID:   prID,
+			PullRequestName: "Rotation",
+			AuthorID:        "u1",
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.PR.AssignedReviewers, 2)
+
+		for _, match := range resp.ReviewerMatches {
+			require.Equal(t, expected[match.ReviewerID], match.RecentReviews)
+			require.LessOrEqual(t, match.RecentReviews, window)
+		}
+	}
+
+	// Окно ограничивает историю последними PR автора.
+	counts, err := historyRepo.CountRecentReviewsOfAuthor(ctx, "u1", "", window)
+	require.NoError(t, err)
+
+	total := 0
+	for _, c := range counts {
+		total += c
+	}
+	require.Equal(t, 2*window, total)
+
+	// PR других авторов не учитываются.
+	counts, err = historyRepo.CountRecentReviewsOfAuthor(ctx, "u2", "", window)
+	require.NoError(t, err)
+	require.Empty(t, counts)
+}
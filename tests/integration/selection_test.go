@@ -0,0 +1,86 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/models"
+	"github.com/mink0ff/pr_service/internal/service"
+	"github.com/mink0ff/pr_service/tests/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelection_ExplicitSeedIsReproducible(t *testing.T) {
+	ctx := utils.SeedBackendTeam(t, ts)
+
+	seed := int64(42)
+	first, err := ts.PRService.CreatePR(ctx, &dto.CreatePRRequest{
+		PullRequestID:   "pr-1",
+		PullRequestName: "First",
+		AuthorID:        "u1",
+		Seed:            &seed,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"u3", "u5"}, first.PR.AssignedReviewers)
+	require.NotNil(t, first.PR.SelectionSeed)
+	require.Equal(t, seed, *first.PR.SelectionSeed)
+
+	// Тот же seed на тех же данных даёт тот же набор ревьюеров.
+	second, err := ts.PRService.CreatePR(ctx, &dto.CreatePRRequest{
+		PullRequestID:   "pr-2",
+		PullRequestName: "Second",
+		AuthorID:        "u1",
+		Seed:            &seed,
+	})
+	require.NoError(t, err)
+	require.Equal(t, first.PR.AssignedReviewers, second.PR.AssignedReviewers)
+}
+
+func TestSelection_HashSeedsDependOnPRID(t *testing.T) {
+	ctx := utils.SeedBackendTeam(t, ts)
+
+	resp, err := ts.PRService.CreatePR(ctx, &dto.CreatePRRequest{
+		PullRequestID:   "pr-det",
+		PullRequestName: "Deterministic",
+		AuthorID:        "u1",
+	})
+	require.NoError(t, err)
+	require.Equal(t, service.HashSeeds()("pr-det"), *resp.PR.SelectionSeed)
+	require.Equal(t, []string{"u3", "u4"}, resp.PR.AssignedReviewers)
+
+	reassigned, err := ts.PRService.ReassignReviewer(ctx, &dto.ReassignReviewerRequest{
+		PullRequestID: "pr-det",
+		OldUserID:     "u3",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "u5", reassigned.ReplacedBy)
+
+	// Seed каждой выборки сохраняется в истории назначений.
+	var events []models.ReviewerAssignmentHistory
+	err = ts.DB.Where("pr_id = ? AND event_type = ?", "pr-det", models.EventAssigned).Order("created_at").Find(&events).Error
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	seeds := map[string]int64{}
+	for _, e := range events {
+		require.NotNil(t, e.SelectionSeed)
+		seeds[e.UserID] = *e.SelectionSeed
+	}
+	require.Equal(t, service.HashSeeds()("pr-det"), seeds["u3"])
+	require.Equal(t, service.HashSeeds()("pr-det/u3"), seeds["u5"])
+}
+
+func TestSelection_SeedModes(t *testing.T) {
+	_, err := service.NewSeedFunc("bogus", 0)
+	require.Error(t, err)
+
+	fixed, err := service.NewSeedFunc(service.SelectionModeRandom, 7)
+	require.NoError(t, err)
+	again, err := service.NewSeedFunc(service.SelectionModeRandom, 7)
+	require.NoError(t, err)
+	require.Equal(t, fixed("a"), again("a"))
+
+	hash, err := service.NewSeedFunc(service.SelectionModePRHash, 0)
+	require.NoError(t, err)
+	require.Equal(t, hash("pr-1"), hash("pr-1"))
+	require.NotEqual(t, hash("pr-1"), hash("pr-2"))
+}
@@ -0,0 +1,86 @@
+package integration
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/service"
+	"github.com/mink0ff/pr_service/tests/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkills_CRUD(t *testing.T) {
+	ctx := utils.SeedBackendTeam(t, ts)
+
+	skills, err := ts.UserService.AddSkills(ctx, &dto.UserSkills{UserID: "u2", Skills: []string{"Go", " sql", "go"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"go", "sql"}, skills.Skills)
+
+	skills, err = ts.UserService.RemoveSkills(ctx, &dto.UserSkills{UserID: "u2", Skills: []string{"sql"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"go"}, skills.Skills)
+
+	_, err = ts.UserService.AddSkills(ctx, &dto.UserSkills{UserID: "u2", Skills: []string{"bad tag"}})
+	require.ErrorIs(t, err, service.ErrInvalidTag)
+
+	_, err = ts.UserService.GetSkills(ctx, "u999")
+	require.ErrorIs(t, err, service.ErrUserNotFound)
+
+	_, err = ts.PRService.CreatePR(ctx, &dto.CreatePRRequest{
+		PullRequestID:   "pr-1",
+		PullRequestName: "Labels",
+		AuthorID:        "u1",
+		Labels:          []string{"Frontend"},
+	})
+	require.NoError(t, err)
+
+	labels, err := ts.PRService.AddLabels(ctx, &dto.PRLabels{PullRequestID: "pr-1", Labels: []string{"go"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"frontend", "go"}, labels.Labels)
+
+	labels, err = ts.PRService.RemoveLabels(ctx, &dto.PRLabels{PullRequestID: "pr-1", Labels: []string{"frontend"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"go"}, labels.Labels)
+
+	_, err = ts.PRService.GetLabels(ctx, "pr-404")
+	require.ErrorIs(t, err, service.ErrPRNotFound)
+}
+
+func TestSkills_CreatePRPrefersMatchingSkills(t *testing.T) {
+	ctx := utils.SeedBackendTeam(t, ts)
+
+	_, err := ts.UserService.AddSkills(ctx, &dto.UserSkills{UserID: "u2", Skills: []string{"go", "sql"}})
+	require.NoError(t, err)
+	_, err = ts.UserService.AddSkills(ctx, &dto.UserSkills{UserID: "u3", Skills: []string{"go"}})
+	require.NoError(t, err)
+	_, err = ts.UserService.AddSkills(ctx, &dto.UserSkills{UserID: "u4", Skills: []string{"frontend"}})
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		resp, err := ts.PRService.CreatePR(ctx, &dto.CreatePRRequest{
+			PullRequestID:   fmt.Sprintf("pr-%d", i),
+			PullRequestName: "Query tuning",
+			AuthorID:        "u1",
+			Labels:          []string{"go", "sql"},
+		})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"u2", "u3"}, resp.PR.AssignedReviewers)
+		require.Equal(t, []string{"go", "sql"}, resp.PR.Labels)
+
+		for _, match := range resp.ReviewerMatches {
+			require.NotEmpty(t, match.MatchedSkills)
+		}
+	}
+
+	// Единственный подходящий кандидат выбирается всегда, второй — случайно.
+	resp, err := ts.PRService.CreatePR(ctx, &dto.CreatePRRequest{
+		PullRequestID:   "pr-ui",
+		PullRequestName: "New button",
+		AuthorID:        "u1",
+		Labels:          []string{"frontend"},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.PR.AssignedReviewers, 2)
+	require.Equal(t, "u4", resp.PR.AssignedReviewers[0])
+}
@@ -0,0 +1,188 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/service"
+	"github.com/mink0ff/pr_service/tests/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// initSoftDeleteTest создаёт команду backend из трёх человек и PR автора b1.
+func initSoftDeleteTest(t *testing.T) (context.Context, *dto.CreatePRResponse) {
+	utils.TruncateTables(ts.DB)
+	ctx := context.Background()
+
+	_, err := ts.TeamService.CreateTeam(ctx, &dto.Team{TeamName: "backend", Members: []dto.TeamMember{
+		{UserID: "b1", Username: "Alice", IsActive: true},
+		{UserID: "b2", Username: "Bob", IsActive: true},
+		{UserID: "b3", Username: "Carol", IsActive: true},
+	}})
+	require.NoError(t, err)
+
+	pr, err := ts.PRService.CreatePR(ctx, &dto.CreatePRRequest{PullRequestID: "pr-s1", PullRequestName: "API", AuthorID: "b1"})
+	require.NoError(t, err)
+	require.Len(t, pr.PR.AssignedReviewers, 2)
+	return ctx, pr
+}
+
+// backdateDeleted сдвигает время удаления всех удалённых записей в прошлое.
+func backdateDeleted(t *testing.T, age time.Duration) {
+	for _, table := range []string{"teams", "users", "pull_requests"} {
+		err := ts.DB.Exec("UPDATE "+table+" SET deleted_at = ? WHERE deleted_at IS NOT NULL", time.Now().Add(-age)).Error
+		require.NoError(t, err)
+	}
+}
+
+func TestSoftDelete_PullRequestRestore(t *testing.T) {
+	ctx, pr := initSoftDeleteTest(t)
+	reviewer := pr.PR.AssignedReviewers[0]
+
+	require.NoError(t, ts.PRService.DeletePR(ctx, &dto.DeletePRRequest{PullRequestID: "pr-s1"}))
+
+	// Удалённый PR пропадает из ревью и открытых ревью, история остаётся.
+	reviews, err := ts.UserService.GetReviewPRs(ctx, reviewer)
+	require.NoError(t, err)
+	require.Empty(t, reviews)
+
+	stats, err := ts.StatsService.GetReviewerStats(ctx)
+	require.NoError(t, err)
+	require.Len(t, stats.Items, 2)
+
+	teamStats, err := ts.StatsService.GetTeamStats(ctx, "backend")
+	require.NoError(t, err)
+	require.Equal(t, int64(0), teamStats.Teams[0].Own.OpenReviews)
+	require.Equal(t, int64(2), teamStats.Teams[0].Own.Assignments)
+
+	_, err = ts.PRService.MergePR(ctx, &dto.MergePRRequest{PullRequestID: "pr-s1"})
+	require.ErrorIs(t, err, service.ErrPRNotFound)
+	_, err = ts.PRService.CreatePR(ctx, &dto.CreatePRRequest{PullRequestID: "pr-s1", PullRequestName: "API", AuthorID: "b1"})
+	require.ErrorIs(t, err, service.ErrPRExists)
+
+	deleted, err := ts.Retention.ListDeleted(ctx, "pull_requests", 10)
+	require.NoError(t, err)
+	require.Len(t, deleted.Records, 1)
+	require.Equal(t, "API", deleted.Records[0].Name)
+
+	restored, err := ts.Retention.Restore(ctx, &dto.RestoreDeletedRequest{Entity: "pull_requests", ID: "pr-s1"})
+	require.NoError(t, err)
+	require.Equal(t, "pr-s1", restored.ID)
+
+	reviews, err = ts.UserService.GetReviewPRs(ctx, reviewer)
+	require.NoError(t, err)
+	require.Len(t, reviews, 1)
+
+	_, err = ts.Retention.Restore(ctx, &dto.RestoreDeletedRequest{Entity: "pull_requests", ID: "pr-s1"})
+	require.ErrorIs(t, err, service.ErrNotDeleted)
+	_, err = ts.Retention.ListDeleted(ctx, "jobs", 10)
+	require.ErrorIs(t, err, service.ErrUnknownDeleted)
+}
+
+func TestSoftDelete_TeamsAndUsers(t *testing.T) {
+	ctx, _ := initSoftDeleteTest(t)
+
+	// Автора PR можно удалить: его PR и история сохраняются.
+	require.NoError(t, ts.TeamService.RemoveMember(ctx, &dto.RemoveTeamMemberRequest{TeamName: "backend", UserID: "b1"}))
+	_, err := ts.UserService.GetUser(ctx, "b1")
+	require.ErrorIs(t, err, service.ErrUserNotFound)
+	_, err = ts.TeamService.AddMember(ctx, &dto.AddTeamMemberRequest{TeamName: "backend", UserID: "b1", Username: "Alice", IsActive: true})
+	require.ErrorIs(t, err, service.ErrUserExists)
+
+	// Восстановленный пользователь остаётся неактивным.
+	_, err = ts.Retention.Restore(ctx, &dto.RestoreDeletedRequest{Entity: "users", ID: "b1"})
+	require.NoError(t, err)
+	user, err := ts.UserService.GetUser(ctx, "b1")
+	require.NoError(t, err)
+	require.False(t, user.IsActive)
+	require.Contains(t, auditActions(t, "backend"), "MEMBER_RESTORED")
+
+	// Имя удалённой команды можно занять, тогда восстановить её нельзя.
+	_, err = ts.TeamService.CreateTeam(ctx, &dto.Team{TeamName: "ml", Members: []dto.TeamMember{}})
+	require.NoError(t, err)
+	require.NoError(t, ts.TeamService.DeleteTeam(ctx, &dto.DeleteTeamRequest{TeamName: "ml"}))
+	deleted, err := ts.Retention.ListDeleted(ctx, "teams", 10)
+	require.NoError(t, err)
+	require.Len(t, deleted.Records, 1)
+	oldID := deleted.Records[0].ID
+
+	_, err = ts.TeamService.CreateTeam(ctx, &dto.Team{TeamName: "ml", Members: []dto.TeamMember{}})
+	require.NoError(t, err)
+	_, err = ts.Retention.Restore(ctx, &dto.RestoreDeletedRequest{Entity: "teams", ID: oldID})
+	require.ErrorIs(t, err, service.ErrTeamExists)
+
+	_, err = ts.TeamService.RenameTeam(ctx, &dto.RenameTeamRequest{TeamName: "ml", NewTeamName: "ml-new"})
+	require.NoError(t, err)
+	_, err = ts.Retention.Restore(ctx, &dto.RestoreDeletedRequest{Entity: "teams", ID: oldID})
+	require.NoError(t, err)
+	_, err = ts.TeamService.GetTeam(ctx, "ml")
+	require.NoError(t, err)
+	require.Contains(t, auditActions(t, "ml"), "TEAM_RESTORED")
+
+	// Пользователя удалённой команды не восстановить, пока не вернётся команда.
+	for _, id := range []string{"b1", "b2", "b3"} {
+		require.NoError(t, ts.TeamService.RemoveMember(ctx, &dto.RemoveTeamMemberRequest{TeamName: "backend", UserID: id}))
+	}
+	require.NoError(t, ts.TeamService.DeleteTeam(ctx, &dto.DeleteTeamRequest{TeamName: "backend"}))
+	_, err = ts.Retention.Restore(ctx, &dto.RestoreDeletedRequest{Entity: "users", ID: "b2"})
+	require.ErrorIs(t, err, service.ErrParentDeleted)
+}
+
+func TestSoftDelete_RetentionPurge(t *testing.T) {
+	ctx, _ := initSoftDeleteTest(t)
+
+	_, err := ts.TeamService.CreateTeam(ctx, &dto.Team{TeamName: "core", Members: []dto.TeamMember{
+		{UserID: "c1", Username: "Dave", IsActive: true},
+		{UserID: "c2", Username: "Eve", IsActive: true},
+		{UserID: "c3", Username: "Frank", IsActive: true},
+	}})
+	require.NoError(t, err)
+	_, err = ts.PRService.CreatePR(ctx, &dto.CreatePRRequest{PullRequestID: "pr-live", PullRequestName: "Core", AuthorID: "c1"})
+	require.NoError(t, err)
+
+	// backend удаляется целиком, из core — только ревьюер живого PR.
+	require.NoError(t, ts.PRService.DeletePR(ctx, &dto.DeletePRRequest{PullRequestID: "pr-s1"}))
+	for _, id := range []string{"b1", "b2", "b3"} {
+		require.NoError(t, ts.TeamService.RemoveMember(ctx, &dto.RemoveTeamMemberRequest{TeamName: "backend", UserID: id}))
+	}
+	require.NoError(t, ts.TeamService.DeleteTeam(ctx, &dto.DeleteTeamRequest{TeamName: "backend"}))
+	require.NoError(t, ts.TeamService.RemoveMember(ctx, &dto.RemoveTeamMemberRequest{TeamName: "core", UserID: "c2"}))
+	backdateDeleted(t, 60*24*time.Hour)
+
+	// Недавно удалённая команда ещё не попадает под удаление.
+	_, err = ts.TeamService.CreateTeam(ctx, &dto.Team{TeamName: "recent", Members: []dto.TeamMember{}})
+	require.NoError(t, err)
+	require.NoError(t, ts.TeamService.DeleteTeam(ctx, &dto.DeleteTeamRequest{TeamName: "recent"}))
+
+	report, err := ts.Retention.PurgeExpired(ctx)
+	require.NoError(t, err)
+	require.Equal(t, &dto.RetentionReport{Mode: "archive", PullRequests: 1, Users: 3, Teams: 1}, report)
+
+	// c2 остаётся в истории живого PR, поэтому не удаляется.
+	users, err := ts.Retention.ListDeleted(ctx, "users", 10)
+	require.NoError(t, err)
+	require.Len(t, users.Records, 1)
+	require.Equal(t, "c2", users.Records[0].ID)
+
+	teams, err := ts.Retention.ListDeleted(ctx, "teams", 10)
+	require.NoError(t, err)
+	require.Len(t, teams.Records, 1)
+	require.Equal(t, "recent", teams.Records[0].Name)
+
+	var archived int64
+	require.NoError(t, ts.DB.Raw("SELECT COUNT(*) FROM archive.records").Scan(&archived).Error)
+	require.Equal(t, int64(5), archived)
+
+	var history int
+	err = ts.DB.Raw(`SELECT jsonb_array_length(data->'history') FROM archive.records
+		WHERE entity = 'pull_requests' AND record_id = 'pr-s1'`).Scan(&history).Error
+	require.NoError(t, err)
+	require.Equal(t, 2, history)
+
+	// Повторный запуск ничего не находит.
+	report, err = ts.Retention.PurgeExpired(ctx)
+	require.NoError(t, err)
+	require.Equal(t, &dto.RetentionReport{Mode: "archive"}, report)
+}
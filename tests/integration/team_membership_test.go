@@ -0,0 +1,141 @@
+package integration
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/service"
+	"github.com/mink0ff/pr_service/tests/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func auditActions(t *testing.T, teamName string) []string {
+	t.Helper()
+	log, err := ts.TeamService.GetAuditLog(context.Background(), teamName, 100)
+	require.NoError(t, err)
+	actions := make([]string, len(log.Events))
+	for i, e := range log.Events {
+		actions[i] = e.Action
+	}
+	return actions
+}
+
+func TestTeamMembership_MoveReassignsReviews(t *testing.T) {
+	utils.TruncateTables(ts.DB)
+	ctx := context.Background()
+
+	for _, team := range []dto.Team{
+		{TeamName: "web", Members: []dto.TeamMember{
+			{UserID: "w1", Username: "Alice", IsActive: true},
+			{UserID: "w2", Username: "Bob", IsActive: true},
+			{UserID: "w3", Username: "Carol", IsActive: true},
+			{UserID: "w4", Username: "Dave", IsActive: true},
+		}},
+		{TeamName: "infra", Members: []dto.TeamMember{{UserID: "i1", Username: "Eve", IsActive: true}}},
+	} {
+		_, err := ts.TeamService.CreateTeam(ctx, &team)
+		require.NoError(t, err)
+	}
+
+	pr, err := ts.PRService.CreatePR(ctx, &dto.CreatePRRequest{PullRequestID: "pr-m1", PullRequestName: "Cache", AuthorID: "w1"})
+	require.NoError(t, err)
+	require.Len(t, pr.PR.AssignedReviewers, 2)
+	moving := pr.PR.AssignedReviewers[0]
+
+	// Ревью PR из другой команды, назначенное вручную, переводом не
+	// затрагивается.
+	_, err = ts.PRService.CreatePR(ctx, &dto.CreatePRRequest{PullRequestID: "pr-m2", PullRequestName: "Infra", AuthorID: "i1"})
+	require.NoError(t, err)
+	_, err = ts.PRService.AddReviewer(ctx, &dto.AddReviewerRequest{PullRequestID: "pr-m2", UserID: moving})
+	require.NoError(t, err)
+
+	// Открытое ревью уходит участнику старой команды.
+	moved, err := ts.TeamService.MoveMember(ctx, &dto.MoveTeamMemberRequest{UserID: moving, TeamName: "infra"})
+	require.NoError(t, err)
+	require.Equal(t, "web", moved.FromTeam)
+	require.Equal(t, "infra", moved.User.TeamName)
+	require.Len(t, moved.Reassigned, 1)
+	require.Equal(t, "pr-m1", moved.Reassigned[0].PullRequestID)
+	require.Empty(t, moved.KeptReviews)
+	require.NotEqual(t, moving, moved.Reassigned[0].ReplacedBy)
+
+	reviews, err := ts.UserService.GetReviewPRs(ctx, moving)
+	require.NoError(t, err)
+	require.Len(t, reviews, 1)
+	require.Equal(t, "pr-m2", reviews[0].PullRequestID)
+
+	infra, err := ts.TeamService.GetTeam(ctx, "infra")
+	require.NoError(t, err)
+	require.Len(t, infra.Members, 2)
+
+	_, err = ts.TeamService.MoveMember(ctx, &dto.MoveTeamMemberRequest{UserID: moving, TeamName: "infra"})
+	require.ErrorIs(t, err, service.ErrAlreadyInTeam)
+
+	require.Subset(t, auditActions(t, "web"), []string{"TEAM_CREATED", "MEMBER_MOVED_OUT", "REVIEW_REASSIGNED"})
+	require.Contains(t, auditActions(t, "infra"), "MEMBER_MOVED_IN")
+}
+
+func TestTeamMembership_AddRemoveRenameDelete(t *testing.T) {
+	utils.TruncateTables(ts.DB)
+	ctx := context.Background()
+
+	_, err := ts.TeamService.CreateTeam(ctx, &dto.Team{TeamName: "data", Members: []dto.TeamMember{
+		{UserID: "d1", Username: "Alice", IsActive: true},
+		{UserID: "d2", Username: "Bob", IsActive: true},
+	}})
+	require.NoError(t, err)
+	_, err = ts.TeamService.CreateTeam(ctx, &dto.Team{TeamName: "ml", Members: []dto.TeamMember{}})
+	require.NoError(t, err)
+
+	// Добавление не переносит пользователя из другой команды.
+	_, err = ts.TeamService.AddMember(ctx, &dto.AddTeamMemberRequest{TeamName: "ml", UserID: "d1", Username: "Alice", IsActive: true})
+	require.ErrorIs(t, err, service.ErrUserInOtherTeam)
+
+	added, err := ts.TeamService.AddMember(ctx, &dto.AddTeamMemberRequest{TeamName: "data", UserID: "d3", Username: "Carol", IsActive: true})
+	require.NoError(t, err)
+	require.Equal(t, "data", added.TeamName)
+
+	// Удаление мягкое: PR автора и его ревьюеры остаются.
+	pr, err := ts.PRService.CreatePR(ctx, &dto.CreatePRRequest{PullRequestID: "pr-d1", PullRequestName: "ETL", AuthorID: "d1"})
+	require.NoError(t, err)
+	require.NoError(t, ts.TeamService.RemoveMember(ctx, &dto.RemoveTeamMemberRequest{TeamName: "data", UserID: "d1"}))
+	reviews, err := ts.UserService.GetReviewPRs(ctx, pr.PR.AssignedReviewers[0])
+	require.NoError(t, err)
+	require.Len(t, reviews, 1)
+
+	err = ts.TeamService.RemoveMember(ctx, &dto.RemoveTeamMemberRequest{TeamName: "ml", UserID: "d2"})
+	require.ErrorIs(t, err, service.ErrUserNotInTeam)
+
+	_, err = ts.TeamService.AddMember(ctx, &dto.AddTeamMemberRequest{TeamName: "data", UserID: "d4", Username: "Dave", IsActive: true})
+	require.NoError(t, err)
+	require.NoError(t, ts.TeamService.RemoveMember(ctx, &dto.RemoveTeamMemberRequest{TeamName: "data", UserID: "d4"}))
+
+	_, err = ts.UserService.GetUser(ctx, "d4")
+	require.ErrorIs(t, err, service.ErrUserNotFound)
+
+	// Переименование.
+	_, err = ts.TeamService.RenameTeam(ctx, &dto.RenameTeamRequest{TeamName: "data", NewTeamName: "ml"})
+	require.ErrorIs(t, err, service.ErrTeamExists)
+
+	renamed, err := ts.TeamService.RenameTeam(ctx, &dto.RenameTeamRequest{TeamName: "data", NewTeamName: "analytics"})
+	require.NoError(t, err)
+	require.Equal(t, "analytics", renamed.TeamName)
+	require.Len(t, renamed.Members, 2)
+
+	_, err = ts.TeamService.GetTeam(ctx, "data")
+	require.ErrorIs(t, err, service.ErrTeamNotFound)
+
+	actions := auditActions(t, "analytics")
+	require.Equal(t, "TEAM_RENAMED", actions[0])
+	require.Equal(t, 2, len(slices.DeleteFunc(actions, func(a string) bool { return a != "MEMBER_REMOVED" })))
+
+	// Удалить можно только пустую команду.
+	err = ts.TeamService.DeleteTeam(ctx, &dto.DeleteTeamRequest{TeamName: "analytics"})
+	require.ErrorIs(t, err, service.ErrTeamNotEmpty)
+
+	require.NoError(t, ts.TeamService.DeleteTeam(ctx, &dto.DeleteTeamRequest{TeamName: "ml"}))
+	_, err = ts.TeamService.GetTeam(ctx, "ml")
+	require.ErrorIs(t, err, service.ErrTeamNotFound)
+}
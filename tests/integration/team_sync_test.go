@@ -0,0 +1,99 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/tests/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeamSync_DiffAndApply(t *testing.T) {
+	utils.TruncateTables(ts.DB)
+	ctx := context.Background()
+
+	_, err := ts.TeamService.CreateTeam(ctx, &dto.Team{TeamName: "core", Members: []dto.TeamMember{
+		{UserID: "c1", Username: "Alice", IsActive: true},
+		{UserID: "c2", Username: "Bob", IsActive: true},
+		{UserID: "c3", Username: "Carol", IsActive: true},
+	}})
+	require.NoError(t, err)
+	_, err = ts.TeamService.CreateTeam(ctx, &dto.Team{TeamName: "ops", Members: []dto.TeamMember{
+		{UserID: "o1", Username: "Oscar", IsActive: true},
+	}})
+	require.NoError(t, err)
+
+	desired := dto.Team{TeamName: "core", Members: []dto.TeamMember{
+		{UserID: "c1", Username: "Alice", IsActive: true},
+		{UserID: "c2", Username: "Bobby", IsActive: true},
+		{UserID: "o1", Username: "Oscar", IsActive: true},
+		{UserID: "c4", Username: "Dave", IsActive: true},
+	}}
+
+	// dry_run только считает разницу.
+	diff, err := ts.TeamService.SyncTeam(ctx, &dto.TeamSyncRequest{Team: desired, DryRun: true})
+	require.NoError(t, err)
+	require.True(t, diff.DryRun)
+	require.False(t, diff.TeamCreated)
+	require.Equal(t, []dto.TeamMember{{UserID: "c4", Username: "Dave", IsActive: true}}, diff.Added)
+	require.Len(t, diff.Updated, 1)
+	require.Equal(t, "Bob", diff.Updated[0].Before.Username)
+	require.Equal(t, "Bobby", diff.Updated[0].After.Username)
+	require.Len(t, diff.Moved, 1)
+	require.Equal(t, "ops", diff.Moved[0].FromTeam)
+	require.Equal(t, []string{"c3"}, diff.Deactivated)
+	require.Equal(t, 1, diff.Unchanged)
+
+	team, err := ts.TeamService.GetTeam(ctx, "core")
+	require.NoError(t, err)
+	require.Len(t, team.Members, 3)
+
+	// Применение даёт ту же разницу.
+	applied, err := ts.TeamService.SyncTeam(ctx, &dto.TeamSyncRequest{Team: desired})
+	require.NoError(t, err)
+	applied.DryRun = true
+	require.Equal(t, diff, applied)
+
+	team, err = ts.TeamService.GetTeam(ctx, "core")
+	require.NoError(t, err)
+	require.Len(t, team.Members, 5)
+	for _, m := range team.Members {
+		require.Equal(t, m.UserID != "c3", m.IsActive, m.UserID)
+	}
+
+	ops, err := ts.TeamService.GetTeam(ctx, "ops")
+	require.NoError(t, err)
+	require.Empty(t, ops.Members)
+
+	// Повторный запуск ничего не меняет.
+	again, err := ts.TeamService.SyncTeam(ctx, &dto.TeamSyncRequest{Team: desired})
+	require.NoError(t, err)
+	require.Empty(t, again.Added)
+	require.Empty(t, again.Updated)
+	require.Empty(t, again.Moved)
+	require.Empty(t, again.Deactivated)
+	require.Equal(t, 5, again.Unchanged)
+
+	require.Contains(t, auditActions(t, "core"), "MEMBER_DEACTIVATED")
+}
+
+func TestTeamSync_CreatesTeam(t *testing.T) {
+	utils.TruncateTables(ts.DB)
+	router := newTestRouter()
+
+	status, _ := doRequest(t, router, http.MethodPut, "/team/sync", `{
+		"team_name": "fresh",
+		"members": [{"user_id": "f1", "username": "Fay", "is_active": true}]
+	}`)
+	require.Equal(t, http.StatusOK, status)
+
+	team, err := ts.TeamService.GetTeam(context.Background(), "fresh")
+	require.NoError(t, err)
+	require.Len(t, team.Members, 1)
+
+	status, resp := doRequest(t, router, http.MethodPut, "/team/sync", `{"team_name": "", "members": [], "dry_run": true}`)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Equal(t, []string{"team_name"}, fields(resp))
+}
@@ -0,0 +1,146 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/service"
+	"github.com/mink0ff/pr_service/tests/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// initTeamTreeTest создаёт департамент eng с двумя командами: backend
+// (один разработчик) и frontend.
+func initTeamTreeTest(t *testing.T) context.Context {
+	utils.TruncateTables(ts.DB)
+	ctx := context.Background()
+
+	for _, team := range []dto.Team{
+		{TeamName: "eng", Members: []dto.TeamMember{
+			{UserID: "e1", Username: "Alice", IsActive: true},
+			{UserID: "e2", Username: "Bob", IsActive: true},
+		}},
+		{TeamName: "backend", Members: []dto.TeamMember{
+			{UserID: "b1", Username: "Carol", IsActive: true},
+		}},
+		{TeamName: "frontend", Members: []dto.TeamMember{
+			{UserID: "f1", Username: "Dave", IsActive: true},
+			{UserID: "f2", Username: "Eve", IsActive: false},
+		}},
+	} {
+		_, err := ts.TeamService.CreateTeam(ctx, &team)
+		require.NoError(t, err)
+	}
+
+	for _, name := range []string{"backend", "frontend"} {
+		_, err := ts.TeamService.SetParent(ctx, &dto.SetTeamParentRequest{TeamName: name, ParentTeamName: "eng"})
+		require.NoError(t, err)
+	}
+	return ctx
+}
+
+func TestTeamTree_TreeAndCycles(t *testing.T) {
+	ctx := initTeamTreeTest(t)
+
+	tree, err := ts.TeamService.GetTree(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, tree.Teams, 1)
+	eng := tree.Teams[0]
+	require.Equal(t, "eng", eng.TeamName)
+	require.Equal(t, 2, eng.Members)
+	require.Len(t, eng.Children, 2)
+	require.Equal(t, "backend", eng.Children[0].TeamName)
+	require.Equal(t, "eng", eng.Children[0].ParentTeamName)
+	require.Equal(t, "frontend", eng.Children[1].TeamName)
+
+	// Команда не может стать потомком самой себя или своей подкоманды.
+	_, err = ts.TeamService.SetParent(ctx, &dto.SetTeamParentRequest{TeamName: "eng", ParentTeamName: "eng"})
+	require.ErrorIs(t, err, service.ErrTeamCycle)
+	_, err = ts.TeamService.SetParent(ctx, &dto.SetTeamParentRequest{TeamName: "eng", ParentTeamName: "backend"})
+	require.ErrorIs(t, err, service.ErrTeamCycle)
+
+	// Вложенность глубже одного уровня.
+	node, err := ts.TeamService.SetParent(ctx, &dto.SetTeamParentRequest{TeamName: "frontend", ParentTeamName: "backend"})
+	require.NoError(t, err)
+	require.Equal(t, "backend", node.ParentTeamName)
+	_, err = ts.TeamService.SetParent(ctx, &dto.SetTeamParentRequest{TeamName: "eng", ParentTeamName: "frontend"})
+	require.ErrorIs(t, err, service.ErrTeamCycle)
+
+	subtree, err := ts.TeamService.GetTree(ctx, "backend")
+	require.NoError(t, err)
+	require.Len(t, subtree.Teams, 1)
+	require.Equal(t, "eng", subtree.Teams[0].ParentTeamName)
+	require.Equal(t, "frontend", subtree.Teams[0].Children[0].TeamName)
+
+	// Команду с подкомандами удалить нельзя; отвязка делает её корневой.
+	err = ts.TeamService.RemoveMember(ctx, &dto.RemoveTeamMemberRequest{TeamName: "backend", UserID: "b1"})
+	require.NoError(t, err)
+	err = ts.TeamService.DeleteTeam(ctx, &dto.DeleteTeamRequest{TeamName: "backend"})
+	require.ErrorIs(t, err, service.ErrTeamHasChildren)
+
+	_, err = ts.TeamService.SetParent(ctx, &dto.SetTeamParentRequest{TeamName: "frontend"})
+	require.NoError(t, err)
+	tree, err = ts.TeamService.GetTree(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, tree.Teams, 2)
+
+	require.Contains(t, auditActions(t, "frontend"), "TEAM_PARENT_CHANGED")
+}
+
+func TestTeamTree_FallbackReviewersFromParent(t *testing.T) {
+	ctx := initTeamTreeTest(t)
+
+	// В backend нет никого, кроме автора: оба ревьюера берутся из eng.
+	resp, err := ts.PRService.CreatePR(ctx, &dto.CreatePRRequest{PullRequestID: "pr-t1", PullRequestName: "API", AuthorID: "b1"})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"e1", "e2"}, resp.PR.AssignedReviewers)
+	for _, match := range resp.ReviewerMatches {
+		require.Equal(t, "eng", match.FallbackTeam)
+	}
+
+	preview, err := ts.PRService.PreviewReviewers(ctx, &dto.PreviewReviewersRequest{AuthorID: "b1"})
+	require.NoError(t, err)
+	require.Contains(t, findCandidate(t, preview.Candidates, "e1").Reasons, dto.CandidateParentTeam)
+
+	// Замена тоже ищется в родительской команде, если в своей никого нет.
+	_, err = ts.TeamService.AddMember(ctx, &dto.AddTeamMemberRequest{TeamName: "backend", UserID: "b2", Username: "Frank", IsActive: true})
+	require.NoError(t, err)
+	resp, err = ts.PRService.CreatePR(ctx, &dto.CreatePRRequest{PullRequestID: "pr-t2", PullRequestName: "DB", AuthorID: "b1"})
+	require.NoError(t, err)
+	require.Contains(t, resp.PR.AssignedReviewers, "b2")
+	require.Len(t, resp.PR.AssignedReviewers, 2)
+
+	reassigned, err := ts.PRService.ReassignReviewer(ctx, &dto.ReassignReviewerRequest{PullRequestID: "pr-t2", OldUserID: "b2"})
+	require.NoError(t, err)
+	require.Contains(t, []string{"e1", "e2"}, reassigned.ReplacedBy)
+	require.NotContains(t, resp.PR.AssignedReviewers, reassigned.ReplacedBy)
+}
+
+func TestTeamTree_RollupStats(t *testing.T) {
+	ctx := initTeamTreeTest(t)
+
+	_, err := ts.PRService.CreatePR(ctx, &dto.CreatePRRequest{PullRequestID: "pr-t2", PullRequestName: "UI", AuthorID: "e1"})
+	require.NoError(t, err)
+
+	stats, err := ts.StatsService.GetTeamStats(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, stats.Teams, 1)
+	eng := stats.Teams[0]
+	require.Equal(t, int64(2), eng.Own.Members)
+	require.Equal(t, int64(5), eng.Total.Members)
+	require.Equal(t, int64(4), eng.Total.ActiveMembers)
+	// Автор из eng, ревьюер из его же команды.
+	require.Equal(t, int64(1), eng.Own.OpenReviews)
+	require.Equal(t, int64(1), eng.Total.OpenReviews)
+	require.Equal(t, int64(1), eng.Total.Assignments)
+	require.Len(t, eng.Children, 2)
+
+	frontend, err := ts.StatsService.GetTeamStats(ctx, "frontend")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), frontend.Teams[0].Total.Members)
+	require.Equal(t, int64(1), frontend.Teams[0].Total.ActiveMembers)
+
+	_, err = ts.StatsService.GetTeamStats(ctx, "unknown")
+	require.ErrorIs(t, err, service.ErrTeamNotFound)
+}
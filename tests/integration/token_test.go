@@ -0,0 +1,66 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mink0ff/pr_service/internal/handler"
+	"github.com/mink0ff/pr_service/internal/health"
+	"github.com/mink0ff/pr_service/internal/service"
+	"github.com/mink0ff/pr_service/tests/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokens_CreateAuthenticateRevoke(t *testing.T) {
+	utils.TruncateTables(ts.DB)
+	ctx := context.Background()
+
+	token, err := ts.Tokens.CreateToken(ctx, "ci", 0)
+	require.NoError(t, err)
+	require.Nil(t, token.ExpiresAt)
+	require.NoError(t, ts.Tokens.Authenticate(ctx, token.Token))
+
+	_, err = ts.Tokens.CreateToken(ctx, "ci", 0)
+	require.ErrorIs(t, err, service.ErrTokenExists)
+
+	// В базе хранится только хэш, поэтому подделанный токен не подходит.
+	require.ErrorIs(t, ts.Tokens.Authenticate(ctx, token.Token+"x"), service.ErrUnauthorized)
+	require.ErrorIs(t, ts.Tokens.Authenticate(ctx, ""), service.ErrUnauthorized)
+
+	expiring, err := ts.Tokens.CreateToken(ctx, "short", time.Millisecond)
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+	require.ErrorIs(t, ts.Tokens.Authenticate(ctx, expiring.Token), service.ErrUnauthorized)
+
+	require.NoError(t, ts.Tokens.RevokeToken(ctx, "ci"))
+	require.ErrorIs(t, ts.Tokens.Authenticate(ctx, token.Token), service.ErrUnauthorized)
+	require.ErrorIs(t, ts.Tokens.RevokeToken(ctx, "ci"), service.ErrTokenNotFound)
+}
+
+func TestTokens_AdminRoutesRequireToken(t *testing.T) {
+	utils.TruncateTables(ts.DB)
+	router := newTestRouter()
+
+	// Без токена и с неверным токеном админские маршруты недоступны.
+	status, resp := doRequest(t, router, http.MethodGet, "/admin/export", "")
+	require.Equal(t, http.StatusUnauthorized, status)
+	require.Equal(t, "UNAUTHORIZED", resp.Code)
+	status, _ = doAdminRequest(t, router, "bogus", http.MethodPost, "/admin/restore", "")
+	require.Equal(t, http.StatusUnauthorized, status)
+
+	token, err := ts.Tokens.CreateToken(context.Background(), "admin", 0)
+	require.NoError(t, err)
+	status, _ = doAdminRequest(t, router, token.Token, http.MethodGet, "/admin/deleted?entity=teams", "")
+	require.Equal(t, http.StatusOK, status)
+
+	// Без проверки токенов админские маршруты не монтируются вовсе.
+	open := chi.NewRouter()
+	handler.RegisterRoutes(open, ts.TeamService, ts.UserService, ts.PRService, ts.StatsService, nil, ts.Import, ts.Export, ts.Retention, nil, health.NewChecker(time.Second, 0))
+	for _, target := range []string{"/admin/export", "/admin/restore", "/admin/deleted/restore", "/admin/pullRequest/create"} {
+		status, _ = doRequest(t, open, http.MethodPost, target, "")
+		require.Equal(t, http.StatusNotFound, status, target)
+	}
+}
@@ -2,9 +2,11 @@ package integration
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
 	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/service"
 	"github.com/mink0ff/pr_service/tests/utils"
 	"github.com/stretchr/testify/require"
 )
@@ -84,3 +86,91 @@ func TestUserService_GetReviewPRs(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, reviewPRs, 0)
 }
+
+func TestUserService_CreateGetAndList(t *testing.T) {
+	initUserServiceTest(t)
+	ctx := context.Background()
+
+	for _, team := range []dto.Team{
+		{TeamName: "web", Members: []dto.TeamMember{{UserID: "w1", Username: "Alice", IsActive: true}, {UserID: "w2", Username: "Bob", IsActive: true}}},
+		{TeamName: "infra", Members: []dto.TeamMember{{UserID: "i1", Username: "Carol", IsActive: false}}},
+	} {
+		_, err := ts.TeamService.CreateTeam(ctx, &team)
+		require.NoError(t, err)
+	}
+
+	// Команда указывается по имени.
+	created, err := ts.UserService.CreateUser(ctx, &dto.CreateUserRequest{UserID: "w3", Name: "Dave", TeamName: "web", IsActive: true})
+	require.NoError(t, err)
+	require.Equal(t, "web", created.TeamName)
+
+	_, err = ts.UserService.CreateUser(ctx, &dto.CreateUserRequest{UserID: "w3", Name: "Dave", TeamName: "web", IsActive: true})
+	require.ErrorIs(t, err, service.ErrUserExists)
+
+	_, err = ts.UserService.CreateUser(ctx, &dto.CreateUserRequest{UserID: "x1", Name: "Eve", TeamName: "missing", IsActive: true})
+	require.ErrorIs(t, err, service.ErrTeamNotFound)
+
+	_, err = ts.PRService.CreatePR(ctx, &dto.CreatePRRequest{PullRequestID: "pr-u1", PullRequestName: "Search", AuthorID: "w1"})
+	require.NoError(t, err)
+
+	// Автор видит свой PR, а ревьюер — открытое ревью.
+	author, err := ts.UserService.GetUser(ctx, "w1")
+	require.NoError(t, err)
+	require.Equal(t, "web", author.TeamName)
+	require.Len(t, author.AuthoredPRs, 1)
+	require.Equal(t, "pr-u1", author.AuthoredPRs[0].PullRequestID)
+	require.Zero(t, author.OpenReviews)
+
+	reviewers := 0
+	for _, id := range []string{"w2", "w3"} {
+		u, err := ts.UserService.GetUser(ctx, id)
+		require.NoError(t, err)
+		require.Empty(t, u.AuthoredPRs)
+		reviewers += u.OpenReviews
+	}
+	require.Equal(t, 2, reviewers)
+
+	_, err = ts.UserService.GetUser(ctx, "nobody")
+	require.ErrorIs(t, err, service.ErrUserNotFound)
+
+	// Фильтры и пагинация.
+	all, err := ts.UserService.ListUsers(ctx, &dto.ListUsersRequest{Limit: 2})
+	require.NoError(t, err)
+	require.EqualValues(t, 4, all.Total)
+	require.Len(t, all.Users, 2)
+	require.Equal(t, "i1", all.Users[0].UserID)
+	require.Equal(t, "infra", all.Users[0].TeamName)
+
+	next, err := ts.UserService.ListUsers(ctx, &dto.ListUsersRequest{Limit: 2, Offset: 2})
+	require.NoError(t, err)
+	require.Len(t, next.Users, 2)
+	require.Equal(t, "w2", next.Users[0].UserID)
+
+	active := true
+	web, err := ts.UserService.ListUsers(ctx, &dto.ListUsersRequest{TeamName: "web", IsActive: &active, Limit: 10})
+	require.NoError(t, err)
+	require.EqualValues(t, 3, web.Total)
+
+	inactive := false
+	idle, err := ts.UserService.ListUsers(ctx, &dto.ListUsersRequest{IsActive: &inactive, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, idle.Users, 1)
+	require.Equal(t, "i1", idle.Users[0].UserID)
+
+	_, err = ts.UserService.ListUsers(ctx, &dto.ListUsersRequest{TeamName: "missing", Limit: 10})
+	require.ErrorIs(t, err, service.ErrTeamNotFound)
+}
+
+func TestUserHandler_ListValidation(t *testing.T) {
+	initUserServiceTest(t)
+	router := newTestRouter()
+
+	status, resp := doRequest(t, router, http.MethodGet, "/users/list?limit=0&offset=-1&is_active=maybe", "")
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Equal(t, "VALIDATION_FAILED", resp.Code)
+	require.ElementsMatch(t, []string{"is_active", "limit", "offset"}, fields(resp))
+
+	status, resp = doRequest(t, router, http.MethodPost, "/users/add", `{"user_id": "n1", "username": "Neo", "team_name": "missing", "is_active": true}`)
+	require.Equal(t, http.StatusNotFound, status)
+	require.Equal(t, "TEAM_NOT_FOUND", resp.Code)
+}
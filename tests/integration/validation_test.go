@@ -0,0 +1,115 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/mink0ff/pr_service/internal/handler"
+	"github.com/mink0ff/pr_service/internal/health"
+	"github.com/mink0ff/pr_service/tests/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRouter() http.Handler {
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r, ts.TeamService, ts.UserService, ts.PRService, ts.StatsService, nil, ts.Import, ts.Export, ts.Retention, handler.RequireToken(ts.Tokens), health.NewChecker(time.Second, 0))
+	return r
+}
+
+type errorBody struct {
+	Code          string           `json:"code"`
+	Message       string           `json:"message"`
+	Details       []dto.FieldError `json:"details"`
+	CorrelationID string           `json:"correlation_id"`
+}
+
+func doRequest(t *testing.T, router http.Handler, method, target, body string) (int, errorBody) {
+	t.Helper()
+	return doAdminRequest(t, router, "", method, target, body)
+}
+
+// doAdminRequest отправляет запрос с токеном в заголовке Authorization,
+// пустой token заголовок не добавляет.
+func doAdminRequest(t *testing.T, router http.Handler, token, method, target, body string) (int, errorBody) {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	var resp errorBody
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	return rec.Code, resp
+}
+
+func fields(resp errorBody) []string {
+	out := make([]string, len(resp.Details))
+	for i, d := range resp.Details {
+		out[i] = d.Field
+	}
+	return out
+}
+
+func TestValidation_RequestBodies(t *testing.T) {
+	utils.TruncateTables(ts.DB)
+	router := newTestRouter()
+
+	// Пустое имя команды и повторяющийся участник — все ошибки в одном ответе.
+	status, resp := doRequest(t, router, http.MethodPost, "/team/add", `{
+		"team_name": " ",
+		"members": [
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+			{"user_id": "u1", "username": "", "is_active": true}
+		]
+	}`)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Equal(t, "VALIDATION_FAILED", resp.Code)
+	require.ElementsMatch(t, []string{"team_name", "members[1].username", "members[1].user_id"}, fields(resp))
+
+	status, resp = doRequest(t, router, http.MethodPost, "/pullRequest/create", `{"pull_request_id": "", "pull_request_name": "x", "author_id": "u 1"}`)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.ElementsMatch(t, []string{"pull_request_id", "author_id"}, fields(resp))
+
+	// seed при создании принимается только на админском пути.
+	status, resp = doRequest(t, router, http.MethodPost, "/pullRequest/create", `{"pull_request_id": "pr-1", "pull_request_name": "x", "author_id": "u1", "seed": 42}`)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Equal(t, []string{"seed"}, fields(resp))
+	token, err := ts.Tokens.CreateToken(context.Background(), "validation", 0)
+	require.NoError(t, err)
+	status, resp = doAdminRequest(t, router, token.Token, http.MethodPost, "/admin/pullRequest/create", `{"pull_request_id": "pr-1", "pull_request_name": "x", "author_id": "u1"}`)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Equal(t, []string{"seed"}, fields(resp))
+
+	// Неизвестные поля отклоняются, чтобы опечатки не терялись молча.
+	status, resp = doRequest(t, router, http.MethodPost, "/pullRequest/merge", `{"pull_request_id": "pr-1", "force": true}`)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Equal(t, "VALIDATION_FAILED", resp.Code)
+	require.Equal(t, []string{"force"}, fields(resp))
+
+	status, resp = doRequest(t, router, http.MethodPost, "/pullRequest/merge", `{"pull_request_id": 1}`)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Equal(t, []string{"pull_request_id"}, fields(resp))
+
+	status, resp = doRequest(t, router, http.MethodPost, "/pullRequest/merge", `{"pull_request_id":`)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Equal(t, "INVALID_JSON", resp.Code)
+
+	status, resp = doRequest(t, router, http.MethodGet, "/users/getReview", "")
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Equal(t, []string{"user_id"}, fields(resp))
+
+	// Корректный запрос проходит валидацию и доходит до сервиса.
+	status, resp = doRequest(t, router, http.MethodPost, "/pullRequest/merge", `{"pull_request_id": "pr-404"}`)
+	require.Equal(t, http.StatusNotFound, status)
+	require.Equal(t, "PR_NOT_FOUND", resp.Code)
+}
@@ -11,13 +11,16 @@ import (
 
 // InitTestDB подключается к тестовой базе и прогоняет миграции
 func InitTestDB() *gorm.DB {
-	cfg := config.LoadDBConfig("../../.env.test")
+	cfg, err := config.Load("", "../../.env.test")
+	if err != nil {
+		log.Fatalf("failed to load test config: %v", err)
+	}
 
 	db, err := gormdb.NewGormDB(&gormdb.GormConfig{
-		DSN:             cfg.DSN,
-		MaxOpenConns:    cfg.MaxOpenConns,
-		MaxIdleConns:    cfg.MaxIdleConns,
-		ConnMaxLifetime: cfg.ConnMaxLife,
+		DSN:             cfg.DB.DSN,
+		MaxOpenConns:    cfg.DB.MaxOpenConns,
+		MaxIdleConns:    cfg.DB.MaxIdleConns,
+		ConnMaxLifetime: cfg.DB.ConnMaxLife,
 	})
 	if err != nil {
 		log.Fatalf("failed to connect to test DB: %v", err)
@@ -32,7 +35,7 @@ func InitTestDB() *gorm.DB {
 
 // TruncateTables очищает все тестовые таблицы
 func TruncateTables(db *gorm.DB) {
-	tables := []string{"users", "teams", "pull_requests", "pr_reviewers", "reviewer_assignment_histories"}
+	tables := []string{"users", "teams", "team_memberships", "pull_requests", "pr_reviewers", "reviewer_assignment_histories", "user_notification_settings", "team_settings", "jobs", "job_runs", "ownership_rules", "user_skills", "pr_labels", "api_tokens", "team_audit_events", "archive.records"}
 
 	for _, table := range tables {
 		if err := db.Exec("TRUNCATE TABLE" + " " + table + " RESTART IDENTITY CASCADE").Error; err != nil {
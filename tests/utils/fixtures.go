@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mink0ff/pr_service/internal/dto"
+	"github.com/stretchr/testify/require"
+)
+
+var backendMembers = []dto.TeamMember{
+	{UserID: "u1", Username: "Alice", IsActive: true},
+	{UserID: "u2", Username: "Bob", IsActive: true},
+	{UserID: "u3", Username: "Charlie", IsActive: true},
+	{UserID: "u4", Username: "Dave", IsActive: true},
+	{UserID: "u5", Username: "Eve", IsActive: true},
+}
+
+// BackendMembers возвращает первых n участников стандартной команды backend:
+// активных u1–u5 (Alice, Bob, Charlie, Dave, Eve).
+func BackendMembers(n int) []dto.TeamMember {
+	return append([]dto.TeamMember(nil), backendMembers[:n]...)
+}
+
+// SeedBackendTeam очищает таблицы и создаёт команду backend из members,
+// а без них — из всех пяти BackendMembers.
+func SeedBackendTeam(t *testing.T, ts *TestServices, members ...dto.TeamMember) context.Context {
+	t.Helper()
+	TruncateTables(ts.DB)
+	ctx := context.Background()
+
+	if len(members) == 0 {
+		members = BackendMembers(len(backendMembers))
+	}
+
+	_, err := ts.TeamService.CreateTeam(ctx, &dto.Team{TeamName: "backend", Members: members})
+	require.NoError(t, err)
+
+	return ctx
+}
@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"time"
+
+	"github.com/mink0ff/pr_service/internal/notifier"
 	"github.com/mink0ff/pr_service/internal/repository"
 	"github.com/mink0ff/pr_service/internal/repository/transaction"
 	"github.com/mink0ff/pr_service/internal/service"
@@ -12,6 +15,11 @@ type TestServices struct {
 	TeamService  service.TeamService
 	PRService    service.PRService
 	StatsService service.StatsService
+	Escalation   service.EscalationService
+	Import       service.ImportService
+	Export       service.ExportService
+	Tokens       service.TokenService
+	Retention    service.RetentionService
 	DB           *gorm.DB
 	Teardown     func()
 }
@@ -23,19 +31,33 @@ func InitTestServices() *TestServices {
 	teamRepo := repository.NewTeamRepo(db)
 	prRepo := repository.NewPrRepo(db)
 	historyRepo := repository.NewReviewerHistoryRepo(db)
+	settingsRepo := repository.NewNotificationSettingsRepo(db)
+	teamSettingsRepo := repository.NewTeamSettingsRepo(db)
+	ownershipRepo := repository.NewOwnershipRuleRepo(db)
+	tagRepo := repository.NewTagRepo(db)
+	auditRepo := repository.NewTeamAuditRepo(db)
 
 	txManager := transaction.NewTransactionManager(db)
 
-	userSvc := service.NewUserService(userRepo, teamRepo)
-	teamSvc := service.NewTeamService(teamRepo, userRepo, prRepo, txManager)
-	prSvc := service.NewPRService(prRepo, userRepo, teamRepo, historyRepo, txManager)
-	statsSvc := service.NewStatsService(historyRepo)
+	userSvc := service.NewUserService(userRepo, teamRepo, settingsRepo, tagRepo)
+	prSvc := service.NewPRService(prRepo, userRepo, teamRepo, historyRepo, ownershipRepo, tagRepo, teamSettingsRepo, txManager, notifier.NewNoopNotifier(), service.HashSeeds())
+	teamSvc := service.NewTeamService(teamRepo, userRepo, prRepo, teamSettingsRepo, ownershipRepo, auditRepo, prSvc, txManager)
+	statsSvc := service.NewStatsService(historyRepo, teamRepo)
+	escalationSvc := service.NewEscalationService(prRepo, userRepo, teamRepo, historyRepo, prSvc, notifier.NewNoopNotifier())
+	importSvc := service.NewImportService(prRepo, userRepo, historyRepo, txManager)
+	exportSvc := service.NewExportService(repository.NewSnapshotRepo(db), txManager)
+	retentionSvc := service.NewRetentionService(repository.NewRetentionRepo(db), teamRepo, userRepo, auditRepo, txManager, 30*24*time.Hour, service.RetentionArchive)
 
 	return &TestServices{
 		UserService:  userSvc,
 		TeamService:  teamSvc,
 		PRService:    prSvc,
 		StatsService: statsSvc,
+		Escalation:   escalationSvc,
+		Import:       importSvc,
+		Export:       exportSvc,
+		Tokens:       service.NewTokenService(repository.NewTokenRepo(db)),
+		Retention:    retentionSvc,
 		DB:           db,
 		Teardown: func() {
 			TruncateTables(db)
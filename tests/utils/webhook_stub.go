@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// WebhookStub — локальный HTTP-сервер, имитирующий входящий вебхук Slack
+type WebhookStub struct {
+	Server *httptest.Server
+
+	mu       sync.Mutex
+	messages []string
+}
+
+func NewWebhookStub() *WebhookStub {
+	stub := &WebhookStub{}
+	stub.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Text string `json:"text"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, "invalid payload", http.StatusBadRequest)
+			return
+		}
+
+		stub.mu.Lock()
+		stub.messages = append(stub.messages, payload.Text)
+		stub.mu.Unlock()
+
+		_, _ = w.Write([]byte("ok"))
+	}))
+	return stub
+}
+
+func (s *WebhookStub) URL() string {
+	return s.Server.URL
+}
+
+func (s *WebhookStub) Messages() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.messages...)
+}
+
+func (s *WebhookStub) Close() {
+	s.Server.Close()
+}